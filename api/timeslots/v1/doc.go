@@ -0,0 +1,5 @@
+// Package timeslotsv1 holds the messages and the gRPC client and server
+// stubs generated from timeslots.proto.
+package timeslotsv1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative timeslots/v1/timeslots.proto
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"main.go/internal/entity"
+	apperrors "main.go/internal/errors"
+)
+
+const usage = `usage: admin [-json] <command> [arguments]
+
+commands:
+  users create -name NAME -username USERNAME -color RRGGBB   password is read from stdin
+  users list
+  users disable USER_ID
+  users enable USER_ID
+  users delete -yes USER_ID                                  also deletes the user's lists and items
+  users reset-password USER_ID                               password is read from stdin
+  users set-color USER_ID RRGGBB
+  lists transfer LIST_ID USER_ID
+  stats`
+
+var errUsage = errors.New(usage)
+
+type AuthService interface {
+	CreateUser(ctx context.Context, user entity.User) (int, error)
+}
+
+type UsersService interface {
+	GetAll(ctx context.Context) ([]entity.UserInfo, error)
+	SetDisabled(ctx context.Context, userID int, disabled bool) error
+	Delete(ctx context.Context, userID int) error
+	ResetPassword(ctx context.Context, userID int, password string) error
+	SetColor(ctx context.Context, userID int, color string) error
+	TransferList(ctx context.Context, listID, userID int) error
+	StudioStats(ctx context.Context) (entity.StudioStats, error)
+}
+
+// CLI runs one admin command and prints its result for people or, with
+// -json, for scripts.
+type CLI struct {
+	auth   AuthService
+	users  UsersService
+	stdin  io.Reader
+	stdout io.Writer
+	json   bool
+}
+
+type result struct {
+	Status string `json:"status"`
+	ID     int    `json:"id,omitempty"`
+}
+
+func (c *CLI) Run(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("admin", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.BoolVar(&c.json, "json", false, "print JSON")
+
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	args = flags.Args()
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch {
+	case args[0] == "stats":
+		return c.stats(ctx)
+	case args[0] == "lists" && len(args) == 4 && args[1] == "transfer":
+		return c.transferList(ctx, args[2], args[3])
+	case args[0] == "users" && len(args) > 1:
+		return c.runUsers(ctx, args[1], args[2:])
+	default:
+		return errUsage
+	}
+}
+
+func (c *CLI) runUsers(ctx context.Context, command string, args []string) error {
+	switch command {
+	case "create":
+		return c.createUser(ctx, args)
+	case "list":
+		return c.listUsers(ctx)
+	case "delete":
+		return c.deleteUser(ctx, args)
+	}
+
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	userID, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case command == "disable" && len(args) == 1:
+		err = c.users.SetDisabled(ctx, userID, true)
+	case command == "enable" && len(args) == 1:
+		err = c.users.SetDisabled(ctx, userID, false)
+	case command == "reset-password" && len(args) == 1:
+		var password string
+		if password, err = c.readPassword(); err == nil {
+			err = c.users.ResetPassword(ctx, userID, password)
+		}
+	case command == "set-color" && len(args) == 2:
+		err = c.users.SetColor(ctx, userID, args[1])
+	default:
+		return errUsage
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return c.done(fmt.Sprintf("user %d updated", userID), userID)
+}
+
+func (c *CLI) createUser(ctx context.Context, args []string) error {
+	var user entity.User
+
+	flags := flag.NewFlagSet("users create", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.StringVar(&user.Name, "name", "", "display name")
+	flags.StringVar(&user.Username, "username", "", "sign-in name")
+	flags.StringVar(&user.Color, "color", "", "calendar color, six hex digits")
+
+	if err := flags.Parse(args); err != nil || flags.NArg() != 0 {
+		return errUsage
+	}
+
+	password, err := c.readPassword()
+	if err != nil {
+		return err
+	}
+
+	user.Password = password
+
+	userID, err := c.auth.CreateUser(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	return c.done(fmt.Sprintf("user %d created", userID), userID)
+}
+
+func (c *CLI) listUsers(ctx context.Context) error {
+	users, err := c.users.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	if c.json {
+		if users == nil {
+			users = []entity.UserInfo{}
+		}
+
+		return c.printJSON(users)
+	}
+
+	table := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0) //nolint:gomnd // column padding
+	fmt.Fprintln(table, "ID\tUSERNAME\tNAME\tCOLOR\tLISTS\tSTATUS")
+
+	for _, user := range users {
+		status := "active"
+		if user.Disabled {
+			status = "disabled"
+		}
+
+		fmt.Fprintf(table, "%d\t%s\t%s\t%s\t%d\t%s\n", user.ID, user.Username, user.Name, user.Color, user.Lists, status)
+	}
+
+	return table.Flush()
+}
+
+func (c *CLI) deleteUser(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("users delete", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	confirmed := flags.Bool("yes", false, "confirm the deletion")
+
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errUsage
+	}
+
+	if !*confirmed {
+		return errors.New("deleting a user also deletes their lists and items, pass -yes to confirm")
+	}
+
+	userID, err := parseID(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if err = c.users.Delete(ctx, userID); err != nil {
+		return err
+	}
+
+	return c.done(fmt.Sprintf("user %d deleted", userID), userID)
+}
+
+func (c *CLI) transferList(ctx context.Context, rawListID, rawUserID string) error {
+	listID, err := parseID(rawListID)
+	if err != nil {
+		return err
+	}
+
+	userID, err := parseID(rawUserID)
+	if err != nil {
+		return err
+	}
+
+	if err = c.users.TransferList(ctx, listID, userID); err != nil {
+		return err
+	}
+
+	return c.done(fmt.Sprintf("list %d transferred to user %d", listID, userID), listID)
+}
+
+func (c *CLI) stats(ctx context.Context) error {
+	stats, err := c.users.StudioStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	if c.json {
+		return c.printJSON(stats)
+	}
+
+	table := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0) //nolint:gomnd // column padding
+	fmt.Fprintf(table, "users\t%d (%d disabled)\n", stats.Users, stats.DisabledUsers)
+	fmt.Fprintf(table, "lists\t%d\n", stats.Lists)
+	fmt.Fprintf(table, "items\t%d (%d upcoming)\n", stats.Items, stats.UpcomingItems)
+	fmt.Fprintf(table, "pending bookings\t%d\n", stats.PendingBookings)
+	fmt.Fprintf(table, "waiting clients\t%d (%d offers pending)\n", stats.WaitingClients, stats.PendingOffers)
+
+	return table.Flush()
+}
+
+// readPassword takes the password from the first line of stdin, so it stays
+// out of the shell history.
+func (c *CLI) readPassword() (string, error) {
+	line, err := bufio.NewReader(c.stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (c *CLI) done(message string, id int) error {
+	if c.json {
+		return c.printJSON(result{Status: "ok", ID: id})
+	}
+
+	_, err := fmt.Fprintln(c.stdout, message)
+
+	return err
+}
+
+func (c *CLI) printJSON(value any) error {
+	encoder := json.NewEncoder(c.stdout)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(value)
+}
+
+// printError prints err with the fields a validation error complains about.
+func printError(w io.Writer, err error) {
+	fmt.Fprintln(w, err)
+
+	var serviceErr *apperrors.ServiceError
+	if errors.As(err, &serviceErr) {
+		for _, detail := range serviceErr.Details {
+			fmt.Fprintf(w, "  %s: %s\n", detail.Field, detail.Message)
+		}
+	}
+}
+
+func parseID(raw string) (int, error) {
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%q is not an id", raw)
+	}
+
+	return id, nil
+}
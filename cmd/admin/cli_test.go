@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"main.go/internal/entity"
+)
+
+type fakeAuth struct {
+	created entity.User
+}
+
+func (f *fakeAuth) CreateUser(_ context.Context, user entity.User) (int, error) {
+	f.created = user
+	return 7, nil
+}
+
+type fakeUsers struct {
+	calls []string
+}
+
+func (f *fakeUsers) GetAll(context.Context) ([]entity.UserInfo, error) {
+	return []entity.UserInfo{
+		{ID: 1, Name: "Ann", Color: "ff0000", Username: "ann", Disabled: false, Lists: 2},
+		{ID: 2, Name: "Bob", Color: "00ff00", Username: "bob", Disabled: true, Lists: 0},
+	}, nil
+}
+
+func (f *fakeUsers) SetDisabled(_ context.Context, userID int, disabled bool) error {
+	f.calls = append(f.calls, fmt.Sprintf("SetDisabled %d %t", userID, disabled))
+	return nil
+}
+
+func (f *fakeUsers) Delete(_ context.Context, userID int) error {
+	f.calls = append(f.calls, fmt.Sprintf("Delete %d", userID))
+	return nil
+}
+
+func (f *fakeUsers) ResetPassword(_ context.Context, userID int, password string) error {
+	f.calls = append(f.calls, fmt.Sprintf("ResetPassword %d %s", userID, password))
+	return nil
+}
+
+func (f *fakeUsers) SetColor(_ context.Context, userID int, color string) error {
+	f.calls = append(f.calls, fmt.Sprintf("SetColor %d %s", userID, color))
+	return nil
+}
+
+func (f *fakeUsers) TransferList(_ context.Context, listID, userID int) error {
+	f.calls = append(f.calls, fmt.Sprintf("TransferList %d %d", listID, userID))
+	return nil
+}
+
+func (f *fakeUsers) StudioStats(context.Context) (entity.StudioStats, error) {
+	return entity.StudioStats{
+		Users: 2, DisabledUsers: 1, Lists: 2, Items: 5, UpcomingItems: 3,
+		PendingBookings: 1, WaitingClients: 4, PendingOffers: 0,
+	}, nil
+}
+
+func TestCLI_Run(t *testing.T) {
+	testTable := []struct {
+		name           string
+		args           []string
+		stdin          string
+		expectedCalls  []string
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:          "List",
+			args:          []string{"users", "list"},
+			stdin:         "",
+			expectedCalls: nil,
+			expectedOutput: "ID  USERNAME  NAME  COLOR   LISTS  STATUS\n" +
+				"1   ann       Ann   ff0000  2      active\n" +
+				"2   bob       Bob   00ff00  0      disabled\n",
+			expectedError: "",
+		},
+		{
+			name:           "Disable JSON",
+			args:           []string{"-json", "users", "disable", "2"},
+			stdin:          "",
+			expectedCalls:  []string{"SetDisabled 2 true"},
+			expectedOutput: "{\n  \"status\": \"ok\",\n  \"id\": 2\n}\n",
+			expectedError:  "",
+		},
+		{
+			name:           "Reset Password From Stdin",
+			args:           []string{"users", "reset-password", "1"},
+			stdin:          "s3cret!\n",
+			expectedCalls:  []string{"ResetPassword 1 s3cret!"},
+			expectedOutput: "user 1 updated\n",
+			expectedError:  "",
+		},
+		{
+			name:           "Delete Needs Confirmation",
+			args:           []string{"users", "delete", "1"},
+			stdin:          "",
+			expectedCalls:  nil,
+			expectedOutput: "",
+			expectedError:  "deleting a user also deletes their lists and items, pass -yes to confirm",
+		},
+		{
+			name:           "Delete",
+			args:           []string{"users", "delete", "-yes", "1"},
+			stdin:          "",
+			expectedCalls:  []string{"Delete 1"},
+			expectedOutput: "user 1 deleted\n",
+			expectedError:  "",
+		},
+		{
+			name:           "Transfer",
+			args:           []string{"lists", "transfer", "3", "2"},
+			stdin:          "",
+			expectedCalls:  []string{"TransferList 3 2"},
+			expectedOutput: "list 3 transferred to user 2\n",
+			expectedError:  "",
+		},
+		{
+			name:           "Bad ID",
+			args:           []string{"users", "set-color", "ann", "ff0000"},
+			stdin:          "",
+			expectedCalls:  nil,
+			expectedOutput: "",
+			expectedError:  `"ann" is not an id`,
+		},
+		{
+			name:          "Stats",
+			args:          []string{"stats"},
+			stdin:         "",
+			expectedCalls: nil,
+			expectedOutput: "users             2 (1 disabled)\nlists             2\nitems             5 (3 upcoming)\n" +
+				"pending bookings  1\nwaiting clients   4 (0 offers pending)\n",
+			expectedError: "",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init deps
+			users := &fakeUsers{calls: nil}
+			stdout := &bytes.Buffer{}
+			cli := &CLI{
+				auth:   &fakeAuth{created: entity.User{}},
+				users:  users,
+				stdin:  strings.NewReader(testCase.stdin),
+				stdout: stdout,
+				json:   false,
+			}
+
+			// Run
+			err := cli.Run(context.Background(), testCase.args)
+
+			// Assert
+			if testCase.expectedError != "" {
+				require.EqualError(t, err, testCase.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, testCase.expectedCalls, users.calls)
+			require.Equal(t, testCase.expectedOutput, stdout.String())
+		})
+	}
+}
+
+func TestCLI_CreateUser(t *testing.T) {
+	auth := &fakeAuth{created: entity.User{}}
+	stdout := &bytes.Buffer{}
+	cli := &CLI{
+		auth:   auth,
+		users:  &fakeUsers{calls: nil},
+		stdin:  strings.NewReader("hunter22\n"),
+		stdout: stdout,
+		json:   false,
+	}
+
+	err := cli.Run(context.Background(), []string{
+		"users", "create", "-name", "Ann", "-username", "ann", "-color", "ff0000",
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, entity.User{ID: 0, Name: "Ann", Color: "ff0000", Username: "ann", Password: "hunter22"}, auth.created)
+	require.Equal(t, "user 7 created\n", stdout.String())
+}
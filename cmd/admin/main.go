@@ -0,0 +1,64 @@
+// Command admin manages users and data of the studio directly against the
+// database, for what the API doesn't offer.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/jmoiron/sqlx"
+	// Package pq is a pure Go Postgres driver for the database/sql package.
+	_ "github.com/lib/pq"
+	"main.go/internal/config"
+	"main.go/internal/repository"
+	"main.go/internal/repository/postgres"
+	"main.go/internal/repository/sqlite"
+	"main.go/internal/service"
+)
+
+func main() {
+	cfg, err := config.Load(".")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if cfg.Store == "memory" {
+		fmt.Fprintln(os.Stderr, "the memory store keeps no data to manage")
+		os.Exit(1)
+	}
+
+	var dataBase *sqlx.DB
+	if cfg.Store == "sqlite" {
+		dataBase, err = sqlite.NewSQLiteDB(cfg.SQLite)
+	} else {
+		dataBase, err = postgres.NewPostgresDB(cfg.DB)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to initialize db: %s\n", err)
+		os.Exit(1)
+	}
+
+	repo := repository.NewRepository(dataBase, cfg.QueryTimeouts)
+	if cfg.Store == "sqlite" {
+		repo = repository.NewSQLiteRepository(dataBase, cfg.QueryTimeouts)
+	}
+	cli := &CLI{
+		auth:   service.NewAuthorizationService(repo.Authorization),
+		users:  service.NewUsersService(repo.Users),
+		stdin:  os.Stdin,
+		stdout: os.Stdout,
+		json:   false,
+	}
+
+	err = cli.Run(context.Background(), os.Args[1:])
+
+	dataBase.Close()
+
+	if err != nil {
+		printError(os.Stderr, err)
+		os.Exit(1)
+	}
+}
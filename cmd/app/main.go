@@ -1,7 +1,16 @@
 package main
 
-import "main.go/internal/app"
+import (
+	"os"
+
+	"main.go/internal/app"
+)
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		app.Migrate(os.Args[2:])
+		return
+	}
+
 	app.Run()
 }
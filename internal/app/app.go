@@ -2,22 +2,43 @@ package app
 
 import (
 	"context"
+	"crypto/tls"
+	"database/sql"
+	"errors"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
-	"github.com/joho/godotenv"
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
 	// Package pq is a pure Go Postgres driver for the database/sql package.
 	_ "github.com/lib/pq"
 	"github.com/sirupsen/logrus"
-	"github.com/spf13/viper"
+	"main.go/internal/cache"
+	"main.go/internal/config"
+	grpcapi "main.go/internal/controller/grpc"
 	handler "main.go/internal/controller/rest"
+	"main.go/internal/health"
+	"main.go/internal/metrics"
 	"main.go/internal/repository"
+	"main.go/internal/repository/memory"
+	"main.go/internal/repository/migrate"
 	"main.go/internal/repository/postgres"
+	"main.go/internal/repository/sqlite"
 	"main.go/internal/server"
 	"main.go/internal/service"
+	"main.go/internal/tracing"
+	"main.go/schema"
 )
 
+// runner is a server the app starts and drains, REST or gRPC.
+type runner interface {
+	Run() error
+	Shutdown(ctx context.Context) error
+}
+
 // @title Timestamp App API
 // @version 1.0
 // @description API Server for studio's calendar Application
@@ -32,43 +53,130 @@ import (
 func Run() {
 	logrus.SetFormatter(new(logrus.JSONFormatter))
 
-	if err := initConfig(); err != nil {
+	cfg, err := config.Load(".")
+	if err != nil {
 		logrus.Fatalf("error initialazing configs: %s", err.Error())
 	}
 
-	if err := godotenv.Load(); err != nil {
-		logrus.Fatalf("error loading env variables: %s", err.Error())
+	shutdownTracing, err := tracing.Setup(cfg.Tracing)
+	if err != nil {
+		logrus.Fatalf("failed to initialize tracing: %s", err.Error())
 	}
 
-	dataBase, err := postgres.NewPostgresDB(postgres.Config{
-		Host:     viper.GetString("db.host"),
-		Port:     viper.GetString("db.port"),
-		Username: viper.GetString("db.username"),
-		Password: os.Getenv("DB_PASSWORD"),
-		DBName:   viper.GetString("db.dbname"),
-		SSLMode:  viper.GetString("db.sslmode"),
+	appMetrics := metrics.New()
+
+	repo, dataBase := openStore(cfg, appMetrics)
+
+	var (
+		replica   *repository.Replica
+		replicaDB *sqlx.DB
+	)
+
+	if cfg.Store == "postgres" && cfg.DB.Replica.DSN != "" {
+		replica, replicaDB = openReplica(cfg, dataBase, appMetrics)
+		repo = repository.Route(repo, replica)
+	}
+
+	if cfg.Cache.Size > 0 {
+		repo = repository.Cached(repo, cache.NewLRU(cfg.Cache.Size, cfg.Cache.TTL))
+	}
+
+	repo = repository.Instrument(repo, appMetrics)
+
+	services := service.NewService(repo, service.Config{
+		WaitlistHold:     cfg.Waitlist.HoldTTL,
+		Booking:          cfg.Booking,
+		RateLimits:       cfg.RateLimit.Groups,
+		IdempotencyTTL:   cfg.Idempotency.TTL,
+		IdempotencyLease: cfg.Idempotency.Lease,
+		Recorder:         appMetrics,
 	})
-	if err != nil {
-		logrus.Fatalf("failed to initialize db: %s", err.Error())
+	var pinger health.Pinger
+	if dataBase != nil {
+		pinger = dataBase
 	}
 
-	repo := repository.NewRepository(dataBase)
-	services := service.NewService(repo)
-	handlers := handler.NewHandlers(services)
+	checker := health.NewChecker(pinger)
+	handlers := handler.NewHandlers(services, checker, nil)
 
-	srv := server.NewServer(
-		viper.GetString("port"),
-		handlers.InitRoutes(),
-		viper.GetInt("maxHeaderBytes"),
-		viper.GetDuration("readTimeout"),
-		viper.GetDuration("writeTimeout"),
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	runWorker(workersCtx, checker, "waitlist", cfg.Waitlist.ExpireInterval, services.Waitlist.Run)
+	runWorker(workersCtx, checker, "rateLimit", cfg.RateLimit.CleanupInterval, services.RateLimit.Run)
+	runWorker(workersCtx, checker, "idempotency", cfg.Idempotency.CleanupInterval, services.Idempotency.Run)
+	runWorker(workersCtx, checker, "stats", cfg.Metrics.StatsInterval, services.Stats.Run)
+
+	if cfg.Store == "postgres" {
+		go logPoolStats(workersCtx, dataBase.DB, cfg.DB.StatsInterval)
+	}
+
+	if replica != nil {
+		runWorker(workersCtx, checker, "replica", cfg.DB.Replica.CheckInterval, replica.Run)
+	}
+
+	// Metrics get a listener of their own that is closed last, so they can
+	// still be scraped while the API drains.
+	metricsSrv := server.NewServer(
+		cfg.Metrics.Port,
+		metricsRouter(appMetrics),
+		cfg.MaxHeaderBytes,
+		cfg.ReadTimeout,
+		cfg.WriteTimeout,
 	)
 	go func() {
-		if err = srv.Run(); err != nil {
-			logrus.Fatalf("error occured while running http server: %s", err.Error())
+		if err := metricsSrv.Run(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logrus.Errorf("error occured while running metrics server: %s", err.Error())
 		}
 	}()
 
+	router, err := handlers.InitRoutes(cfg.TrustedProxies, tracing.Middleware, appMetrics.Middleware, replicaSession)
+	if err != nil {
+		logrus.Fatalf("failed to initialize routes: %s", err.Error())
+	}
+
+	srv := server.NewServer(
+		cfg.Port,
+		router,
+		cfg.MaxHeaderBytes,
+		cfg.ReadTimeout,
+		cfg.WriteTimeout,
+	)
+	if cfg.TLS.Enabled() {
+		if err = srv.EnableTLS(cfg.TLS); err != nil {
+			logrus.Fatalf("failed to initialize tls: %s", err.Error())
+		}
+	}
+
+	// The gRPC API gets a listener of its own and shuts down with the REST
+	// one.
+	var grpcTLS *tls.Config
+
+	if cfg.TLS.Enabled() {
+		grpcTLS, err = server.NewTLSConfig(workersCtx, cfg.TLS)
+		if err != nil {
+			logrus.Fatalf("failed to initialize grpc tls: %s", err.Error())
+		}
+	}
+
+	grpcSrv := grpcapi.NewServer(services, grpcapi.Options{
+		Port:           cfg.GRPC.Port,
+		TLS:            grpcTLS,
+		MaxHeaderBytes: cfg.MaxHeaderBytes,
+		Observer:       appMetrics,
+	}, grpcReplicaSession)
+
+	servers := []runner{srv, grpcSrv}
+	if cfg.TLS.RedirectPort != "" {
+		servers = append(servers, server.NewRedirectServer(cfg.TLS.RedirectPort, cfg.Port, cfg.ReadTimeout, cfg.WriteTimeout))
+	}
+
+	for _, s := range servers {
+		go func(s runner) {
+			if err := s.Run(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logrus.Fatalf("error occured while running server: %s", err.Error())
+			}
+		}(s)
+	}
+
 	logrus.Println("App started")
 
 	quit := make(chan os.Signal, 1)
@@ -77,17 +185,177 @@ func Run() {
 
 	logrus.Println("App is shutting down")
 
-	if err = srv.Shutdown(context.Background()); err != nil {
-		logrus.Errorf("error occured on server shutting down: %s", err.Error())
+	// Fail readiness first and keep serving for a while, so the orchestrator
+	// stops routing here before the listener closes.
+	checker.Drain()
+	time.Sleep(cfg.Shutdown.Delay)
+
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), cfg.Shutdown.Timeout)
+	defer cancelDrain()
+
+	for _, s := range servers {
+		if err = s.Shutdown(drainCtx); err != nil {
+			logrus.Errorf("error occured on server shutting down: %s", err.Error())
+		}
+	}
+
+	stopWorkers()
+
+	for _, db := range []*sqlx.DB{dataBase, replicaDB} {
+		if db == nil {
+			continue
+		}
+
+		if err = db.Close(); err != nil {
+			logrus.Errorf("error occured on db connection close: %s", err.Error())
+		}
+	}
+
+	if err = shutdownTracing(context.Background()); err != nil {
+		logrus.Errorf("error occured on flushing traces: %s", err.Error())
 	}
 
-	if err = dataBase.Close(); err != nil {
-		logrus.Errorf("error occured on db connection close: %s", err.Error())
+	if err = metricsSrv.Shutdown(context.Background()); err != nil {
+		logrus.Errorf("error occured on metrics server shutting down: %s", err.Error())
 	}
 }
 
-func initConfig() error {
-	viper.AddConfigPath("configs")
-	viper.SetConfigName("config")
-	return viper.ReadInConfig()
+// runWorker starts a background worker under the watch of the readiness
+// checks.
+func runWorker(
+	ctx context.Context,
+	checker *health.Checker,
+	name string,
+	interval time.Duration,
+	run func(ctx context.Context, interval time.Duration),
+) {
+	go run(checker.Watch(ctx, name, interval), interval)
+}
+
+// logPoolStats logs the connection pool every interval until ctx is
+// cancelled, so a pool running dry shows up next to the slow requests.
+func logPoolStats(ctx context.Context, db *sql.DB, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		stats := db.Stats()
+		logrus.WithFields(logrus.Fields{
+			"open":              stats.OpenConnections,
+			"inUse":             stats.InUse,
+			"idle":              stats.Idle,
+			"maxOpen":           stats.MaxOpenConnections,
+			"waitCount":         stats.WaitCount,
+			"waitDuration":      stats.WaitDuration.String(),
+			"maxIdleClosed":     stats.MaxIdleClosed,
+			"maxIdleTimeClosed": stats.MaxIdleTimeClosed,
+			"maxLifetimeClosed": stats.MaxLifetimeClosed,
+		}).Info("db pool stats")
+	}
+}
+
+func metricsRouter(appMetrics *metrics.Metrics) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", appMetrics.Handler())
+
+	return mux
+}
+
+// openStore connects to the configured store. The memory store needs no
+// database and loses everything on restart, so it's only meant for demos;
+// dataBase is nil then.
+func openStore(cfg *config.Config, appMetrics *metrics.Metrics) (*repository.Repository, *sqlx.DB) {
+	if cfg.Store == "memory" {
+		logrus.Warn("running on the in-memory store, data will be lost on restart")
+
+		return repository.NewMemoryRepository(), nil
+	}
+
+	dataBase, err := openDB(cfg)
+	if err != nil {
+		logrus.Fatalf("failed to initialize db: %s", err.Error())
+	}
+
+	if cfg.Migrations.Auto {
+		migrator, err := newMigrator(cfg, dataBase)
+		if err != nil {
+			logrus.Fatalf("error reading migrations: %s", err.Error())
+		}
+
+		if err = migrator.Up(context.Background()); err != nil {
+			logrus.Fatalf("error migrating: %s", err.Error())
+		}
+	}
+
+	appMetrics.RegisterDB(dataBase.DB, cfg.Store)
+
+	repo := repository.NewRepository(dataBase, cfg.QueryTimeouts)
+	if cfg.Store == "sqlite" {
+		repo = repository.NewSQLiteRepository(dataBase, cfg.QueryTimeouts)
+	}
+
+	if cfg.RateLimit.Store == "memory" {
+		repo.RateLimit = memory.NewRateLimitMemory()
+	}
+
+	return repo, dataBase
+}
+
+// openReplica opens the read replica, which Route falls back from while it
+// is unreachable, lags behind primary or isn't streaming from it.
+func openReplica(
+	cfg *config.Config,
+	primary *sqlx.DB,
+	appMetrics *metrics.Metrics,
+) (*repository.Replica, *sqlx.DB) {
+	replicaDB, err := postgres.NewReplicaDB(cfg.DB)
+	if err != nil {
+		logrus.Fatalf("failed to initialize replica db: %s", err.Error())
+	}
+
+	appMetrics.RegisterDB(replicaDB.DB, "replica")
+
+	replica := repository.NewReplica(
+		repository.NewRepository(replicaDB, cfg.QueryTimeouts),
+		postgres.NewReplicaPostgres(replicaDB, primary, cfg.QueryTimeouts.Default),
+		cfg.DB.Replica.MaxLag,
+	)
+
+	return replica, replicaDB
+}
+
+// replicaSession lets the reads of each request go to the replica until the
+// request writes.
+func replicaSession(c *gin.Context) {
+	c.Request = c.Request.WithContext(repository.WithSession(c.Request.Context()))
+	c.Next()
+}
+
+// grpcReplicaSession is replicaSession for the calls of the gRPC API.
+func grpcReplicaSession(ctx context.Context, _ string, next grpcapi.Handler) error {
+	return next(repository.WithSession(ctx))
+}
+
+// openDB connects to the database of the configured store, Postgres or
+// SQLite.
+func openDB(cfg *config.Config) (*sqlx.DB, error) {
+	if cfg.Store == "sqlite" {
+		return sqlite.NewSQLiteDB(cfg.SQLite)
+	}
+
+	return postgres.NewPostgresDB(cfg.DB)
+}
+
+func newMigrator(cfg *config.Config, dataBase *sqlx.DB) (*migrate.Migrator, error) {
+	if cfg.Store == "sqlite" {
+		return sqlite.NewMigrator(dataBase, schema.SQLiteMigrations)
+	}
+
+	return postgres.NewMigrator(dataBase, schema.Migrations)
 }
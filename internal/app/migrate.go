@@ -0,0 +1,78 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	"main.go/internal/config"
+	"main.go/internal/repository/migrate"
+)
+
+const migrateUsage = "usage: timeslot-app migrate up|down|status|to N"
+
+// Migrate runs the migrate subcommand against the configured database.
+func Migrate(args []string) {
+	cfg, err := config.Load(".")
+	if err != nil {
+		logrus.Fatalf("error initialazing configs: %s", err.Error())
+	}
+
+	if cfg.Store == "memory" {
+		logrus.Fatal("the memory store has nothing to migrate")
+	}
+
+	dataBase, err := openDB(cfg)
+	if err != nil {
+		logrus.Fatalf("failed to initialize db: %s", err.Error())
+	}
+	defer dataBase.Close()
+
+	migrator, err := newMigrator(cfg, dataBase)
+	if err != nil {
+		logrus.Fatalf("error reading migrations: %s", err.Error())
+	}
+
+	ctx := context.Background()
+
+	switch {
+	case len(args) == 1 && args[0] == "up":
+		err = migrator.Up(ctx)
+	case len(args) == 1 && args[0] == "down":
+		err = migrator.Down(ctx)
+	case len(args) == 2 && args[0] == "to":
+		version, parseErr := strconv.ParseUint(args[1], 10, 0)
+		if parseErr != nil {
+			logrus.Fatal(migrateUsage)
+		}
+
+		err = migrator.To(ctx, uint(version))
+	case len(args) != 1 || args[0] != "status":
+		logrus.Fatal(migrateUsage)
+	}
+
+	if err != nil {
+		logrus.Fatalf("error migrating: %s", err.Error())
+	}
+
+	status, err := migrator.Status(ctx)
+	if err != nil {
+		logrus.Fatalf("error reading migration status: %s", err.Error())
+	}
+
+	printStatus(status)
+}
+
+func printStatus(status migrate.Status) {
+	dirty := ""
+	if status.Dirty {
+		dirty = " (dirty)"
+	}
+
+	fmt.Printf("version %d%s, latest %d\n", status.Version, dirty, status.Latest) //nolint:forbidigo // command output
+
+	for _, migration := range status.Pending {
+		fmt.Printf("pending %d_%s\n", migration.Version, migration.Name) //nolint:forbidigo // command output
+	}
+}
@@ -0,0 +1,93 @@
+// Package cache holds the in-process cache the repositories keep hot data
+// in when no shared cache is configured.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// LRU keeps up to size values for ttl each, evicting the least recently
+// used first. It is safe for concurrent use.
+type LRU struct {
+	size int
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+func NewLRU(size int, ttl time.Duration) *LRU {
+	return &LRU{
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		mu:      sync.Mutex{},
+		order:   list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+func (c *LRU) Get(_ context.Context, key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	stored := element.Value.(*entry) //nolint:forcetypeassert // only entries are stored
+	if c.now().After(stored.expires) {
+		c.remove(element)
+
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+
+	return stored.value, true
+}
+
+func (c *LRU) Set(_ context.Context, key string, value []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := c.now().Add(c.ttl)
+
+	if element, ok := c.entries[key]; ok {
+		stored := element.Value.(*entry) //nolint:forcetypeassert // only entries are stored
+		stored.value, stored.expires = value, expires
+		c.order.MoveToFront(element)
+
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&entry{key: key, value: value, expires: expires})
+
+	if c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *LRU) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*entry).key) //nolint:forcetypeassert // only entries are stored
+}
@@ -0,0 +1,41 @@
+package cache //nolint:testpackage // controls the clock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	lru := NewLRU(2, time.Minute)
+	lru.now = func() time.Time { return now }
+
+	lru.Set(ctx, "a", []byte("1"))
+	lru.Set(ctx, "b", []byte("2"))
+
+	value, ok := lru.Get(ctx, "a")
+	require.True(t, ok)
+	require.Equal(t, []byte("1"), value)
+
+	lru.Set(ctx, "c", []byte("3"))
+	require.Equal(t, 2, lru.Len())
+
+	_, ok = lru.Get(ctx, "b")
+	require.False(t, ok, "least recently used is evicted")
+
+	lru.Set(ctx, "a", []byte("4"))
+
+	value, ok = lru.Get(ctx, "a")
+	require.True(t, ok)
+	require.Equal(t, []byte("4"), value)
+
+	now = now.Add(time.Minute + time.Second)
+
+	_, ok = lru.Get(ctx, "c")
+	require.False(t, ok, "expired")
+	require.Equal(t, 1, lru.Len())
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/spf13/viper"
+	"main.go/internal/entity"
+	"main.go/internal/repository"
+	"main.go/internal/repository/postgres"
+	"main.go/internal/repository/sqlite"
+	"main.go/internal/server"
+	"main.go/internal/service"
+	"main.go/internal/tracing"
+)
+
+// fileSuffix marks env vars that name a file holding the value instead, as
+// Docker and Kubernetes mount secrets.
+const fileSuffix = "_FILE"
+
+// Config is everything the app reads at startup. Every key has a default,
+// may be set in configs/config.yml and may be overridden by an env var named
+// after its path, e.g. DB_PASSWORD for db.password or DB_PASSWORD_FILE to
+// read it from a file.
+type Config struct {
+	Port           string                   `mapstructure:"port"`
+	MaxHeaderBytes int                      `mapstructure:"maxHeaderBytes"`
+	ReadTimeout    time.Duration            `mapstructure:"readTimeout"`
+	WriteTimeout   time.Duration            `mapstructure:"writeTimeout"`
+	Store          string                   `mapstructure:"store"`
+	TrustedProxies []string                 `mapstructure:"trustedProxies"`
+	TLS            server.TLSConfig         `mapstructure:"tls"`
+	Shutdown       Shutdown                 `mapstructure:"shutdown"`
+	Waitlist       Waitlist                 `mapstructure:"waitlist"`
+	Booking        service.BookingConfig    `mapstructure:"booking"`
+	RateLimit      RateLimit                `mapstructure:"rateLimit"`
+	Idempotency    Idempotency              `mapstructure:"idempotency"`
+	Metrics        Metrics                  `mapstructure:"metrics"`
+	GRPC           GRPC                     `mapstructure:"grpc"`
+	Tracing        tracing.Config           `mapstructure:"tracing"`
+	DB             postgres.Config          `mapstructure:"db"`
+	SQLite         sqlite.Config            `mapstructure:"sqlite"`
+	QueryTimeouts  repository.QueryTimeouts `mapstructure:"queryTimeouts"`
+	Cache          Cache                    `mapstructure:"cache"`
+	Migrations     Migrations               `mapstructure:"migrations"`
+}
+
+type Shutdown struct {
+	Delay   time.Duration `mapstructure:"delay"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+type Waitlist struct {
+	HoldTTL        time.Duration `mapstructure:"holdTTL"`
+	ExpireInterval time.Duration `mapstructure:"expireInterval"`
+}
+
+type RateLimit struct {
+	Store           string                      `mapstructure:"store"`
+	CleanupInterval time.Duration               `mapstructure:"cleanupInterval"`
+	Groups          map[string]entity.RateLimit `mapstructure:"groups"`
+}
+
+// Idempotency.Lease is how long a request may hold its key before a retry
+// may take it over, for requests lost with a crashed instance.
+type Idempotency struct {
+	TTL             time.Duration `mapstructure:"ttl"`
+	Lease           time.Duration `mapstructure:"lease"`
+	CleanupInterval time.Duration `mapstructure:"cleanupInterval"`
+}
+
+// Migrations.Auto applies pending migrations at startup. Replicas starting
+// together take turns through an advisory lock.
+type Migrations struct {
+	Auto bool `mapstructure:"auto"`
+}
+
+// Cache.Size is how many schedule ranges and day generations are kept in
+// process, 0 turning the cache off, and TTL how long each is kept.
+type Cache struct {
+	Size int           `mapstructure:"size"`
+	TTL  time.Duration `mapstructure:"ttl"`
+}
+
+type Metrics struct {
+	Port          string        `mapstructure:"port"`
+	StatsInterval time.Duration `mapstructure:"statsInterval"`
+}
+
+// GRPC.Port is where the gRPC API listens, next to the REST one on Port.
+type GRPC struct {
+	Port string `mapstructure:"port"`
+}
+
+// Load reads .env and configs/config.yml under dir when they exist, applies
+// env overrides and validates the result, reporting every problem at once.
+func Load(dir string) (*Config, error) {
+	if err := godotenv.Load(filepath.Join(dir, ".env")); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("error loading .env: %w", err)
+	}
+
+	v := viper.New()
+	setDefaults(v)
+
+	v.AddConfigPath(filepath.Join(dir, "configs"))
+	v.SetConfigName("config")
+
+	var notFound viper.ConfigFileNotFoundError
+	if err := v.ReadInConfig(); err != nil && !errors.As(err, &notFound) {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	problems := readFiles(v)
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		problems = append(problems, err)
+	} else {
+		problems = append(problems, cfg.validate()...)
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid config:\n%w", errors.Join(problems...))
+	}
+
+	return &cfg, nil
+}
+
+// readFiles sets every key whose KEY_FILE env var is set to the contents of
+// that file.
+func readFiles(v *viper.Viper) []error {
+	var problems []error
+
+	for _, key := range v.AllKeys() {
+		env := envName(key)
+
+		path := os.Getenv(env + fileSuffix)
+		if path == "" {
+			continue
+		}
+
+		if _, ok := os.LookupEnv(env); ok {
+			problems = append(problems, fmt.Errorf("%s and %s are both set", env, env+fileSuffix))
+			continue
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", env+fileSuffix, err))
+			continue
+		}
+
+		v.Set(key, strings.TrimRight(string(content), "\r\n"))
+	}
+
+	return problems
+}
+
+func envName(key string) string {
+	return strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
@@ -0,0 +1,157 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"main.go/internal/config"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestLoad(t *testing.T) {
+	testTable := []struct {
+		name          string
+		configFile    string
+		env           map[string]string
+		secretFile    string
+		check         func(t *testing.T, cfg *config.Config)
+		expectedError string
+	}{
+		{
+			name:       "Defaults Without Files",
+			configFile: "",
+			env:        map[string]string{},
+			secretFile: "",
+			check: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+				require.Equal(t, "8000", cfg.Port)
+				require.Equal(t, 10*time.Second, cfg.ReadTimeout)
+				require.Empty(t, cfg.TrustedProxies)
+				require.Equal(t, time.Minute, cfg.Idempotency.Lease)
+				require.Equal(t, 5, cfg.RateLimit.Groups["auth"].Requests)
+				require.Equal(t, "disable", cfg.DB.SSLMode)
+				require.Equal(t, 5*time.Second, cfg.QueryTimeouts.Default)
+				require.Equal(t, 8*time.Second, cfg.QueryTimeouts.Search)
+				require.Equal(t, 8*time.Second, cfg.QueryTimeouts.GetByRange)
+				require.Zero(t, cfg.QueryTimeouts.Writes)
+				require.Equal(t, 25, cfg.DB.MaxOpenConns)
+				require.Equal(t, 30*time.Second, cfg.DB.ConnectTimeout)
+				require.Empty(t, cfg.DB.Replica.DSN)
+				require.Equal(t, 5*time.Second, cfg.DB.Replica.MaxLag)
+				require.Equal(t, 4096, cfg.Cache.Size)
+				require.Equal(t, "9090", cfg.GRPC.Port)
+			},
+			expectedError: "",
+		},
+		{
+			name:       "File And Env Overrides",
+			configFile: "port: \"8080\"\nrateLimit:\n  store: \"postgres\"\n",
+			env: map[string]string{
+				"PORT":                          "9000",
+				"RATELIMIT_GROUPS_API_REQUESTS": "50",
+				"BOOKING_MINSLOT":               "15m",
+				"TRUSTEDPROXIES":                "10.0.0.0/8,192.0.2.1",
+			},
+			secretFile: "",
+			check: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+				require.Equal(t, "9000", cfg.Port)
+				require.Equal(t, "postgres", cfg.RateLimit.Store)
+				require.Equal(t, 50, cfg.RateLimit.Groups["api"].Requests)
+				require.Equal(t, 15*time.Minute, cfg.Booking.MinSlot)
+				require.Equal(t, []string{"10.0.0.0/8", "192.0.2.1"}, cfg.TrustedProxies)
+			},
+			expectedError: "",
+		},
+		{
+			name:       "Secret File",
+			configFile: "",
+			env:        map[string]string{},
+			secretFile: "s3cret\n",
+			check: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+				require.Equal(t, "s3cret", cfg.DB.Password)
+			},
+			expectedError: "",
+		},
+		{
+			name:       "SQLite Ignores Postgres",
+			configFile: "",
+			env:        map[string]string{"STORE": "sqlite", "DB_PORT": "none"},
+			secretFile: "",
+			check: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+				require.Equal(t, "sqlite", cfg.Store)
+				require.Equal(t, "timeslots.db", cfg.SQLite.Path)
+			},
+			expectedError: "",
+		},
+		{
+			name:       "All Problems Reported",
+			configFile: "",
+			env: map[string]string{
+				"PORT":                 "http",
+				"GRPC_PORT":            "9100",
+				"TRUSTEDPROXIES":       "proxy.local",
+				"RATELIMIT_STORE":      "redis",
+				"TRACING_EXPORTER":     "jaeger",
+				"SHUTDOWN_TIMEOUT":     "0s",
+				"QUERYTIMEOUTS_SEARCH": "1m",
+				"DB_PASSWORD":          "inline",
+				"DB_PASSWORD_FILE":     "/run/secrets/db",
+			},
+			secretFile: "",
+			check:      nil,
+			expectedError: "invalid config:\n" +
+				"DB_PASSWORD and DB_PASSWORD_FILE are both set\n" +
+				"port: \"http\" is not a port\n" +
+				"trustedProxies: \"proxy.local\" is not an IP or CIDR\n" +
+				"shutdown.timeout: must be positive, got 0s\n" +
+				"rateLimit.store: \"redis\" is not one of memory, postgres\n" +
+				"grpc.port: must differ from port http and metrics.port 9100\n" +
+				"tracing.exporter: \"jaeger\" is not one of none, stdout, otlp\n" +
+				"queryTimeouts.search: must be between 0 and writeTimeout 10s, got 1m0s",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init deps
+			dir := t.TempDir()
+
+			if testCase.configFile != "" {
+				writeFile(t, filepath.Join(dir, "configs", "config.yml"), testCase.configFile)
+			}
+
+			if testCase.secretFile != "" {
+				secret := filepath.Join(dir, "db_password")
+				writeFile(t, secret, testCase.secretFile)
+				t.Setenv("DB_PASSWORD_FILE", secret)
+			}
+
+			for key, value := range testCase.env {
+				t.Setenv(key, value)
+			}
+
+			// Load
+			cfg, err := config.Load(dir)
+
+			// Assert
+			if testCase.expectedError != "" {
+				require.EqualError(t, err, testCase.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			testCase.check(t, cfg)
+		})
+	}
+}
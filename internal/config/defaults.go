@@ -0,0 +1,95 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const defaultRateLimitPer = time.Minute
+
+// setDefaults registers every key, which is also what lets env vars
+// override keys missing from the config file.
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("port", "8000")
+	v.SetDefault("maxHeaderBytes", 1<<20)
+	v.SetDefault("readTimeout", 10*time.Second)
+	v.SetDefault("writeTimeout", 10*time.Second)
+	v.SetDefault("store", "postgres")
+	v.SetDefault("trustedProxies", []string{})
+
+	v.SetDefault("tls.certFile", "")
+	v.SetDefault("tls.keyFile", "")
+	v.SetDefault("tls.minVersion", "1.2")
+	v.SetDefault("tls.watchInterval", time.Minute)
+	v.SetDefault("tls.redirectPort", "")
+
+	v.SetDefault("shutdown.delay", 5*time.Second)
+	v.SetDefault("shutdown.timeout", 20*time.Second)
+
+	v.SetDefault("waitlist.holdTTL", 2*time.Hour)
+	v.SetDefault("waitlist.expireInterval", time.Minute)
+
+	v.SetDefault("booking.dayStart", 10*time.Hour)
+	v.SetDefault("booking.dayEnd", 20*time.Hour)
+	v.SetDefault("booking.minSlot", 30*time.Minute)
+	v.SetDefault("booking.maxRange", 14*24*time.Hour)
+	v.SetDefault("booking.maxRequestsPerIP", 5)
+	v.SetDefault("booking.ipWindow", time.Hour)
+	v.SetDefault("booking.maxPendingPerEmail", 3)
+
+	v.SetDefault("rateLimit.store", "memory")
+	v.SetDefault("rateLimit.cleanupInterval", 10*time.Minute)
+	v.SetDefault("rateLimit.groups.auth.requests", 5)
+	v.SetDefault("rateLimit.groups.auth.per", defaultRateLimitPer)
+	v.SetDefault("rateLimit.groups.auth.burst", 5)
+	v.SetDefault("rateLimit.groups.public.requests", 30)
+	v.SetDefault("rateLimit.groups.public.per", defaultRateLimitPer)
+	v.SetDefault("rateLimit.groups.public.burst", 10)
+	v.SetDefault("rateLimit.groups.api.requests", 600)
+	v.SetDefault("rateLimit.groups.api.per", defaultRateLimitPer)
+	v.SetDefault("rateLimit.groups.api.burst", 100)
+
+	v.SetDefault("idempotency.ttl", 24*time.Hour)
+	v.SetDefault("idempotency.lease", time.Minute)
+	v.SetDefault("idempotency.cleanupInterval", time.Hour)
+
+	v.SetDefault("metrics.port", "9100")
+	v.SetDefault("metrics.statsInterval", time.Minute)
+
+	v.SetDefault("grpc.port", "9090")
+
+	v.SetDefault("tracing.exporter", "none")
+	v.SetDefault("tracing.endpoint", "http://localhost:4318")
+	v.SetDefault("tracing.serviceName", "timeslot-app")
+	v.SetDefault("tracing.sampleRatio", 1)
+
+	v.SetDefault("db.host", "localhost")
+	v.SetDefault("db.port", "5432")
+	v.SetDefault("db.username", "postgres")
+	v.SetDefault("db.password", "")
+	v.SetDefault("db.dbname", "postgres")
+	v.SetDefault("db.sslmode", "disable")
+	v.SetDefault("db.maxOpenConns", 25)
+	v.SetDefault("db.maxIdleConns", 10)
+	v.SetDefault("db.connMaxLifetime", 30*time.Minute)
+	v.SetDefault("db.connMaxIdleTime", 5*time.Minute)
+	v.SetDefault("db.connectTimeout", 30*time.Second)
+	v.SetDefault("db.statsInterval", time.Minute)
+	v.SetDefault("db.replica.dsn", "")
+	v.SetDefault("db.replica.maxLag", 5*time.Second)
+	v.SetDefault("db.replica.checkInterval", 2*time.Second)
+
+	v.SetDefault("sqlite.path", "timeslots.db")
+
+	v.SetDefault("queryTimeouts.default", 5*time.Second)
+	v.SetDefault("queryTimeouts.writes", 0)
+	v.SetDefault("queryTimeouts.itemSearch", 8*time.Second)
+	v.SetDefault("queryTimeouts.getByRange", 8*time.Second)
+	v.SetDefault("queryTimeouts.search", 8*time.Second)
+
+	v.SetDefault("cache.size", 4096)
+	v.SetDefault("cache.ttl", 5*time.Minute)
+
+	v.SetDefault("migrations.auto", false)
+}
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"main.go/internal/server"
+	"main.go/internal/tracing"
+)
+
+const (
+	maxPort  = 65535
+	day      = 24 * time.Hour
+	maxRatio = 1
+)
+
+//nolint:gochecknoglobals // lookup tables
+var (
+	stores           = map[string]bool{"memory": true, "postgres": true, "sqlite": true}
+	rateLimitStores  = map[string]bool{"memory": true, "postgres": true}
+	tracingExporters = map[string]bool{
+		tracing.ExporterNone:   true,
+		tracing.ExporterStdout: true,
+		tracing.ExporterOTLP:   true,
+	}
+	sslModes = map[string]bool{
+		"disable": true, "allow": true, "prefer": true, "require": true, "verify-ca": true, "verify-full": true,
+	}
+)
+
+// validate returns every problem with cfg rather than stopping at the first,
+// so a broken deployment can be fixed in one go.
+func (cfg *Config) validate() []error {
+	var problems []error
+
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			problems = append(problems, fmt.Errorf(format, args...))
+		}
+	}
+
+	checkPort := func(key, port string) {
+		n, err := strconv.Atoi(port)
+		check(err == nil && n > 0 && n <= maxPort, "%s: %q is not a port", key, port)
+	}
+
+	checkPositive := func(key string, d time.Duration) {
+		check(d > 0, "%s: must be positive, got %s", key, d)
+	}
+
+	checkPort("port", cfg.Port)
+	check(cfg.MaxHeaderBytes > 0, "maxHeaderBytes: must be positive, got %d", cfg.MaxHeaderBytes)
+	checkPositive("readTimeout", cfg.ReadTimeout)
+	checkPositive("writeTimeout", cfg.WriteTimeout)
+	check(stores[cfg.Store], "store: %q is not one of postgres, sqlite, memory", cfg.Store)
+
+	for _, proxy := range cfg.TrustedProxies {
+		_, _, err := net.ParseCIDR(proxy)
+		check(err == nil || net.ParseIP(proxy) != nil, "trustedProxies: %q is not an IP or CIDR", proxy)
+	}
+
+	if cfg.TLS.Enabled() {
+		check(cfg.TLS.CertFile != "" && cfg.TLS.KeyFile != "", "tls: certFile and keyFile must be set together")
+		_, knownVersion := server.TLSVersions[cfg.TLS.MinVersion]
+		check(knownVersion, "tls.minVersion: %q is not one of 1.2, 1.3", cfg.TLS.MinVersion)
+		checkPositive("tls.watchInterval", cfg.TLS.WatchInterval)
+	}
+
+	if cfg.TLS.RedirectPort != "" {
+		check(cfg.TLS.Enabled(), "tls.redirectPort: needs tls.certFile and tls.keyFile")
+		checkPort("tls.redirectPort", cfg.TLS.RedirectPort)
+		check(cfg.TLS.RedirectPort != cfg.Port, "tls.redirectPort: must differ from port %s", cfg.Port)
+	}
+
+	check(cfg.Shutdown.Delay >= 0, "shutdown.delay: must not be negative, got %s", cfg.Shutdown.Delay)
+	checkPositive("shutdown.timeout", cfg.Shutdown.Timeout)
+
+	checkPositive("waitlist.holdTTL", cfg.Waitlist.HoldTTL)
+	checkPositive("waitlist.expireInterval", cfg.Waitlist.ExpireInterval)
+
+	check(cfg.Booking.DayStart >= 0 && cfg.Booking.DayStart < cfg.Booking.DayEnd && cfg.Booking.DayEnd <= day,
+		"booking: dayStart %s and dayEnd %s must be within a day, start first", cfg.Booking.DayStart, cfg.Booking.DayEnd)
+	checkPositive("booking.minSlot", cfg.Booking.MinSlot)
+	checkPositive("booking.maxRange", cfg.Booking.MaxRange)
+	checkPositive("booking.ipWindow", cfg.Booking.IPWindow)
+	check(cfg.Booking.MaxRequestsPerIP > 0,
+		"booking.maxRequestsPerIP: must be positive, got %d", cfg.Booking.MaxRequestsPerIP)
+	check(cfg.Booking.MaxPendingPerEmail > 0,
+		"booking.maxPendingPerEmail: must be positive, got %d", cfg.Booking.MaxPendingPerEmail)
+
+	check(rateLimitStores[cfg.RateLimit.Store],
+		"rateLimit.store: %q is not one of memory, postgres", cfg.RateLimit.Store)
+	checkPositive("rateLimit.cleanupInterval", cfg.RateLimit.CleanupInterval)
+
+	for name, limit := range cfg.RateLimit.Groups {
+		check(limit.Requests > 0 && limit.Per > 0 && limit.Burst > 0,
+			"rateLimit.groups.%s: requests, per and burst must be positive", name)
+	}
+
+	checkPositive("idempotency.ttl", cfg.Idempotency.TTL)
+	check(cfg.Idempotency.Lease > cfg.WriteTimeout && cfg.Idempotency.Lease <= cfg.Idempotency.TTL,
+		"idempotency.lease: must be longer than writeTimeout %s and at most ttl %s, got %s",
+		cfg.WriteTimeout, cfg.Idempotency.TTL, cfg.Idempotency.Lease)
+	checkPositive("idempotency.cleanupInterval", cfg.Idempotency.CleanupInterval)
+
+	checkPort("metrics.port", cfg.Metrics.Port)
+	check(cfg.Metrics.Port != cfg.Port, "metrics.port: must differ from port %s", cfg.Port)
+	checkPositive("metrics.statsInterval", cfg.Metrics.StatsInterval)
+
+	checkPort("grpc.port", cfg.GRPC.Port)
+	check(cfg.GRPC.Port != cfg.Port && cfg.GRPC.Port != cfg.Metrics.Port,
+		"grpc.port: must differ from port %s and metrics.port %s", cfg.Port, cfg.Metrics.Port)
+
+	check(tracingExporters[cfg.Tracing.Exporter],
+		"tracing.exporter: %q is not one of none, stdout, otlp", cfg.Tracing.Exporter)
+	check(cfg.Tracing.SampleRatio >= 0 && cfg.Tracing.SampleRatio <= maxRatio,
+		"tracing.sampleRatio: must be between 0 and 1, got %g", cfg.Tracing.SampleRatio)
+
+	checkQueryTimeout := func(key string, d time.Duration) {
+		check(d >= 0 && d < cfg.WriteTimeout,
+			"queryTimeouts.%s: must be between 0 and writeTimeout %s, got %s", key, cfg.WriteTimeout, d)
+	}
+
+	checkQueryTimeout("default", cfg.QueryTimeouts.Default)
+	checkQueryTimeout("writes", cfg.QueryTimeouts.Writes)
+	checkQueryTimeout("itemSearch", cfg.QueryTimeouts.ItemSearch)
+	checkQueryTimeout("getByRange", cfg.QueryTimeouts.GetByRange)
+	checkQueryTimeout("search", cfg.QueryTimeouts.Search)
+
+	check(cfg.Cache.Size >= 0, "cache.size: must not be negative, got %d", cfg.Cache.Size)
+	checkPositive("cache.ttl", cfg.Cache.TTL)
+
+	switch cfg.Store {
+	case "postgres":
+		check(cfg.DB.Host != "", "db.host: must be set")
+		checkPort("db.port", cfg.DB.Port)
+		check(cfg.DB.Username != "", "db.username: must be set")
+		check(cfg.DB.DBName != "", "db.dbname: must be set")
+		check(sslModes[cfg.DB.SSLMode], "db.sslmode: %q is not a libpq sslmode", cfg.DB.SSLMode)
+		check(cfg.DB.MaxOpenConns >= 0, "db.maxOpenConns: must not be negative, got %d", cfg.DB.MaxOpenConns)
+		check(cfg.DB.MaxIdleConns >= 0 && (cfg.DB.MaxOpenConns == 0 || cfg.DB.MaxIdleConns <= cfg.DB.MaxOpenConns),
+			"db.maxIdleConns: must be between 0 and maxOpenConns %d, got %d", cfg.DB.MaxOpenConns, cfg.DB.MaxIdleConns)
+		check(cfg.DB.ConnMaxLifetime >= 0, "db.connMaxLifetime: must not be negative, got %s", cfg.DB.ConnMaxLifetime)
+		check(cfg.DB.ConnMaxIdleTime >= 0, "db.connMaxIdleTime: must not be negative, got %s", cfg.DB.ConnMaxIdleTime)
+		checkPositive("db.connectTimeout", cfg.DB.ConnectTimeout)
+		checkPositive("db.statsInterval", cfg.DB.StatsInterval)
+
+		if cfg.DB.Replica.DSN != "" {
+			checkPositive("db.replica.maxLag", cfg.DB.Replica.MaxLag)
+			checkPositive("db.replica.checkInterval", cfg.DB.Replica.CheckInterval)
+		}
+	case "sqlite":
+		check(cfg.SQLite.Path != "", "sqlite.path: must be set")
+	}
+
+	return problems
+}
@@ -0,0 +1,101 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	timeslotsv1 "main.go/api/timeslots/v1"
+	"main.go/internal/entity"
+)
+
+type AuthorizationService interface {
+	CreateUser(ctx context.Context, user entity.User) (int, error)
+	GenerateToken(ctx context.Context, username, password string) (string, error)
+	ParseToken(ctx context.Context, token string) (int, error)
+}
+
+// publicMethods are served without a token.
+var publicMethods = map[string]bool{ //nolint:gochecknoglobals // lookup table
+	timeslotsv1.AuthService_SignUp_FullMethodName: true,
+	timeslotsv1.AuthService_SignIn_FullMethodName: true,
+}
+
+type userKey struct{}
+
+// authenticate is the REST userIdentity middleware as an interceptor: calls
+// outside publicMethods need an "authorization: Bearer <token>" metadata
+// entry holding a token of a user that is still active.
+func authenticate(service AuthorizationService) Interceptor {
+	return func(ctx context.Context, method string, next Handler) error {
+		if publicMethods[method] {
+			return next(ctx)
+		}
+
+		header := firstMetadata(ctx, "authorization")
+		if header == "" {
+			return status.Error(codes.Unauthenticated, "empty authorization metadata")
+		}
+
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || token == "" {
+			return status.Error(codes.Unauthenticated, "invalid authorization metadata")
+		}
+
+		userID, err := service.ParseToken(ctx, token)
+		if err != nil {
+			return err
+		}
+
+		return next(context.WithValue(ctx, userKey{}, userID))
+	}
+}
+
+func getUserID(ctx context.Context) (int, error) {
+	userID, ok := ctx.Value(userKey{}).(int)
+	if !ok {
+		return 0, status.Error(codes.Internal, "user id not found")
+	}
+
+	return userID, nil
+}
+
+type authServer struct {
+	timeslotsv1.UnimplementedAuthServiceServer
+	service AuthorizationService
+}
+
+func newAuthServer(service AuthorizationService) *authServer {
+	return &authServer{UnimplementedAuthServiceServer: timeslotsv1.UnimplementedAuthServiceServer{}, service: service}
+}
+
+func (s *authServer) SignUp(
+	ctx context.Context,
+	request *timeslotsv1.SignUpRequest,
+) (*timeslotsv1.SignUpResponse, error) {
+	userID, err := s.service.CreateUser(ctx, entity.User{
+		ID:       0,
+		Name:     request.GetName(),
+		Color:    request.GetColor(),
+		Username: request.GetUsername(),
+		Password: request.GetPassword(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &timeslotsv1.SignUpResponse{Id: int64(userID)}, nil
+}
+
+func (s *authServer) SignIn(
+	ctx context.Context,
+	request *timeslotsv1.SignInRequest,
+) (*timeslotsv1.SignInResponse, error) {
+	token, err := s.service.GenerateToken(ctx, request.GetUsername(), request.GetPassword())
+	if err != nil {
+		return nil, err
+	}
+
+	return &timeslotsv1.SignInResponse{Token: token, Username: request.GetUsername()}, nil
+}
@@ -0,0 +1,231 @@
+package grpc
+
+import (
+	"context"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"main.go/internal/logging"
+)
+
+const requestIDKey = "x-request-id"
+
+var tracer = otel.Tracer("main.go/internal/controller/grpc") //nolint:gochecknoglobals // package tracer
+
+// Handler serves a call once the interceptors before it are done.
+type Handler func(ctx context.Context) error
+
+// Interceptor runs around every call, unary or streaming, as middleware does
+// around the REST routes. method is the full method name, such as
+// "/timeslots.v1.ItemService/Get". unaryInterceptor and streamInterceptor
+// turn it into the interceptors grpc.Server takes.
+type Interceptor func(ctx context.Context, method string, next Handler) error
+
+// CallObserver is told how every call went, as metrics.Middleware counts
+// the REST requests.
+type CallObserver interface {
+	ObserveCall(service, method, code string, duration time.Duration)
+}
+
+func unaryInterceptor(intercept Interceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var response any
+
+		err := intercept(ctx, info.FullMethod, func(ctx context.Context) error {
+			var err error
+			response, err = handler(ctx, req)
+
+			return err
+		})
+
+		return response, err
+	}
+}
+
+func streamInterceptor(intercept Interceptor) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return intercept(stream.Context(), info.FullMethod, func(ctx context.Context) error {
+			return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+		})
+	}
+}
+
+// contextStream hands the context the interceptors built to a streaming
+// handler, which reads it from its stream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context //nolint:containedctx // the stream's context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// splitMethod cuts "/timeslots.v1.ItemService/Get" into its service and
+// method.
+func splitMethod(fullMethod string) (string, string) {
+	serviceName, methodName, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+
+	return serviceName, methodName
+}
+
+// requestID is the REST requestID middleware for calls: it passes on the
+// x-request-id metadata entry the caller sent, or makes one up, and sends it
+// back in the response header.
+func requestID(ctx context.Context, _ string, next Handler) error {
+	id := firstMetadata(ctx, requestIDKey)
+	if !logging.ValidRequestID(id) {
+		id = logging.NewRequestID()
+	}
+
+	if err := grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, id)); err != nil {
+		logging.FromContext(ctx).Warnf("failed to set request id header: %s", err.Error())
+	}
+
+	return next(logging.WithRequestID(ctx, id))
+}
+
+// firstMetadata returns the first value of the incoming metadata entry key.
+func firstMetadata(ctx context.Context, key string) string {
+	values := metadata.ValueFromIncomingContext(ctx, key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// logCalls writes one line per call, as the REST access log does per
+// request.
+func logCalls(ctx context.Context, fullMethod string, next Handler) error {
+	start := time.Now()
+	err := next(ctx)
+	code := status.Code(err)
+
+	serviceName, methodName := splitMethod(fullMethod)
+	fields := logrus.Fields{
+		"grpc_service": serviceName,
+		"grpc_method":  methodName,
+		"grpc_code":    code.String(),
+		"latency_ms":   float64(time.Since(start).Microseconds()) / float64(time.Millisecond/time.Microsecond),
+	}
+
+	if err != nil {
+		fields["error"] = status.Convert(err).Message()
+	}
+
+	entry := logging.FromContext(ctx).WithFields(fields)
+
+	switch {
+	case code == codes.OK:
+		entry.Info("call handled")
+	case serverFault(code):
+		entry.Error("call handled")
+	default:
+		entry.Warn("call handled")
+	}
+
+	return err
+}
+
+// traceCalls starts a server span for every call, continuing a trace the
+// caller passed in the traceparent metadata entry, as tracing.Middleware
+// does for REST requests.
+func traceCalls(ctx context.Context, fullMethod string, next Handler) error {
+	incoming, _ := metadata.FromIncomingContext(ctx)
+	parent := otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(incoming))
+
+	serviceName, methodName := splitMethod(fullMethod)
+
+	spanCtx, span := tracer.Start(parent, strings.TrimPrefix(fullMethod, "/"),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.RPCSystemGRPC,
+			semconv.RPCService(serviceName),
+			semconv.RPCMethod(methodName),
+		),
+	)
+	defer span.End()
+
+	err := next(spanCtx)
+	code := status.Code(err)
+
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+
+	if serverFault(code) {
+		span.SetStatus(otelcodes.Error, code.String())
+	}
+
+	return err
+}
+
+// metadataCarrier lets the propagator read the trace context from the
+// incoming metadata.
+type metadataCarrier metadata.MD
+
+var _ propagation.TextMapCarrier = metadataCarrier{}
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// observeCalls counts and times the calls by method and code.
+func observeCalls(observer CallObserver) Interceptor {
+	return func(ctx context.Context, fullMethod string, next Handler) error {
+		start := time.Now()
+		err := next(ctx)
+
+		serviceName, methodName := splitMethod(fullMethod)
+		observer.ObserveCall(serviceName, methodName, status.Code(err).String(), time.Since(start))
+
+		return err
+	}
+}
+
+// statusErrors turns the errors of the services into statuses for the
+// interceptors before it and for the client.
+func statusErrors(ctx context.Context, _ string, next Handler) error {
+	return toStatus(next(ctx))
+}
+
+// recoverPanic turns a panic in a call into an Internal status, so one bad
+// call doesn't take the server down, as the REST recovery middleware does.
+func recoverPanic(ctx context.Context, fullMethod string, next Handler) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			logging.FromContext(ctx).Errorf("panic serving %s: %v\n%s", fullMethod, recovered, debug.Stack())
+			err = status.Error(codes.Internal, "internal error")
+		}
+	}()
+
+	return next(ctx)
+}
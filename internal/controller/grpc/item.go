@@ -0,0 +1,248 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	timeslotsv1 "main.go/api/timeslots/v1"
+	"main.go/internal/entity"
+)
+
+type TimeslotItemService interface {
+	Create(ctx context.Context, userID, listID int, input entity.TimeslotItem) (int, error)
+	GetAll(ctx context.Context, userID, listID int, filter entity.ItemsFilter) (entity.ItemsPage, error)
+	GetByID(ctx context.Context, userID, itemID int) (entity.TimeslotItem, error)
+	Delete(ctx context.Context, userID, itemID int) error
+	Update(ctx context.Context, userID, itemID int, input entity.UpdateItemInput, version int) error
+	GetByRange(ctx context.Context, input entity.ItemsByRange) (entity.ItemsPage, error)
+}
+
+type itemServer struct {
+	timeslotsv1.UnimplementedItemServiceServer
+	service TimeslotItemService
+}
+
+func newItemServer(service TimeslotItemService) *itemServer {
+	return &itemServer{UnimplementedItemServiceServer: timeslotsv1.UnimplementedItemServiceServer{}, service: service}
+}
+
+func newItem(item entity.TimeslotItem) *timeslotsv1.Item {
+	return &timeslotsv1.Item{
+		Id:          int64(item.ID),
+		Title:       item.Title,
+		Description: item.Description,
+		Start:       timestamppb.New(item.Start),
+		End:         timestamppb.New(item.End),
+		Done:        item.Done,
+		Cancelled:   item.Cancelled,
+		Username:    item.Username,
+		Color:       item.Color,
+		ListId:      int64(item.ListID),
+		Version:     int64(item.Version),
+	}
+}
+
+// asTime is the time of an optional timestamp, nil when it wasn't sent.
+func asTime(timestamp *timestamppb.Timestamp) *time.Time {
+	if timestamp == nil {
+		return nil
+	}
+
+	t := timestamp.AsTime()
+
+	return &t
+}
+
+// timeOf is the time of a required timestamp, the zero time when it wasn't
+// sent, which the validation rejects.
+func timeOf(timestamp *timestamppb.Timestamp) time.Time {
+	if timestamp == nil {
+		return time.Time{}
+	}
+
+	return timestamp.AsTime()
+}
+
+func (s *itemServer) Create(
+	ctx context.Context,
+	request *timeslotsv1.CreateItemRequest,
+) (*timeslotsv1.CreateItemResponse, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	itemID, err := s.service.Create(ctx, userID, int(request.GetListId()), entity.TimeslotItem{
+		ID:          0,
+		Title:       request.GetTitle(),
+		Description: request.GetDescription(),
+		Start:       timeOf(request.GetStart()),
+		End:         timeOf(request.GetEnd()),
+		Done:        false,
+		Cancelled:   false,
+		Username:    "",
+		Color:       "",
+		ListID:      0,
+		Version:     0,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.service.GetByID(ctx, userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &timeslotsv1.CreateItemResponse{Id: int64(itemID), Color: created.Color}, nil
+}
+
+func (s *itemServer) GetAll(
+	ctx context.Context,
+	request *timeslotsv1.GetAllItemsRequest,
+) (*timeslotsv1.GetAllItemsResponse, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := entity.ItemsFilter{
+		PageParams: entity.PageParams{Cursor: request.GetCursor(), Limit: int(request.GetLimit()), After: entity.Cursor{}},
+		Title:      request.GetTitle(),
+		Status:     request.GetStatus(),
+		Artist:     request.GetArtist(),
+	}
+
+	if err = filter.Prepare(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	page, err := s.service.GetAll(ctx, userID, int(request.GetListId()), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &timeslotsv1.GetAllItemsResponse{
+		Items:      make([]*timeslotsv1.Item, 0, len(page.Items)),
+		NextCursor: page.NextCursor,
+	}
+	for _, item := range page.Items {
+		response.Items = append(response.Items, newItem(item))
+	}
+
+	return response, nil
+}
+
+func (s *itemServer) Get(ctx context.Context, request *timeslotsv1.GetItemRequest) (*timeslotsv1.Item, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := s.service.GetByID(ctx, userID, int(request.GetId()))
+	if err != nil {
+		return nil, err
+	}
+
+	return newItem(item), nil
+}
+
+func (s *itemServer) Update(ctx context.Context, request *timeslotsv1.UpdateItemRequest) (*timeslotsv1.Item, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	itemID := int(request.GetId())
+	update := entity.UpdateItemInput{
+		Title:       request.Title,
+		Description: request.Description,
+		Start:       asTime(request.GetStart()),
+		End:         asTime(request.GetEnd()),
+		Done:        request.Done,
+		Cancelled:   request.Cancelled,
+	}
+
+	if err = s.service.Update(ctx, userID, itemID, update, int(request.GetVersion())); err != nil {
+		return nil, err
+	}
+
+	item, err := s.service.GetByID(ctx, userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newItem(item), nil
+}
+
+func (s *itemServer) Delete(
+	ctx context.Context,
+	request *timeslotsv1.DeleteItemRequest,
+) (*timeslotsv1.DeleteItemResponse, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.service.Delete(ctx, userID, int(request.GetId())); err != nil {
+		return nil, err
+	}
+
+	return &timeslotsv1.DeleteItemResponse{}, nil
+}
+
+// GetByRange streams every item in the range, walking the pages the service
+// hands out, so the client needs no cursor and never holds more than a page
+// of them in flight.
+func (s *itemServer) GetByRange(
+	request *timeslotsv1.GetByRangeRequest,
+	stream timeslotsv1.ItemService_GetByRangeServer,
+) error {
+	ctx := stream.Context()
+
+	if _, err := getUserID(ctx); err != nil {
+		return err
+	}
+
+	start, end := timeOf(request.GetStart()), timeOf(request.GetEnd())
+	if start.IsZero() || !end.After(start) {
+		return status.Error(codes.InvalidArgument, "start must be set and before end")
+	}
+
+	byRange := entity.ItemsByRange{
+		ItemsFilter: entity.ItemsFilter{
+			PageParams: entity.PageParams{Cursor: "", Limit: entity.MaxPageLimit, After: entity.Cursor{}},
+			Title:      request.GetTitle(),
+			Status:     request.GetStatus(),
+			Artist:     request.GetArtist(),
+		},
+		Start: start,
+		End:   end,
+	}
+
+	for {
+		if err := byRange.Prepare(); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		page, err := s.service.GetByRange(ctx, byRange)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range page.Items {
+			if err = stream.Send(newItem(item)); err != nil {
+				return err
+			}
+		}
+
+		if page.NextCursor == "" {
+			return nil
+		}
+
+		byRange.Cursor = page.NextCursor
+	}
+}
@@ -0,0 +1,143 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	timeslotsv1 "main.go/api/timeslots/v1"
+	"main.go/internal/entity"
+)
+
+type TimeslotListService interface {
+	Create(ctx context.Context, userID int, list entity.TimeslotsList) (int, error)
+	GetAll(ctx context.Context, userID int, filter entity.ListsFilter) (entity.ListsPage, error)
+	GetByID(ctx context.Context, userID, listID int) (entity.TimeslotsList, error)
+	Delete(ctx context.Context, userID, listID int) error
+	Update(ctx context.Context, userID, listID int, input entity.UpdateListInput, version int) error
+}
+
+type listServer struct {
+	timeslotsv1.UnimplementedListServiceServer
+	service TimeslotListService
+}
+
+func newListServer(service TimeslotListService) *listServer {
+	return &listServer{UnimplementedListServiceServer: timeslotsv1.UnimplementedListServiceServer{}, service: service}
+}
+
+func newList(list entity.TimeslotsList) *timeslotsv1.List {
+	return &timeslotsv1.List{
+		Id:          int64(list.ID),
+		Title:       list.Title,
+		Description: list.Description,
+		Version:     int64(list.Version),
+	}
+}
+
+func (s *listServer) Create(
+	ctx context.Context,
+	request *timeslotsv1.CreateListRequest,
+) (*timeslotsv1.CreateListResponse, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	listID, err := s.service.Create(ctx, userID, entity.TimeslotsList{
+		ID:          0,
+		Title:       request.GetTitle(),
+		Description: request.GetDescription(),
+		Version:     0,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &timeslotsv1.CreateListResponse{Id: int64(listID)}, nil
+}
+
+func (s *listServer) GetAll(
+	ctx context.Context,
+	request *timeslotsv1.GetAllListsRequest,
+) (*timeslotsv1.GetAllListsResponse, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := entity.ListsFilter{
+		PageParams: entity.PageParams{Cursor: request.GetCursor(), Limit: int(request.GetLimit()), After: entity.Cursor{}},
+		Title:      request.GetTitle(),
+	}
+
+	if err = filter.Prepare(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	page, err := s.service.GetAll(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &timeslotsv1.GetAllListsResponse{
+		Lists:      make([]*timeslotsv1.List, 0, len(page.Lists)),
+		NextCursor: page.NextCursor,
+	}
+	for _, list := range page.Lists {
+		response.Lists = append(response.Lists, newList(list))
+	}
+
+	return response, nil
+}
+
+func (s *listServer) Get(ctx context.Context, request *timeslotsv1.GetListRequest) (*timeslotsv1.List, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.service.GetByID(ctx, userID, int(request.GetId()))
+	if err != nil {
+		return nil, err
+	}
+
+	return newList(list), nil
+}
+
+func (s *listServer) Update(ctx context.Context, request *timeslotsv1.UpdateListRequest) (*timeslotsv1.List, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	listID := int(request.GetId())
+	update := entity.UpdateListInput{Title: request.Title, Description: request.Description}
+
+	if err = s.service.Update(ctx, userID, listID, update, int(request.GetVersion())); err != nil {
+		return nil, err
+	}
+
+	list, err := s.service.GetByID(ctx, userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newList(list), nil
+}
+
+func (s *listServer) Delete(
+	ctx context.Context,
+	request *timeslotsv1.DeleteListRequest,
+) (*timeslotsv1.DeleteListResponse, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.service.Delete(ctx, userID, int(request.GetId())); err != nil {
+		return nil, err
+	}
+
+	return &timeslotsv1.DeleteListResponse{}, nil
+}
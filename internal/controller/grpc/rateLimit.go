@@ -0,0 +1,72 @@
+package grpc
+
+import (
+	"context"
+	"math"
+	"net"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"main.go/internal/entity"
+	"main.go/internal/logging"
+)
+
+type RateLimitService interface {
+	Allow(ctx context.Context, group, key string) (entity.RateDecision, error)
+}
+
+// rateLimit takes calls from the same buckets as the REST routes: the
+// AuthService calls count against the auth group per client IP, the others
+// against the api group per user, so a client can't get around a limit by
+// switching APIs. It runs after authenticate. Calls are let through when
+// the limiter store fails, so an outage there does not take the API down.
+func rateLimit(service RateLimitService) Interceptor {
+	return func(ctx context.Context, method string, next Handler) error {
+		group, key := entity.RateLimitGroupAuth, "ip:"+clientIP(ctx)
+		if userID, ok := ctx.Value(userKey{}).(int); ok {
+			group, key = entity.RateLimitGroupAPI, "user:"+strconv.Itoa(userID)
+		}
+
+		decision, err := service.Allow(ctx, group, key)
+		if err != nil {
+			logging.FromContext(ctx).Errorf("rate limiter failed, letting call through: %s", err.Error())
+			return next(ctx)
+		}
+
+		header := metadata.Pairs("x-ratelimit-remaining", strconv.Itoa(decision.Remaining))
+
+		if !decision.Allowed {
+			header.Set("retry-after", strconv.Itoa(int(math.Ceil(decision.RetryAfter.Seconds()))))
+		}
+
+		if err = grpc.SetHeader(ctx, header); err != nil {
+			logging.FromContext(ctx).Warnf("failed to set rate limit header: %s", err.Error())
+		}
+
+		if !decision.Allowed {
+			return status.Error(codes.ResourceExhausted, "too many requests")
+		}
+
+		return next(ctx)
+	}
+}
+
+// clientIP is the address the call came from. The gRPC port is reached
+// directly, without the proxies the REST API may sit behind.
+func clientIP(ctx context.Context) string {
+	client, ok := peer.FromContext(ctx)
+	if !ok || client.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(client.Addr.String())
+	if err != nil {
+		return client.Addr.String()
+	}
+
+	return host
+}
@@ -0,0 +1,106 @@
+// Package grpc serves the auth, list and item services of
+// api/timeslots/v1 over gRPC, next to the REST API, for clients that prefer
+// typed calls. The calls go through the same request IDs, access log,
+// tracing, metrics, token check and rate limits as the REST routes, as
+// interceptors.
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	timeslotsv1 "main.go/api/timeslots/v1"
+	"main.go/internal/service"
+)
+
+// Options are the settings the gRPC server shares with the REST one.
+type Options struct {
+	Port string
+	// TLS serves the calls over TLS when set, and in the clear otherwise.
+	TLS            *tls.Config
+	MaxHeaderBytes int
+	Observer       CallObserver
+}
+
+// Server runs a grpc.Server on a listener of its own.
+type Server struct {
+	grpcServer *grpc.Server
+	port       string
+}
+
+// NewServer wires the gRPC services to the services. Every call, unary or
+// streaming, gets a request ID and an access log line, then a span and its
+// metrics; the interceptors given run next, in order, followed by the
+// panic recovery, the token check of all calls but the AuthService ones
+// and the rate limit.
+func NewServer(services *service.Service, opts Options, interceptors ...Interceptor) *Server {
+	chain := []Interceptor{requestID, logCalls, traceCalls, observeCalls(opts.Observer), statusErrors}
+	chain = append(chain, interceptors...)
+	chain = append(chain, recoverPanic, authenticate(services.Authorization), rateLimit(services.RateLimit))
+
+	unary := make([]grpc.UnaryServerInterceptor, 0, len(chain))
+	stream := make([]grpc.StreamServerInterceptor, 0, len(chain))
+
+	for _, intercept := range chain {
+		unary = append(unary, unaryInterceptor(intercept))
+		stream = append(stream, streamInterceptor(intercept))
+	}
+
+	serverOptions := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}
+
+	if opts.MaxHeaderBytes > 0 {
+		serverOptions = append(serverOptions, grpc.MaxHeaderListSize(uint32(opts.MaxHeaderBytes)))
+	}
+
+	if opts.TLS != nil {
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(opts.TLS)))
+	}
+
+	grpcServer := grpc.NewServer(serverOptions...)
+	timeslotsv1.RegisterAuthServiceServer(grpcServer, newAuthServer(services.Authorization))
+	timeslotsv1.RegisterListServiceServer(grpcServer, newListServer(services.TimeslotList))
+	timeslotsv1.RegisterItemServiceServer(grpcServer, newItemServer(services.TimeslotItem))
+
+	return &Server{grpcServer: grpcServer, port: opts.Port}
+}
+
+func (s *Server) Run() error {
+	listener, err := net.Listen("tcp", ":"+s.port)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(listener)
+}
+
+// Serve serves the calls coming in on listener until Shutdown.
+func (s *Server) Serve(listener net.Listener) error {
+	return s.grpcServer.Serve(listener)
+}
+
+// Shutdown stops taking calls and waits for the running ones, streams
+// included, to finish. Once ctx is done it cancels those left.
+func (s *Server) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		<-stopped
+
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,417 @@
+package grpc //nolint:testpackage // need to stop the grpc.Server.
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	timeslotsv1 "main.go/api/timeslots/v1"
+	"main.go/internal/entity"
+	"main.go/internal/repository"
+	"main.go/internal/service"
+)
+
+const bufferSize = 1 << 20
+
+// callRecorder is a CallObserver that keeps the calls it was told about.
+type callRecorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *callRecorder) ObserveCall(service, method, code string, _ time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = append(r.calls, service+"/"+method+" "+code)
+}
+
+func (r *callRecorder) observed() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.calls...)
+}
+
+// client calls the gRPC API through the generated clients, over an
+// in-memory connection.
+type client struct {
+	auth     timeslotsv1.AuthServiceClient
+	lists    timeslotsv1.ListServiceClient
+	items    timeslotsv1.ItemServiceClient
+	observer *callRecorder
+}
+
+func newServices(rateLimits map[string]entity.RateLimit) *service.Service {
+	return service.NewService(repository.NewMemoryRepository(), service.Config{
+		WaitlistHold:     time.Hour,
+		Booking:          service.BookingConfig{},
+		RateLimits:       rateLimits,
+		IdempotencyTTL:   time.Hour,
+		IdempotencyLease: time.Minute,
+		Recorder:         nil,
+	})
+}
+
+func newClient(t *testing.T, services *service.Service) *client {
+	t.Helper()
+
+	observer := &callRecorder{mu: sync.Mutex{}, calls: nil}
+	srv := NewServer(services, Options{Port: "", TLS: nil, MaxHeaderBytes: 0, Observer: observer})
+	listener := bufconn.Listen(bufferSize)
+
+	go func() {
+		_ = srv.Serve(listener)
+	}()
+
+	t.Cleanup(srv.grpcServer.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return &client{
+		auth:     timeslotsv1.NewAuthServiceClient(conn),
+		lists:    timeslotsv1.NewListServiceClient(conn),
+		items:    timeslotsv1.NewItemServiceClient(conn),
+		observer: observer,
+	}
+}
+
+// signIn signs up a user and returns a context whose calls carry their
+// token.
+func (c *client) signIn(t *testing.T) context.Context {
+	t.Helper()
+
+	ctx := context.Background()
+
+	_, err := c.auth.SignUp(ctx, &timeslotsv1.SignUpRequest{
+		Name: "Ann", Color: "ff0000", Username: "ann", Password: "secret",
+	})
+	require.NoError(t, err)
+
+	signedIn, err := c.auth.SignIn(ctx, &timeslotsv1.SignInRequest{Username: "ann", Password: "secret"})
+	require.NoError(t, err)
+	require.Equal(t, "ann", signedIn.GetUsername())
+
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+signedIn.GetToken())
+}
+
+func TestServer_ListsAndItems(t *testing.T) {
+	// Init deps
+	c := newClient(t, newServices(nil))
+	ctx := c.signIn(t)
+
+	day := time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)
+
+	createdList, err := c.lists.Create(ctx, &timeslotsv1.CreateListRequest{Title: "Week", Description: ""})
+	require.NoError(t, err)
+
+	createdItem, err := c.items.Create(ctx, &timeslotsv1.CreateItemRequest{
+		ListId:      createdList.GetId(),
+		Title:       "Session",
+		Description: "",
+		Start:       timestamppb.New(day.Add(10 * time.Hour)),
+		End:         timestamppb.New(day.Add(12 * time.Hour)),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "ff0000", createdItem.GetColor())
+
+	// Update
+	title := "Long session"
+	update := &timeslotsv1.UpdateItemRequest{
+		Id: createdItem.GetId(), Title: &title, Description: nil, Start: nil, End: nil, Done: nil, Cancelled: nil, Version: 1,
+	}
+
+	updated, err := c.items.Update(ctx, update)
+	require.NoError(t, err)
+	require.Equal(t, "Long session", updated.GetTitle())
+	require.True(t, updated.GetStart().AsTime().Equal(day.Add(10*time.Hour)))
+	require.Equal(t, int64(2), updated.GetVersion())
+
+	_, err = c.items.Update(ctx, update)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// Page
+	lists, err := c.lists.GetAll(ctx, &timeslotsv1.GetAllListsRequest{Cursor: "", Limit: 0, Title: ""})
+	require.NoError(t, err)
+	require.Len(t, lists.GetLists(), 1)
+	require.Equal(t, "Week", lists.GetLists()[0].GetTitle())
+
+	// Delete
+	_, err = c.items.Delete(ctx, &timeslotsv1.DeleteItemRequest{Id: createdItem.GetId()})
+	require.NoError(t, err)
+
+	_, err = c.items.Get(ctx, &timeslotsv1.GetItemRequest{Id: createdItem.GetId()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestServer_GetByRange(t *testing.T) {
+	// Init deps
+	c := newClient(t, newServices(nil))
+	ctx := c.signIn(t)
+
+	day := time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)
+
+	createdList, err := c.lists.Create(ctx, &timeslotsv1.CreateListRequest{Title: "Week", Description: ""})
+	require.NoError(t, err)
+
+	// More than a page, so the stream has to walk the cursor.
+	count := 250
+	for i := 0; i < count; i++ {
+		_, err = c.items.Create(ctx, &timeslotsv1.CreateItemRequest{
+			ListId:      createdList.GetId(),
+			Title:       "Session",
+			Description: "",
+			Start:       timestamppb.New(day.Add(time.Duration(i) * time.Minute)),
+			End:         timestamppb.New(day.Add(time.Duration(i+1) * time.Minute)),
+		})
+		require.NoError(t, err)
+	}
+
+	// Call
+	stream, err := c.items.GetByRange(ctx, &timeslotsv1.GetByRangeRequest{
+		Start: timestamppb.New(day), End: timestamppb.New(day.Add(24 * time.Hour)), Title: "", Status: "", Artist: "",
+	})
+	require.NoError(t, err)
+
+	var streamed []*timeslotsv1.Item
+
+	for {
+		item, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		require.NoError(t, err)
+
+		streamed = append(streamed, item)
+	}
+
+	// Assert
+	require.Len(t, streamed, count)
+
+	for i, item := range streamed {
+		require.True(t, item.GetStart().AsTime().Equal(day.Add(time.Duration(i)*time.Minute)), "item %d out of order", i)
+		require.Equal(t, "ann", item.GetUsername())
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	testTable := []struct {
+		name            string
+		signedIn        bool
+		header          []string
+		call            func(ctx context.Context, c *client) error
+		expectedCode    codes.Code
+		expectedMessage string
+	}{
+		{
+			name:     "No Token",
+			signedIn: false,
+			header:   nil,
+			call: func(ctx context.Context, c *client) error {
+				_, err := c.lists.Get(ctx, &timeslotsv1.GetListRequest{Id: 1})
+
+				return err
+			},
+			expectedCode:    codes.Unauthenticated,
+			expectedMessage: "empty authorization metadata",
+		},
+		{
+			name:     "Bad Token",
+			signedIn: false,
+			header:   []string{"authorization", "Bearer nonsense"},
+			call: func(ctx context.Context, c *client) error {
+				_, err := c.lists.Get(ctx, &timeslotsv1.GetListRequest{Id: 1})
+
+				return err
+			},
+			expectedCode:    codes.Unauthenticated,
+			expectedMessage: "token is malformed: token contains an invalid number of segments",
+		},
+		{
+			name:     "Stream Without Token",
+			signedIn: false,
+			header:   nil,
+			call: func(ctx context.Context, c *client) error {
+				stream, err := c.items.GetByRange(ctx, &timeslotsv1.GetByRangeRequest{
+					Start: nil, End: nil, Title: "", Status: "", Artist: "",
+				})
+				if err != nil {
+					return err
+				}
+
+				_, err = stream.Recv()
+
+				return err
+			},
+			expectedCode:    codes.Unauthenticated,
+			expectedMessage: "empty authorization metadata",
+		},
+		{
+			name:     "Wrong Credentials",
+			signedIn: false,
+			header:   nil,
+			call: func(ctx context.Context, c *client) error {
+				_, err := c.auth.SignIn(ctx, &timeslotsv1.SignInRequest{Username: "ann", Password: "wrong"})
+
+				return err
+			},
+			expectedCode:    codes.Unauthenticated,
+			expectedMessage: "invalid username or password",
+		},
+		{
+			name:     "Invalid Input",
+			signedIn: true,
+			header:   nil,
+			call: func(ctx context.Context, c *client) error {
+				_, err := c.lists.Create(ctx, &timeslotsv1.CreateListRequest{Title: "", Description: ""})
+
+				return err
+			},
+			expectedCode:    codes.InvalidArgument,
+			expectedMessage: "invalid fields: title",
+		},
+		{
+			name:     "Not Found",
+			signedIn: true,
+			header:   nil,
+			call: func(ctx context.Context, c *client) error {
+				_, err := c.lists.Get(ctx, &timeslotsv1.GetListRequest{Id: 42})
+
+				return err
+			},
+			expectedCode:    codes.NotFound,
+			expectedMessage: "list not found",
+		},
+		{
+			name:     "Empty Range",
+			signedIn: true,
+			header:   nil,
+			call: func(ctx context.Context, c *client) error {
+				stream, err := c.items.GetByRange(ctx, &timeslotsv1.GetByRangeRequest{
+					Start: nil, End: nil, Title: "", Status: "", Artist: "",
+				})
+				if err != nil {
+					return err
+				}
+
+				_, err = stream.Recv()
+
+				return err
+			},
+			expectedCode:    codes.InvalidArgument,
+			expectedMessage: "start must be set and before end",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init deps
+			c := newClient(t, newServices(nil))
+			ctx := c.signIn(t)
+
+			if !testCase.signedIn {
+				ctx = context.Background()
+			}
+
+			if testCase.header != nil {
+				ctx = metadata.AppendToOutgoingContext(ctx, testCase.header...)
+			}
+
+			// Call
+			err := testCase.call(ctx, c)
+
+			// Assert
+			require.Equal(t, testCase.expectedCode, status.Code(err), err)
+			require.Equal(t, testCase.expectedMessage, status.Convert(err).Message())
+		})
+	}
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	// Init deps
+	limit := entity.RateLimit{Requests: 1, Per: time.Hour, Burst: 3}
+	c := newClient(t, newServices(map[string]entity.RateLimit{
+		entity.RateLimitGroupAuth: limit,
+		entity.RateLimitGroupAPI:  {Requests: 1, Per: time.Hour, Burst: 1},
+	}))
+
+	// SignUp and SignIn take the auth group's tokens of the client IP.
+	ctx := c.signIn(t)
+
+	var header metadata.MD
+
+	_, err := c.auth.SignIn(context.Background(), &timeslotsv1.SignInRequest{Username: "ann", Password: "secret"})
+	require.NoError(t, err)
+
+	_, err = c.auth.SignIn(context.Background(), &timeslotsv1.SignInRequest{Username: "ann", Password: "secret"},
+		grpc.Header(&header))
+	require.Equal(t, codes.ResourceExhausted, status.Code(err), err)
+	require.Equal(t, []string{"3600"}, header.Get("retry-after"))
+
+	// The other calls take the api group's tokens of the user.
+	_, err = c.lists.GetAll(ctx, &timeslotsv1.GetAllListsRequest{Cursor: "", Limit: 0, Title: ""}, grpc.Header(&header))
+	require.NoError(t, err)
+	require.Equal(t, []string{"0"}, header.Get("x-ratelimit-remaining"))
+
+	_, err = c.lists.GetAll(ctx, &timeslotsv1.GetAllListsRequest{Cursor: "", Limit: 0, Title: ""})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err), err)
+}
+
+func TestServer_Observability(t *testing.T) {
+	// Init deps
+	c := newClient(t, newServices(nil))
+	ctx := metadata.AppendToOutgoingContext(c.signIn(t), "x-request-id", "call-1")
+
+	var header metadata.MD
+
+	// Call
+	_, err := c.lists.Get(ctx, &timeslotsv1.GetListRequest{Id: 42}, grpc.Header(&header))
+
+	// Assert
+	require.Equal(t, codes.NotFound, status.Code(err))
+	require.Equal(t, []string{"call-1"}, header.Get("x-request-id"))
+	require.Equal(t, []string{
+		"timeslots.v1.AuthService/SignUp OK",
+		"timeslots.v1.AuthService/SignIn OK",
+		"timeslots.v1.ListService/Get NotFound",
+	}, c.observer.observed())
+}
+
+func TestServer_RecoverPanic(t *testing.T) {
+	// Init deps
+	services := newServices(nil)
+	services.TimeslotList = nil
+	c := newClient(t, services)
+	ctx := c.signIn(t)
+
+	// Call
+	_, err := c.lists.Get(ctx, &timeslotsv1.GetListRequest{Id: 1})
+
+	// Assert
+	require.Equal(t, codes.Internal, status.Code(err))
+	require.Equal(t, "internal error", status.Convert(err).Message())
+
+	_, err = c.auth.SignIn(context.Background(), &timeslotsv1.SignInRequest{Username: "ann", Password: "secret"})
+	require.NoError(t, err)
+}
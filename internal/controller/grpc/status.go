@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apperrors "main.go/internal/errors"
+	"main.go/internal/service"
+)
+
+var errorKindCode = map[apperrors.Kind]codes.Code{ //nolint:gochecknoglobals // lookup table
+	apperrors.KindNotFound:        codes.NotFound,
+	apperrors.KindConflict:        codes.AlreadyExists,
+	apperrors.KindValidation:      codes.InvalidArgument,
+	apperrors.KindForbidden:       codes.PermissionDenied,
+	apperrors.KindUnauthenticated: codes.Unauthenticated,
+	apperrors.KindRateLimited:     codes.ResourceExhausted,
+	apperrors.KindUnsupported:     codes.Unimplemented,
+	apperrors.KindTimeout:         codes.DeadlineExceeded,
+}
+
+// toStatus turns the error a call ended with into a status, the gRPC
+// counterpart of the REST layer's newServiceErrorResponse. A stale version
+// fails the precondition the caller set, as 412 does over REST. Errors that
+// already are a status, such as those of grpc-go itself, pass as they are.
+func toStatus(err error) error {
+	var serviceErr *apperrors.ServiceError
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, service.ErrVersionMismatch):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.As(err, &serviceErr):
+		code, ok := errorKindCode[serviceErr.Kind]
+		if !ok {
+			code = codes.Internal
+		}
+
+		return status.Error(code, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// serverFault tells the codes that are the server's fault, the 5xx of gRPC,
+// from those the caller brought on.
+func serverFault(code codes.Code) bool {
+	switch code { //nolint:exhaustive // everything else is the client's fault
+	case codes.Unknown, codes.Internal, codes.DeadlineExceeded, codes.Unimplemented, codes.Unavailable, codes.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -9,9 +10,9 @@ import (
 
 //go:generate mockgen -source=auth.go -destination=mocks/authMock.go
 type AuthorizationService interface {
-	CreateUser(user entity.User) (int, error)
-	GenerateToken(username, password string) (string, error)
-	ParseToken(token string) (int, error)
+	CreateUser(ctx context.Context, user entity.User) (int, error)
+	GenerateToken(ctx context.Context, username, password string) (string, error)
+	ParseToken(ctx context.Context, token string) (int, error)
 }
 
 type AuthorizationHandler struct {
@@ -42,9 +43,9 @@ func (h *AuthorizationHandler) signUp(ctx *gin.Context) {
 		return
 	}
 
-	userID, err := h.service.CreateUser(input)
+	userID, err := h.service.CreateUser(ctx.Request.Context(), input)
 	if err != nil {
-		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		newServiceErrorResponse(ctx, err)
 		return
 	}
 
@@ -78,9 +79,10 @@ func (h *AuthorizationHandler) signIn(ctx *gin.Context) {
 		return
 	}
 
-	token, err := h.service.GenerateToken(input.Username, input.Password)
+	token, err := h.service.GenerateToken(ctx.Request.Context(), input.Username, input.Password)
 	if err != nil {
-		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		newServiceErrorResponse(ctx, err)
+		return
 	}
 
 	ctx.JSON(http.StatusOK, map[string]interface{}{
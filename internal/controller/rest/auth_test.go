@@ -29,25 +29,27 @@ func TestHandler_signUp(t *testing.T) {
 	}{
 		{
 			name:      "Ok",
-			inputBody: `{"username": "username", "name": "Test Name", "password": "qwerty"}`,
+			inputBody: `{"username": "username", "name": "Test Name", "color": "ff0000", "password": "qwerty"}`,
 			inputUser: entity.User{
 				ID:       0,
 				Username: "username",
 				Name:     "Test Name",
+				Color:    "ff0000",
 				Password: "qwerty",
 			},
 			mockBehavior: func(r *mock_service.MockAuthorizationService, user entity.User) {
-				r.EXPECT().CreateUser(user).Return(1, nil)
+				r.EXPECT().CreateUser(gomock.Any(), user).Return(1, nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"id":1}`,
 		},
 		{
 			name:      "Wrong Input",
-			inputBody: `{"username": "username"}`,
+			inputBody: `{"username": "username"`,
 			inputUser: entity.User{
 				ID:       0,
 				Name:     "",
+				Color:    "",
 				Username: "",
 				Password: "",
 			},
@@ -55,17 +57,37 @@ func TestHandler_signUp(t *testing.T) {
 			expectedStatusCode:   400,
 			expectedResponseBody: `{"message":"invalid input body"}`,
 		},
+		{
+			name:      "Invalid Fields",
+			inputBody: `{"username": "username", "color": "red"}`,
+			inputUser: entity.User{
+				ID:       0,
+				Name:     "",
+				Color:    "red",
+				Username: "username",
+				Password: "",
+			},
+			mockBehavior: func(r *mock_service.MockAuthorizationService, user entity.User) {
+				r.EXPECT().CreateUser(gomock.Any(), user).Return(0, user.Validate())
+			},
+			expectedStatusCode: 422,
+			expectedResponseBody: `{"code":"invalid_user","message":"invalid fields: name, color, password",` +
+				`"details":[{"field":"name","code":"required","message":"name is required"},` +
+				`{"field":"color","code":"invalid_format","message":"color must be six hex digits"},` +
+				`{"field":"password","code":"required","message":"password is required"}]}`,
+		},
 		{
 			name:      "Service Error",
-			inputBody: `{"username": "username", "name": "Test Name", "password": "qwerty"}`,
+			inputBody: `{"username": "username", "name": "Test Name", "color": "ff0000", "password": "qwerty"}`,
 			inputUser: entity.User{
 				ID:       0,
 				Username: "username",
 				Name:     "Test Name",
+				Color:    "ff0000",
 				Password: "qwerty",
 			},
 			mockBehavior: func(r *mock_service.MockAuthorizationService, user entity.User) {
-				r.EXPECT().CreateUser(user).Return(0, errors.New("something went wrong"))
+				r.EXPECT().CreateUser(gomock.Any(), user).Return(0, errors.New("something went wrong"))
 			},
 			expectedStatusCode:   500,
 			expectedResponseBody: `{"message":"something went wrong"}`,
@@ -85,8 +107,13 @@ func TestHandler_signUp(t *testing.T) {
 				Authorization: auth,
 				TimeslotList:  nil,
 				TimeslotItem:  nil,
+				Search:        nil,
+				Waitlist:      nil,
+				Booking:       nil,
+				RateLimit:     nil,
+				Idempotency:   nil,
 			}
-			handler := NewHandlers(services)
+			handler := NewHandlers(services, nil, nil)
 
 			// Init Endpoint
 			engine := gin.New()
@@ -0,0 +1,229 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"main.go/internal/entity"
+)
+
+//go:generate mockgen -source=booking.go -destination=mocks/bookingMock.go
+type BookingService interface {
+	GetArtists(ctx context.Context) ([]entity.Artist, error)
+	GetAvailability(ctx context.Context, artistID int, input entity.AvailabilityInput) ([]entity.TimeRange, error)
+	CreateRequest(ctx context.Context, request entity.BookingRequest) (int, error)
+	GetRequests(ctx context.Context, filter entity.BookingsFilter) ([]entity.BookingRequest, error)
+	ApproveRequest(ctx context.Context, userID, requestID int, input entity.ApproveBookingInput) (int, error)
+	RejectRequest(ctx context.Context, requestID int) error
+}
+
+type BookingHandler struct {
+	service BookingService
+}
+
+func NewBookingHandler(service BookingService) *BookingHandler {
+	return &BookingHandler{service: service}
+}
+
+type getArtistsResponse struct {
+	Data []entity.Artist `json:"data"`
+}
+
+// @Summary Get artists
+// @Tags public
+// @Description get artists clients can book
+// @ID get-artists
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} getArtistsResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /public/artists [get].
+func (h *BookingHandler) getArtists(ctx *gin.Context) {
+	artists, err := h.service.GetArtists(ctx.Request.Context())
+	if err != nil {
+		newServiceErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, getArtistsResponse{Data: artists})
+}
+
+type getAvailabilityResponse struct {
+	Data []entity.TimeRange `json:"data"`
+}
+
+// @Summary Get availability
+// @Tags public
+// @Description get free time of an artist within a range
+// @ID get-availability
+// @Accept  json
+// @Produce  json
+// @Param start query string true "range start"
+// @Param end query string true "range end"
+// @Success 200 {object} getAvailabilityResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /public/artists/:id/availability [get].
+func (h *BookingHandler) getAvailability(ctx *gin.Context) {
+	artistID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, "invalid id parameter")
+		return
+	}
+
+	var input entity.AvailabilityInput
+	if err = ctx.ShouldBindQuery(&input); err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	slots, err := h.service.GetAvailability(ctx.Request.Context(), artistID, input)
+	if err != nil {
+		newServiceErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, getAvailabilityResponse{Data: slots})
+}
+
+// @Summary Request booking
+// @Tags public
+// @Description ask for an appointment, staff approve or reject it later
+// @ID request-booking
+// @Accept  json
+// @Produce  json
+// @Param input body entity.BookingRequest true "booking info"
+// @Success 200 {object} statusResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 429 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /public/bookings [post].
+func (h *BookingHandler) requestBooking(ctx *gin.Context) {
+	var input entity.BookingRequest
+	if err := ctx.BindJSON(&input); err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	input.ClientIP = ctx.ClientIP()
+
+	if _, err := h.service.CreateRequest(ctx.Request.Context(), input); err != nil {
+		newServiceErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, statusResponse{Status: entity.BookingStatusRequested})
+}
+
+type getBookingRequestsResponse struct {
+	Data []entity.BookingRequest `json:"data"`
+}
+
+// @Summary Get booking requests
+// @Security ApiKeyAuth
+// @Tags bookings
+// @Description get booking requests, oldest first
+// @ID get-booking-requests
+// @Accept  json
+// @Produce  json
+// @Param artist_id query int false "only requests for this artist"
+// @Param status query string false "requested, approved or rejected"
+// @Success 200 {object} getBookingRequestsResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/bookings [get].
+func (h *BookingHandler) getBookingRequests(ctx *gin.Context) {
+	filter := entity.BookingsFilter{ArtistID: 0, Status: entity.BookingStatusRequested}
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	requests, err := h.service.GetRequests(ctx.Request.Context(), filter)
+	if err != nil {
+		newServiceErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, getBookingRequestsResponse{Data: requests})
+}
+
+// @Summary Approve booking request
+// @Security ApiKeyAuth
+// @Tags bookings
+// @Description book the request as an item of one of the user's lists
+// @ID approve-booking-request
+// @Accept  json
+// @Produce  json
+// @Param input body entity.ApproveBookingInput true "target list"
+// @Success 200 {integer} integer 1
+// @Failure 400,404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/bookings/:id/approve [post].
+func (h *BookingHandler) approveBookingRequest(ctx *gin.Context) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, "user userID not found")
+		return
+	}
+
+	requestID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, "invalid id parameter")
+		return
+	}
+
+	var input entity.ApproveBookingInput
+	if err = ctx.BindJSON(&input); err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	itemID, err := h.service.ApproveRequest(ctx.Request.Context(), userID, requestID, input)
+	if err != nil {
+		newServiceErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"id": itemID,
+	})
+}
+
+// @Summary Reject booking request
+// @Security ApiKeyAuth
+// @Tags bookings
+// @Description reject a booking request
+// @ID reject-booking-request
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} statusResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/bookings/:id/reject [post].
+func (h *BookingHandler) rejectBookingRequest(ctx *gin.Context) {
+	requestID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, "invalid id parameter")
+		return
+	}
+
+	if err = h.service.RejectRequest(ctx.Request.Context(), requestID); err != nil {
+		newServiceErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, statusResponse{Status: "ok"})
+}
@@ -0,0 +1,36 @@
+package rest
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	etagHeader    = "ETag"
+	ifMatchHeader = "If-Match"
+)
+
+func setETag(ctx *gin.Context, version int) {
+	ctx.Header(etagHeader, `"`+strconv.Itoa(version)+`"`)
+}
+
+// ifMatchVersion returns the version the client expects from the If-Match
+// header, or zero when any version will do.
+func ifMatchVersion(ctx *gin.Context) (int, error) {
+	header := strings.TrimSpace(ctx.GetHeader(ifMatchHeader))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	tag := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+
+	version, err := strconv.Atoi(tag)
+	if err != nil || version <= 0 {
+		return 0, errors.New("invalid If-Match header")
+	}
+
+	return version, nil
+}
@@ -7,6 +7,7 @@ import (
 
 	// Package docs Code generated by swaggo/swag.
 	_ "main.go/docs"
+	"main.go/internal/entity"
 	"main.go/internal/service"
 )
 
@@ -14,32 +15,74 @@ type Handlers struct {
 	*AuthorizationHandler
 	*TimeslotListHandler
 	*TimeslotItemHandler
+	*SearchHandler
+	*WaitlistHandler
+	*BookingHandler
+	*RateLimitHandler
+	*IdempotencyHandler
+	*RecoveryHandler
+	*HealthHandler
 }
 
-func NewHandlers(services *service.Service) *Handlers {
+// NewHandlers wires the handlers to the services and the readiness checks.
+// Recovered panics are reported to sink, which may be nil.
+func NewHandlers(services *service.Service, health HealthService, sink ErrorSink) *Handlers {
 	return &Handlers{
 		AuthorizationHandler: NewAuthorizationHandler(services.Authorization),
 		TimeslotListHandler:  NewTimeslotListHandler(services.TimeslotList),
 		TimeslotItemHandler:  NewTimeslotItemHandler(services.TimeslotItem),
+		SearchHandler:        NewSearchHandler(services.Search),
+		WaitlistHandler:      NewWaitlistHandler(services.Waitlist),
+		BookingHandler:       NewBookingHandler(services.Booking),
+		RateLimitHandler:     NewRateLimitHandler(services.RateLimit),
+		IdempotencyHandler:   NewIdempotencyHandler(services.Idempotency),
+		RecoveryHandler:      NewRecoveryHandler(sink),
+		HealthHandler:        NewHealthHandler(health),
 	}
 }
 
-func (h *Handlers) InitRoutes() *gin.Engine {
+// InitRoutes builds the router. Client IPs, which the rate limits and booking
+// limits key on, are only taken from X-Forwarded-For and X-Real-IP when the
+// request comes from one of trustedProxies, given as IPs or CIDRs; with none
+// the peer address is used. Middleware runs before every route, including
+// unmatched ones, after the request ID and access log. Panics are recovered
+// below it, so it sees them as 500s.
+func (h *Handlers) InitRoutes(trustedProxies []string, middleware ...gin.HandlerFunc) (*gin.Engine, error) {
 	router := gin.New()
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, err
+	}
+
+	router.Use(requestID, accessLog)
+	router.Use(middleware...)
+	router.Use(h.recoverPanic)
+
+	router.GET("/healthz", h.HealthHandler.healthz)
+	router.GET("/readyz", h.HealthHandler.readyz)
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	auth := router.Group("/auth")
+	auth := router.Group("/auth", h.rateLimit(entity.RateLimitGroupAuth))
 	{
 		auth.POST("/sign-up", h.AuthorizationHandler.signUp)
 		auth.POST("/sign-in", h.AuthorizationHandler.signIn)
 	}
 
-	api := router.Group("/api", h.userIdentity)
+	public := router.Group("/public", h.rateLimit(entity.RateLimitGroupPublic))
+	{
+		artists := public.Group("/artists")
+		{
+			artists.GET("/", h.BookingHandler.getArtists)
+			artists.GET("/:id/availability", h.BookingHandler.getAvailability)
+		}
+		public.POST("/bookings", h.BookingHandler.requestBooking)
+	}
+
+	api := router.Group("/api", h.userIdentity, h.rateLimit(entity.RateLimitGroupAPI))
 	{
 		lists := api.Group("/lists")
 		{
-			lists.POST("/", h.TimeslotListHandler.createList)
+			lists.POST("/", h.idempotent, h.TimeslotListHandler.createList)
 			lists.GET("/", h.TimeslotListHandler.getAllLists)
 			lists.GET("/:id", h.TimeslotListHandler.getListByID)
 			lists.PUT("/:id", h.TimeslotListHandler.updateList)
@@ -47,7 +90,7 @@ func (h *Handlers) InitRoutes() *gin.Engine {
 
 			items := lists.Group(":id/items")
 			{
-				items.POST("/", h.TimeslotItemHandler.createItem)
+				items.POST("/", h.idempotent, h.TimeslotItemHandler.createItem)
 				items.GET("/", h.TimeslotItemHandler.getAllItems)
 			}
 		}
@@ -58,7 +101,29 @@ func (h *Handlers) InitRoutes() *gin.Engine {
 			items.DELETE("/:id", h.TimeslotItemHandler.deleteItem)
 		}
 		api.GET("/schedule", h.TimeslotItemHandler.getItemsByRange)
+		api.GET("/search", h.SearchHandler.search)
+
+		waitlist := api.Group("/waitlist")
+		{
+			waitlist.POST("/", h.WaitlistHandler.createWaitlistEntry)
+			waitlist.GET("/", h.WaitlistHandler.getWaitlistEntries)
+			waitlist.DELETE("/:id", h.WaitlistHandler.deleteWaitlistEntry)
+
+			offers := waitlist.Group("/offers")
+			{
+				offers.GET("/", h.WaitlistHandler.getWaitlistOffers)
+				offers.POST("/:id/accept", h.WaitlistHandler.acceptWaitlistOffer)
+				offers.POST("/:id/decline", h.WaitlistHandler.declineWaitlistOffer)
+			}
+		}
+
+		bookings := api.Group("/bookings")
+		{
+			bookings.GET("/", h.BookingHandler.getBookingRequests)
+			bookings.POST("/:id/approve", h.BookingHandler.approveBookingRequest)
+			bookings.POST("/:id/reject", h.BookingHandler.rejectBookingRequest)
+		}
 	}
 
-	return router
+	return router, nil
 }
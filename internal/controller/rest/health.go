@@ -0,0 +1,49 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:generate mockgen -source=health.go -destination=mocks/healthMock.go
+type HealthService interface {
+	Ready(ctx context.Context) error
+}
+
+type HealthHandler struct {
+	service HealthService
+}
+
+func NewHealthHandler(service HealthService) *HealthHandler {
+	return &HealthHandler{service: service}
+}
+
+// @Summary Liveness
+// @Tags health
+// @Description answers as long as the process serves HTTP
+// @ID healthz
+// @Produce  json
+// @Success 200 {object} statusResponse
+// @Router /healthz [get].
+func (h *HealthHandler) healthz(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, statusResponse{Status: "ok"})
+}
+
+// @Summary Readiness
+// @Tags health
+// @Description answers 503 while the database is unreachable, a worker is stuck or the app is shutting down
+// @ID readyz
+// @Produce  json
+// @Success 200 {object} statusResponse
+// @Failure 503 {object} errorResponse
+// @Router /readyz [get].
+func (h *HealthHandler) readyz(ctx *gin.Context) {
+	if err := h.service.Ready(ctx.Request.Context()); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, errorResponse{Code: "", Message: err.Error(), Details: nil})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, statusResponse{Status: "ready"})
+}
@@ -0,0 +1,69 @@
+package rest //nolint:testpackage // need to use handler.readyz.
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/magiconair/properties/assert"
+	"go.uber.org/mock/gomock"
+	mock_service "main.go/internal/controller/rest/mocks"
+)
+
+func TestHandler_readyz(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockHealthService)
+
+	testTable := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mock_service.MockHealthService) {
+				s.EXPECT().Ready(gomock.Any()).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ready"}`,
+		},
+		{
+			name: "Not Ready",
+			mockBehavior: func(s *mock_service.MockHealthService) {
+				s.EXPECT().Ready(gomock.Any()).Return(errors.New("shutting down"))
+			},
+			expectedStatusCode:   503,
+			expectedResponseBody: `{"message":"shutting down"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init deps
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			checker := mock_service.NewMockHealthService(ctrl)
+			testCase.mockBehavior(checker)
+
+			handler := NewHealthHandler(checker)
+
+			// Test server
+			engine := gin.New()
+			engine.GET("/readyz", handler.readyz)
+
+			// Test request
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+
+			// Make request
+			engine.ServeHTTP(recorder, request)
+
+			// Assert
+			assert.Equal(t, recorder.Code, testCase.expectedStatusCode)
+			assert.Equal(t, recorder.Body.String(), testCase.expectedResponseBody)
+		})
+	}
+}
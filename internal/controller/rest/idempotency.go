@@ -0,0 +1,117 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"main.go/internal/entity"
+	"main.go/internal/logging"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	idempotentResponseContent = "application/json; charset=utf-8"
+)
+
+//go:generate mockgen -source=idempotency.go -destination=mocks/idempotencyMock.go
+type IdempotencyService interface {
+	Start(ctx context.Context, userID int, key string, request []byte) (entity.IdempotencyKey, error)
+	Finish(ctx context.Context, record entity.IdempotencyKey) error
+	Release(ctx context.Context, record entity.IdempotencyKey) error
+}
+
+type IdempotencyHandler struct {
+	service IdempotencyService
+}
+
+func NewIdempotencyHandler(service IdempotencyService) *IdempotencyHandler {
+	return &IdempotencyHandler{service: service}
+}
+
+// responseRecorder keeps a copy of the body written to the client.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// idempotent replays the stored response when a request is retried with the
+// same Idempotency-Key header. Requests without the header are handled as
+// usual. The key is released when the handlers below panic, so the retry does
+// not wait for the claim's lease to run out.
+func (h *IdempotencyHandler) idempotent(ctx *gin.Context) {
+	key := ctx.GetHeader(idempotencyKeyHeader)
+	if key == "" {
+		return
+	}
+
+	if len(key) > maxIdempotencyKeyLength {
+		newErrorResponse(ctx, http.StatusBadRequest, "idempotency key is too long")
+		return
+	}
+
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return
+	}
+
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	request := append([]byte(ctx.Request.Method+" "+ctx.Request.URL.Path+"\n"), body...)
+
+	record, err := h.service.Start(ctx.Request.Context(), userID, key, request)
+	if err != nil {
+		newServiceErrorResponse(ctx, err)
+		return
+	}
+
+	if record.Completed() {
+		ctx.Header(idempotentReplayedHeader, "true")
+		ctx.Data(record.StatusCode, idempotentResponseContent, record.Response)
+		ctx.Abort()
+
+		return
+	}
+
+	recorder := &responseRecorder{ResponseWriter: ctx.Writer, body: new(bytes.Buffer)}
+	ctx.Writer = recorder
+
+	completed := false
+
+	defer func() {
+		if completed {
+			return
+		}
+
+		if err := h.service.Release(context.WithoutCancel(ctx.Request.Context()), record); err != nil {
+			logging.FromContext(ctx.Request.Context()).Errorf("error releasing idempotency key: %s", err.Error())
+		}
+	}()
+
+	ctx.Next()
+
+	completed = true
+
+	record.StatusCode = ctx.Writer.Status()
+	record.Response = recorder.body.Bytes()
+
+	// The response has been sent, so a client hanging up now must not keep
+	// it from being stored.
+	if err = h.service.Finish(context.WithoutCancel(ctx.Request.Context()), record); err != nil {
+		logging.FromContext(ctx.Request.Context()).Errorf("error saving idempotent response: %s", err.Error())
+	}
+}
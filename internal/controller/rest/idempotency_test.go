@@ -0,0 +1,175 @@
+package rest //nolint:testpackage // need to use handler.idempotent.
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/magiconair/properties/assert"
+	"go.uber.org/mock/gomock"
+	mock_service "main.go/internal/controller/rest/mocks"
+	"main.go/internal/entity"
+	"main.go/internal/service"
+)
+
+func TestHandler_idempotent(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockIdempotencyService, request []byte)
+
+	started := entity.IdempotencyKey{
+		UserID:      1,
+		Key:         "key",
+		RequestHash: "hash",
+		StatusCode:  0,
+		Response:    nil,
+		CreatedAt:   time.Time{},
+	}
+
+	testTable := []struct {
+		name                 string
+		key                  string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedReplayed     string
+		expectedResponseBody string
+	}{
+		{
+			name:                 "No Key",
+			key:                  "",
+			mockBehavior:         func(_ *mock_service.MockIdempotencyService, _ []byte) {},
+			expectedStatusCode:   200,
+			expectedReplayed:     "",
+			expectedResponseBody: `{"id":1}`,
+		},
+		{
+			name: "First Request",
+			key:  "key",
+			mockBehavior: func(s *mock_service.MockIdempotencyService, request []byte) {
+				s.EXPECT().Start(gomock.Any(), 1, "key", request).Return(started, nil)
+
+				finished := started
+				finished.StatusCode = http.StatusOK
+				finished.Response = []byte(`{"id":1}`)
+				s.EXPECT().Finish(gomock.Any(), finished).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedReplayed:     "",
+			expectedResponseBody: `{"id":1}`,
+		},
+		{
+			name: "Replay",
+			key:  "key",
+			mockBehavior: func(s *mock_service.MockIdempotencyService, request []byte) {
+				stored := started
+				stored.StatusCode = http.StatusOK
+				stored.Response = []byte(`{"id":1}`)
+				s.EXPECT().Start(gomock.Any(), 1, "key", request).Return(stored, nil)
+			},
+			expectedStatusCode:   200,
+			expectedReplayed:     "true",
+			expectedResponseBody: `{"id":1}`,
+		},
+		{
+			name: "Key Reused",
+			key:  "key",
+			mockBehavior: func(s *mock_service.MockIdempotencyService, request []byte) {
+				s.EXPECT().Start(gomock.Any(), 1, "key", request).Return(started, service.ErrIdempotencyKeyReused)
+			},
+			expectedStatusCode:   422,
+			expectedReplayed:     "",
+			expectedResponseBody: `{"code":"idempotency_key_reused","message":"idempotency key was used with a different request"}`,
+		},
+		{
+			name: "In Progress",
+			key:  "key",
+			mockBehavior: func(s *mock_service.MockIdempotencyService, request []byte) {
+				s.EXPECT().Start(gomock.Any(), 1, "key", request).Return(started, service.ErrIdempotencyKeyInProgress)
+			},
+			expectedStatusCode:   409,
+			expectedReplayed:     "",
+			expectedResponseBody: `{"code":"idempotency_key_in_progress","message":"request with this idempotency key is still in progress"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init deps
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			body := `{"title":"title"}`
+			idempotency := mock_service.NewMockIdempotencyService(ctrl)
+			testCase.mockBehavior(idempotency, []byte("POST /api/lists/\n"+body))
+
+			handler := NewIdempotencyHandler(idempotency)
+
+			// Test server
+			engine := gin.New()
+			engine.POST("/api/lists/", func(ctx *gin.Context) {
+				ctx.Set(userCtx, 1)
+			}, handler.idempotent, func(ctx *gin.Context) {
+				ctx.JSON(http.StatusOK, map[string]interface{}{"id": 1})
+			})
+
+			// Test request
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/api/lists/", bytes.NewBufferString(body))
+			if testCase.key != "" {
+				request.Header.Set(idempotencyKeyHeader, testCase.key)
+			}
+
+			// Make request
+			engine.ServeHTTP(recorder, request)
+
+			// Assert
+			assert.Equal(t, recorder.Code, testCase.expectedStatusCode)
+			assert.Equal(t, recorder.Header().Get(idempotentReplayedHeader), testCase.expectedReplayed)
+			assert.Equal(t, recorder.Body.String(), testCase.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_idempotent_panic(t *testing.T) {
+	// Init deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	started := entity.IdempotencyKey{
+		UserID:      1,
+		Key:         "key",
+		RequestHash: "hash",
+		StatusCode:  0,
+		Response:    nil,
+		CreatedAt:   time.Time{},
+	}
+
+	idempotency := mock_service.NewMockIdempotencyService(ctrl)
+	idempotency.EXPECT().Start(gomock.Any(), 1, "key", gomock.Any()).Return(started, nil)
+	idempotency.EXPECT().Release(gomock.Any(), started).Return(nil)
+
+	handler := NewIdempotencyHandler(idempotency)
+
+	// Test server
+	engine := gin.New()
+	engine.Use(gin.CustomRecovery(func(ctx *gin.Context, _ any) {
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	engine.POST("/api/lists/", func(ctx *gin.Context) {
+		ctx.Set(userCtx, 1)
+	}, handler.idempotent, func(_ *gin.Context) {
+		panic("boom")
+	})
+
+	// Test request
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/api/lists/", bytes.NewBufferString(`{"title":"title"}`))
+	request.Header.Set(idempotencyKeyHeader, "key")
+
+	// Make request
+	engine.ServeHTTP(recorder, request)
+
+	// Assert
+	assert.Equal(t, recorder.Code, http.StatusInternalServerError)
+}
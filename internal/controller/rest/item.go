@@ -1,21 +1,24 @@
 package rest
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"main.go/internal/entity"
+	"main.go/internal/service"
 )
 
 //go:generate mockgen -source=item.go -destination=mocks/itemMock.go
 type TimeslotItemService interface {
-	Create(userID, listID int, input entity.TimeslotItem) (int, error)
-	GetAll(userID, listID int) ([]entity.TimeslotItem, error)
-	GetByID(userID, itemID int) (entity.TimeslotItem, error)
-	Delete(userID, itemID int) error
-	Update(userID, itemID int, input entity.UpdateItemInput) error
-	GetByRange(input entity.ItemsByRange) ([]entity.TimeslotItem, error)
+	Create(ctx context.Context, userID, listID int, input entity.TimeslotItem) (int, error)
+	GetAll(ctx context.Context, userID, listID int, filter entity.ItemsFilter) (entity.ItemsPage, error)
+	GetByID(ctx context.Context, userID, itemID int) (entity.TimeslotItem, error)
+	Delete(ctx context.Context, userID, itemID int) error
+	Update(ctx context.Context, userID, itemID int, input entity.UpdateItemInput, version int) error
+	GetByRange(ctx context.Context, input entity.ItemsByRange) (entity.ItemsPage, error)
 }
 
 type TimeslotItemHandler struct {
@@ -34,8 +37,10 @@ func NewTimeslotItemHandler(service TimeslotItemService) *TimeslotItemHandler {
 // @Accept  json
 // @Produce  json
 // @Param input body entity.TimeslotItem true "item info"
+// @Param Idempotency-Key header string false "key that makes retries safe"
 // @Success 200 {integer} integer 1
 // @Failure 400,404 {object} errorResponse
+// @Failure 409,422 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /api/items [post].
@@ -59,15 +64,15 @@ func (h *TimeslotItemHandler) createItem(ctx *gin.Context) {
 		return
 	}
 
-	itemID, err := h.service.Create(userID, listID, input)
+	itemID, err := h.service.Create(ctx.Request.Context(), userID, listID, input)
 	if err != nil {
-		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		newServiceErrorResponse(ctx, err)
 		return
 	}
 
-	item, err := h.service.GetByID(userID, itemID)
+	item, err := h.service.GetByID(ctx.Request.Context(), userID, itemID)
 	if err != nil {
-		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		newServiceErrorResponse(ctx, err)
 		return
 	}
 
@@ -78,7 +83,8 @@ func (h *TimeslotItemHandler) createItem(ctx *gin.Context) {
 }
 
 type getAllItemsResponse struct {
-	Data []entity.TimeslotItem `json:"data"`
+	Data       []entity.TimeslotItem `json:"data"`
+	NextCursor string                `json:"next_cursor"`
 }
 
 // @Summary Get All Items
@@ -88,6 +94,11 @@ type getAllItemsResponse struct {
 // @ID get-all-items
 // @Accept  json
 // @Produce  json
+// @Param cursor query string false "cursor from the previous page"
+// @Param limit query int false "page size"
+// @Param title query string false "title contains"
+// @Param status query string false "open or done"
+// @Param artist query string false "artist username"
 // @Success 200 {object} getAllItemsResponse
 // @Failure 400,404 {object} errorResponse
 // @Failure 500 {object} errorResponse
@@ -106,13 +117,24 @@ func (h *TimeslotItemHandler) getAllItems(ctx *gin.Context) {
 		return
 	}
 
-	items, err := h.service.GetAll(userID, listID)
+	var filter entity.ItemsFilter
+	if err = ctx.ShouldBindQuery(&filter); err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err = filter.Prepare(); err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := h.service.GetAll(ctx.Request.Context(), userID, listID, filter)
 	if err != nil {
-		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		newServiceErrorResponse(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, getAllItemsResponse{Data: items})
+	ctx.JSON(http.StatusOK, getAllItemsResponse{Data: page.Items, NextCursor: page.NextCursor})
 }
 
 // @Summary Get Item By Id
@@ -140,31 +162,40 @@ func (h *TimeslotItemHandler) getItemByID(ctx *gin.Context) {
 		return
 	}
 
-	item, err := h.service.GetByID(userID, itemID)
+	item, err := h.service.GetByID(ctx.Request.Context(), userID, itemID)
 	if err != nil {
-		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		newServiceErrorResponse(ctx, err)
 		return
 	}
 
+	setETag(ctx, item.Version)
 	ctx.JSON(http.StatusOK, item)
 }
 
 type getItemsByRangeResponse struct {
-	Data []entity.TimeslotItem `json:"data"`
+	Data       []entity.TimeslotItem `json:"data"`
+	NextCursor string                `json:"next_cursor"`
 }
 
-// @Summary Get Item By Id
+// @Summary Get Items By Range
 // @Security ApiKeyAuth
 // @Tags items
-// @Description get item by id
-// @ID get-item-by-id
+// @Description get items of all artists within a time range
+// @ID get-items-by-range
 // @Accept  json
 // @Produce  json
-// @Success 200 {object} entity.TimeslotItem
+// @Param start query string true "range start"
+// @Param end query string true "range end"
+// @Param cursor query string false "cursor from the previous page"
+// @Param limit query int false "page size"
+// @Param title query string false "title contains"
+// @Param status query string false "open or done"
+// @Param artist query string false "artist username"
+// @Success 200 {object} getItemsByRangeResponse
 // @Failure 400,404 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
-// @Router /api/items/:id [get].
+// @Router /api/schedule [get].
 func (h *TimeslotItemHandler) getItemsByRange(ctx *gin.Context) {
 	_, err := getUserID(ctx)
 	if err != nil {
@@ -179,24 +210,31 @@ func (h *TimeslotItemHandler) getItemsByRange(ctx *gin.Context) {
 		return
 	}
 
-	items, err := h.service.GetByRange(input)
+	if err = input.Prepare(); err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := h.service.GetByRange(ctx.Request.Context(), input)
 	if err != nil {
-		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		newServiceErrorResponse(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, getItemsByRangeResponse{Data: items})
+	ctx.JSON(http.StatusOK, getItemsByRangeResponse{Data: page.Items, NextCursor: page.NextCursor})
 }
 
 // @Summary Update Item
 // @Security ApiKeyAuth
 // @Tags items
 // @Description update list
-// @ID update-list
+// @ID update-item
 // @Accept  json
 // @Produce  json
+// @Param If-Match header string false "ETag of the version being updated"
 // @Success 200 {integer} integer 1
 // @Failure 400,404 {object} errorResponse
+// @Failure 412 {object} entity.TimeslotItem
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /api/items/:id [put].
@@ -212,20 +250,34 @@ func (h *TimeslotItemHandler) updateItem(ctx *gin.Context) {
 		return
 	}
 
+	version, err := ifMatchVersion(ctx)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	var input entity.UpdateItemInput
 	if err = ctx.BindJSON(&input); err != nil {
 		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	if err = h.service.Update(userID, itemID, input); err != nil {
-		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+	err = h.service.Update(ctx.Request.Context(), userID, itemID, input, version)
+	if err != nil && !errors.Is(err, service.ErrVersionMismatch) {
+		newServiceErrorResponse(ctx, err)
+		return
+	}
+
+	item, getErr := h.service.GetByID(ctx.Request.Context(), userID, itemID)
+	if getErr != nil {
+		newServiceErrorResponse(ctx, getErr)
 		return
 	}
 
-	item, err := h.service.GetByID(userID, itemID)
+	setETag(ctx, item.Version)
+
 	if err != nil {
-		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		ctx.AbortWithStatusJSON(http.StatusPreconditionFailed, item)
 		return
 	}
 
@@ -257,9 +309,9 @@ func (h *TimeslotItemHandler) deleteItem(ctx *gin.Context) {
 		return
 	}
 
-	err = h.service.Delete(userID, itemID)
+	err = h.service.Delete(ctx.Request.Context(), userID, itemID)
 	if err != nil {
-		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		newServiceErrorResponse(ctx, err)
 		return
 	}
 
@@ -1,20 +1,23 @@
 package rest
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"main.go/internal/entity"
+	"main.go/internal/service"
 )
 
 //go:generate mockgen -source=list.go -destination=mocks/listMock.go
 type TimeslotListService interface {
-	Create(userID int, list entity.TimeslotsList) (int, error)
-	GetAll(userID int) ([]entity.TimeslotsList, error)
-	GetByID(userID, listID int) (entity.TimeslotsList, error)
-	Delete(userID, listID int) error
-	Update(userID, listID int, input entity.UpdateListInput) error
+	Create(ctx context.Context, userID int, list entity.TimeslotsList) (int, error)
+	GetAll(ctx context.Context, userID int, filter entity.ListsFilter) (entity.ListsPage, error)
+	GetByID(ctx context.Context, userID, listID int) (entity.TimeslotsList, error)
+	Delete(ctx context.Context, userID, listID int) error
+	Update(ctx context.Context, userID, listID int, input entity.UpdateListInput, version int) error
 }
 
 type TimeslotListHandler struct {
@@ -33,8 +36,10 @@ func NewTimeslotListHandler(service TimeslotListService) *TimeslotListHandler {
 // @Accept  json
 // @Produce  json
 // @Param input body entity.TimeslotsList true "list info"
+// @Param Idempotency-Key header string false "key that makes retries safe"
 // @Success 200 {integer} integer 1
 // @Failure 400,404 {object} errorResponse
+// @Failure 409,422 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /api/lists [post].
@@ -51,9 +56,9 @@ func (h *TimeslotListHandler) createList(ctx *gin.Context) {
 		return
 	}
 
-	listID, err := h.service.Create(userID, input)
+	listID, err := h.service.Create(ctx.Request.Context(), userID, input)
 	if err != nil {
-		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		newServiceErrorResponse(ctx, err)
 		return
 	}
 
@@ -63,7 +68,8 @@ func (h *TimeslotListHandler) createList(ctx *gin.Context) {
 }
 
 type getAllListsResponse struct {
-	Data []entity.TimeslotsList `json:"data"`
+	Data       []entity.TimeslotsList `json:"data"`
+	NextCursor string                 `json:"next_cursor"`
 }
 
 // @Summary Get All Lists
@@ -73,6 +79,9 @@ type getAllListsResponse struct {
 // @ID get-all-lists
 // @Accept  json
 // @Produce  json
+// @Param cursor query string false "cursor from the previous page"
+// @Param limit query int false "page size"
+// @Param title query string false "title contains"
 // @Success 200 {object} getAllListsResponse
 // @Failure 400,404 {object} errorResponse
 // @Failure 500 {object} errorResponse
@@ -85,13 +94,24 @@ func (h *TimeslotListHandler) getAllLists(ctx *gin.Context) {
 		return
 	}
 
-	lists, err := h.service.GetAll(userID)
+	var filter entity.ListsFilter
+	if err = ctx.ShouldBindQuery(&filter); err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err = filter.Prepare(); err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := h.service.GetAll(ctx.Request.Context(), userID, filter)
 	if err != nil {
-		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		newServiceErrorResponse(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, getAllListsResponse{Data: lists})
+	ctx.JSON(http.StatusOK, getAllListsResponse{Data: page.Lists, NextCursor: page.NextCursor})
 }
 
 // @Summary Get List By Id
@@ -119,12 +139,13 @@ func (h *TimeslotListHandler) getListByID(ctx *gin.Context) {
 		return
 	}
 
-	list, err := h.service.GetByID(userID, listID)
+	list, err := h.service.GetByID(ctx.Request.Context(), userID, listID)
 	if err != nil {
-		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		newServiceErrorResponse(ctx, err)
 		return
 	}
 
+	setETag(ctx, list.Version)
 	ctx.JSON(http.StatusOK, list)
 }
 
@@ -135,8 +156,10 @@ func (h *TimeslotListHandler) getListByID(ctx *gin.Context) {
 // @ID update-list
 // @Accept  json
 // @Produce  json
+// @Param If-Match header string false "ETag of the version being updated"
 // @Success 200 {integer} integer 1
 // @Failure 400,404 {object} errorResponse
+// @Failure 412 {object} entity.TimeslotsList
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /api/lists/:id [put].
@@ -152,14 +175,34 @@ func (h *TimeslotListHandler) updateList(ctx *gin.Context) {
 		return
 	}
 
+	version, err := ifMatchVersion(ctx)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	var input entity.UpdateListInput
 	if err = ctx.BindJSON(&input); err != nil {
 		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	if err = h.service.Update(userID, listID, input); err != nil {
-		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+	err = h.service.Update(ctx.Request.Context(), userID, listID, input, version)
+	if errors.Is(err, service.ErrVersionMismatch) {
+		list, getErr := h.service.GetByID(ctx.Request.Context(), userID, listID)
+		if getErr != nil {
+			newServiceErrorResponse(ctx, getErr)
+			return
+		}
+
+		setETag(ctx, list.Version)
+		ctx.AbortWithStatusJSON(http.StatusPreconditionFailed, list)
+
+		return
+	}
+
+	if err != nil {
+		newServiceErrorResponse(ctx, err)
 		return
 	}
 
@@ -191,9 +234,9 @@ func (h *TimeslotListHandler) deleteList(ctx *gin.Context) {
 		return
 	}
 
-	err = h.service.Delete(userID, listID)
+	err = h.service.Delete(ctx.Request.Context(), userID, listID)
 	if err != nil {
-		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		newServiceErrorResponse(ctx, err)
 		return
 	}
 
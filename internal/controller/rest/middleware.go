@@ -30,9 +30,10 @@ func (h *Handlers) userIdentity(ctx *gin.Context) {
 		newErrorResponse(ctx, http.StatusUnauthorized, "token is empty")
 		return
 	}
-	userID, err := h.AuthorizationHandler.service.ParseToken(headerParts[1])
+	userID, err := h.AuthorizationHandler.service.ParseToken(ctx.Request.Context(), headerParts[1])
 	if err != nil {
-		newErrorResponse(ctx, http.StatusUnauthorized, err.Error())
+		newServiceErrorResponse(ctx, err)
+		return
 	}
 
 	ctx.Set(userCtx, userID)
@@ -11,6 +11,7 @@ import (
 	"github.com/magiconair/properties/assert"
 	"go.uber.org/mock/gomock"
 	mock_service "main.go/internal/controller/rest/mocks"
+	apperrors "main.go/internal/errors"
 	"main.go/internal/service"
 )
 
@@ -32,7 +33,7 @@ func TestHandler_userIdentity(t *testing.T) {
 			headerValue: "Bearer token",
 			token:       "token",
 			mockBehavior: func(s *mock_service.MockAuthorizationService, token string) {
-				s.EXPECT().ParseToken(token).Return(1, nil)
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(1, nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: "1",
@@ -89,10 +90,33 @@ func TestHandler_userIdentity(t *testing.T) {
 			headerValue: "Bearer token",
 			token:       "token",
 			mockBehavior: func(s *mock_service.MockAuthorizationService, token string) {
-				s.EXPECT().ParseToken(token).Return(0, errors.New("invalid token"))
+				s.EXPECT().ParseToken(gomock.Any(), token).
+					Return(0, apperrors.Unauthenticated("invalid_token", "token is expired", nil))
 			},
 			expectedStatusCode:   401,
-			expectedResponseBody: `{"message":"invalid token"}`,
+			expectedResponseBody: `{"code":"invalid_token","message":"token is expired"}`,
+		},
+		{
+			name:        "User Inactive",
+			headerName:  "Authorization",
+			headerValue: "Bearer token",
+			token:       "token",
+			mockBehavior: func(s *mock_service.MockAuthorizationService, token string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(0, service.ErrUserInactive)
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"code":"user_inactive","message":"user is disabled or deleted"}`,
+		},
+		{
+			name:        "Store Failure",
+			headerName:  "Authorization",
+			headerValue: "Bearer token",
+			token:       "token",
+			mockBehavior: func(s *mock_service.MockAuthorizationService, token string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(0, errors.New("connection refused"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"connection refused"}`,
 		},
 	}
 
@@ -109,8 +133,13 @@ func TestHandler_userIdentity(t *testing.T) {
 				Authorization: auth,
 				TimeslotList:  nil,
 				TimeslotItem:  nil,
+				Search:        nil,
+				Waitlist:      nil,
+				Booking:       nil,
+				RateLimit:     nil,
+				Idempotency:   nil,
 			}
-			handler := NewHandlers(services)
+			handler := NewHandlers(services, nil, nil)
 
 			// Test server
 			engine := gin.New()
@@ -10,6 +10,7 @@
 package mock_rest
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
@@ -40,46 +41,46 @@ func (m *MockAuthorizationService) EXPECT() *MockAuthorizationServiceMockRecorde
 }
 
 // CreateUser mocks base method.
-func (m *MockAuthorizationService) CreateUser(user entity.User) (int, error) {
+func (m *MockAuthorizationService) CreateUser(ctx context.Context, user entity.User) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", user)
+	ret := m.ctrl.Call(m, "CreateUser", ctx, user)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateUser indicates an expected call of CreateUser.
-func (mr *MockAuthorizationServiceMockRecorder) CreateUser(user any) *gomock.Call {
+func (mr *MockAuthorizationServiceMockRecorder) CreateUser(ctx, user any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockAuthorizationService)(nil).CreateUser), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockAuthorizationService)(nil).CreateUser), ctx, user)
 }
 
 // GenerateToken mocks base method.
-func (m *MockAuthorizationService) GenerateToken(username, password string) (string, error) {
+func (m *MockAuthorizationService) GenerateToken(ctx context.Context, username, password string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GenerateToken", username, password)
+	ret := m.ctrl.Call(m, "GenerateToken", ctx, username, password)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GenerateToken indicates an expected call of GenerateToken.
-func (mr *MockAuthorizationServiceMockRecorder) GenerateToken(username, password any) *gomock.Call {
+func (mr *MockAuthorizationServiceMockRecorder) GenerateToken(ctx, username, password any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockAuthorizationService)(nil).GenerateToken), username, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockAuthorizationService)(nil).GenerateToken), ctx, username, password)
 }
 
 // ParseToken mocks base method.
-func (m *MockAuthorizationService) ParseToken(token string) (int, error) {
+func (m *MockAuthorizationService) ParseToken(ctx context.Context, token string) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ParseToken", token)
+	ret := m.ctrl.Call(m, "ParseToken", ctx, token)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ParseToken indicates an expected call of ParseToken.
-func (mr *MockAuthorizationServiceMockRecorder) ParseToken(token any) *gomock.Call {
+func (mr *MockAuthorizationServiceMockRecorder) ParseToken(ctx, token any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseToken", reflect.TypeOf((*MockAuthorizationService)(nil).ParseToken), token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseToken", reflect.TypeOf((*MockAuthorizationService)(nil).ParseToken), ctx, token)
 }
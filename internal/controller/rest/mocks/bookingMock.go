@@ -0,0 +1,130 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: booking.go
+//
+// Generated by this command:
+//
+//	mockgen -source=booking.go -destination=mocks/bookingMock.go
+//
+
+// Package mock_rest is a generated GoMock package.
+package mock_rest
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+	entity "main.go/internal/entity"
+)
+
+// MockBookingService is a mock of BookingService interface.
+type MockBookingService struct {
+	ctrl     *gomock.Controller
+	recorder *MockBookingServiceMockRecorder
+}
+
+// MockBookingServiceMockRecorder is the mock recorder for MockBookingService.
+type MockBookingServiceMockRecorder struct {
+	mock *MockBookingService
+}
+
+// NewMockBookingService creates a new mock instance.
+func NewMockBookingService(ctrl *gomock.Controller) *MockBookingService {
+	mock := &MockBookingService{ctrl: ctrl}
+	mock.recorder = &MockBookingServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBookingService) EXPECT() *MockBookingServiceMockRecorder {
+	return m.recorder
+}
+
+// ApproveRequest mocks base method.
+func (m *MockBookingService) ApproveRequest(ctx context.Context, userID, requestID int, input entity.ApproveBookingInput) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveRequest", ctx, userID, requestID, input)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveRequest indicates an expected call of ApproveRequest.
+func (mr *MockBookingServiceMockRecorder) ApproveRequest(ctx, userID, requestID, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveRequest", reflect.TypeOf((*MockBookingService)(nil).ApproveRequest), ctx, userID, requestID, input)
+}
+
+// CreateRequest mocks base method.
+func (m *MockBookingService) CreateRequest(ctx context.Context, request entity.BookingRequest) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRequest", ctx, request)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRequest indicates an expected call of CreateRequest.
+func (mr *MockBookingServiceMockRecorder) CreateRequest(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRequest", reflect.TypeOf((*MockBookingService)(nil).CreateRequest), ctx, request)
+}
+
+// GetArtists mocks base method.
+func (m *MockBookingService) GetArtists(ctx context.Context) ([]entity.Artist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArtists", ctx)
+	ret0, _ := ret[0].([]entity.Artist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArtists indicates an expected call of GetArtists.
+func (mr *MockBookingServiceMockRecorder) GetArtists(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArtists", reflect.TypeOf((*MockBookingService)(nil).GetArtists), ctx)
+}
+
+// GetAvailability mocks base method.
+func (m *MockBookingService) GetAvailability(ctx context.Context, artistID int, input entity.AvailabilityInput) ([]entity.TimeRange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAvailability", ctx, artistID, input)
+	ret0, _ := ret[0].([]entity.TimeRange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAvailability indicates an expected call of GetAvailability.
+func (mr *MockBookingServiceMockRecorder) GetAvailability(ctx, artistID, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailability", reflect.TypeOf((*MockBookingService)(nil).GetAvailability), ctx, artistID, input)
+}
+
+// GetRequests mocks base method.
+func (m *MockBookingService) GetRequests(ctx context.Context, filter entity.BookingsFilter) ([]entity.BookingRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRequests", ctx, filter)
+	ret0, _ := ret[0].([]entity.BookingRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRequests indicates an expected call of GetRequests.
+func (mr *MockBookingServiceMockRecorder) GetRequests(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRequests", reflect.TypeOf((*MockBookingService)(nil).GetRequests), ctx, filter)
+}
+
+// RejectRequest mocks base method.
+func (m *MockBookingService) RejectRequest(ctx context.Context, requestID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectRequest", ctx, requestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RejectRequest indicates an expected call of RejectRequest.
+func (mr *MockBookingServiceMockRecorder) RejectRequest(ctx, requestID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectRequest", reflect.TypeOf((*MockBookingService)(nil).RejectRequest), ctx, requestID)
+}
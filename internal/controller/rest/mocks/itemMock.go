@@ -10,6 +10,7 @@
 package mock_rest
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
@@ -40,74 +41,89 @@ func (m *MockTimeslotItemService) EXPECT() *MockTimeslotItemServiceMockRecorder
 }
 
 // Create mocks base method.
-func (m *MockTimeslotItemService) Create(userID, listID int, input entity.TimeslotItem) (int, error) {
+func (m *MockTimeslotItemService) Create(ctx context.Context, userID, listID int, input entity.TimeslotItem) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", userID, listID, input)
+	ret := m.ctrl.Call(m, "Create", ctx, userID, listID, input)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockTimeslotItemServiceMockRecorder) Create(userID, listID, input any) *gomock.Call {
+func (mr *MockTimeslotItemServiceMockRecorder) Create(ctx, userID, listID, input any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTimeslotItemService)(nil).Create), userID, listID, input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTimeslotItemService)(nil).Create), ctx, userID, listID, input)
 }
 
 // Delete mocks base method.
-func (m *MockTimeslotItemService) Delete(userID, itemID int) error {
+func (m *MockTimeslotItemService) Delete(ctx context.Context, userID, itemID int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", userID, itemID)
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, itemID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockTimeslotItemServiceMockRecorder) Delete(userID, itemID any) *gomock.Call {
+func (mr *MockTimeslotItemServiceMockRecorder) Delete(ctx, userID, itemID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTimeslotItemService)(nil).Delete), userID, itemID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTimeslotItemService)(nil).Delete), ctx, userID, itemID)
 }
 
 // GetAll mocks base method.
-func (m *MockTimeslotItemService) GetAll(userID, listID int) ([]entity.TimeslotItem, error) {
+func (m *MockTimeslotItemService) GetAll(ctx context.Context, userID, listID int, filter entity.ItemsFilter) (entity.ItemsPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", userID, listID)
-	ret0, _ := ret[0].([]entity.TimeslotItem)
+	ret := m.ctrl.Call(m, "GetAll", ctx, userID, listID, filter)
+	ret0, _ := ret[0].(entity.ItemsPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockTimeslotItemServiceMockRecorder) GetAll(userID, listID any) *gomock.Call {
+func (mr *MockTimeslotItemServiceMockRecorder) GetAll(ctx, userID, listID, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTimeslotItemService)(nil).GetAll), userID, listID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTimeslotItemService)(nil).GetAll), ctx, userID, listID, filter)
 }
 
 // GetByID mocks base method.
-func (m *MockTimeslotItemService) GetByID(userID, itemID int) (entity.TimeslotItem, error) {
+func (m *MockTimeslotItemService) GetByID(ctx context.Context, userID, itemID int) (entity.TimeslotItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", userID, itemID)
+	ret := m.ctrl.Call(m, "GetByID", ctx, userID, itemID)
 	ret0, _ := ret[0].(entity.TimeslotItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockTimeslotItemServiceMockRecorder) GetByID(userID, itemID any) *gomock.Call {
+func (mr *MockTimeslotItemServiceMockRecorder) GetByID(ctx, userID, itemID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTimeslotItemService)(nil).GetByID), userID, itemID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTimeslotItemService)(nil).GetByID), ctx, userID, itemID)
+}
+
+// GetByRange mocks base method.
+func (m *MockTimeslotItemService) GetByRange(ctx context.Context, input entity.ItemsByRange) (entity.ItemsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByRange", ctx, input)
+	ret0, _ := ret[0].(entity.ItemsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByRange indicates an expected call of GetByRange.
+func (mr *MockTimeslotItemServiceMockRecorder) GetByRange(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByRange", reflect.TypeOf((*MockTimeslotItemService)(nil).GetByRange), ctx, input)
 }
 
 // Update mocks base method.
-func (m *MockTimeslotItemService) Update(userID, itemID int, input entity.UpdateItemInput) error {
+func (m *MockTimeslotItemService) Update(ctx context.Context, userID, itemID int, input entity.UpdateItemInput, version int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", userID, itemID, input)
+	ret := m.ctrl.Call(m, "Update", ctx, userID, itemID, input, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockTimeslotItemServiceMockRecorder) Update(userID, itemID, input any) *gomock.Call {
+func (mr *MockTimeslotItemServiceMockRecorder) Update(ctx, userID, itemID, input, version any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTimeslotItemService)(nil).Update), userID, itemID, input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTimeslotItemService)(nil).Update), ctx, userID, itemID, input, version)
 }
@@ -10,6 +10,7 @@
 package mock_rest
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
@@ -40,74 +41,74 @@ func (m *MockTimeslotListService) EXPECT() *MockTimeslotListServiceMockRecorder
 }
 
 // Create mocks base method.
-func (m *MockTimeslotListService) Create(userID int, list entity.TimeslotsList) (int, error) {
+func (m *MockTimeslotListService) Create(ctx context.Context, userID int, list entity.TimeslotsList) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", userID, list)
+	ret := m.ctrl.Call(m, "Create", ctx, userID, list)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockTimeslotListServiceMockRecorder) Create(userID, list any) *gomock.Call {
+func (mr *MockTimeslotListServiceMockRecorder) Create(ctx, userID, list any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTimeslotListService)(nil).Create), userID, list)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTimeslotListService)(nil).Create), ctx, userID, list)
 }
 
 // Delete mocks base method.
-func (m *MockTimeslotListService) Delete(userID, listID int) error {
+func (m *MockTimeslotListService) Delete(ctx context.Context, userID, listID int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", userID, listID)
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, listID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockTimeslotListServiceMockRecorder) Delete(userID, listID any) *gomock.Call {
+func (mr *MockTimeslotListServiceMockRecorder) Delete(ctx, userID, listID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTimeslotListService)(nil).Delete), userID, listID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTimeslotListService)(nil).Delete), ctx, userID, listID)
 }
 
 // GetAll mocks base method.
-func (m *MockTimeslotListService) GetAll(userID int) ([]entity.TimeslotsList, error) {
+func (m *MockTimeslotListService) GetAll(ctx context.Context, userID int, filter entity.ListsFilter) (entity.ListsPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", userID)
-	ret0, _ := ret[0].([]entity.TimeslotsList)
+	ret := m.ctrl.Call(m, "GetAll", ctx, userID, filter)
+	ret0, _ := ret[0].(entity.ListsPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockTimeslotListServiceMockRecorder) GetAll(userID any) *gomock.Call {
+func (mr *MockTimeslotListServiceMockRecorder) GetAll(ctx, userID, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTimeslotListService)(nil).GetAll), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTimeslotListService)(nil).GetAll), ctx, userID, filter)
 }
 
 // GetByID mocks base method.
-func (m *MockTimeslotListService) GetByID(userID, listID int) (entity.TimeslotsList, error) {
+func (m *MockTimeslotListService) GetByID(ctx context.Context, userID, listID int) (entity.TimeslotsList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", userID, listID)
+	ret := m.ctrl.Call(m, "GetByID", ctx, userID, listID)
 	ret0, _ := ret[0].(entity.TimeslotsList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockTimeslotListServiceMockRecorder) GetByID(userID, listID any) *gomock.Call {
+func (mr *MockTimeslotListServiceMockRecorder) GetByID(ctx, userID, listID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTimeslotListService)(nil).GetByID), userID, listID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTimeslotListService)(nil).GetByID), ctx, userID, listID)
 }
 
 // Update mocks base method.
-func (m *MockTimeslotListService) Update(userID, listID int, input entity.UpdateListInput) error {
+func (m *MockTimeslotListService) Update(ctx context.Context, userID, listID int, input entity.UpdateListInput, version int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", userID, listID, input)
+	ret := m.ctrl.Call(m, "Update", ctx, userID, listID, input, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockTimeslotListServiceMockRecorder) Update(userID, listID, input any) *gomock.Call {
+func (mr *MockTimeslotListServiceMockRecorder) Update(ctx, userID, listID, input, version any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTimeslotListService)(nil).Update), userID, listID, input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTimeslotListService)(nil).Update), ctx, userID, listID, input, version)
 }
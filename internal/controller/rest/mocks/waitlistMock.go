@@ -0,0 +1,129 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: waitlist.go
+//
+// Generated by this command:
+//
+//	mockgen -source=waitlist.go -destination=mocks/waitlistMock.go
+//
+
+// Package mock_rest is a generated GoMock package.
+package mock_rest
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+	entity "main.go/internal/entity"
+)
+
+// MockWaitlistService is a mock of WaitlistService interface.
+type MockWaitlistService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWaitlistServiceMockRecorder
+}
+
+// MockWaitlistServiceMockRecorder is the mock recorder for MockWaitlistService.
+type MockWaitlistServiceMockRecorder struct {
+	mock *MockWaitlistService
+}
+
+// NewMockWaitlistService creates a new mock instance.
+func NewMockWaitlistService(ctrl *gomock.Controller) *MockWaitlistService {
+	mock := &MockWaitlistService{ctrl: ctrl}
+	mock.recorder = &MockWaitlistServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWaitlistService) EXPECT() *MockWaitlistServiceMockRecorder {
+	return m.recorder
+}
+
+// AcceptOffer mocks base method.
+func (m *MockWaitlistService) AcceptOffer(ctx context.Context, offerID int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptOffer", ctx, offerID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptOffer indicates an expected call of AcceptOffer.
+func (mr *MockWaitlistServiceMockRecorder) AcceptOffer(ctx, offerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptOffer", reflect.TypeOf((*MockWaitlistService)(nil).AcceptOffer), ctx, offerID)
+}
+
+// CreateEntry mocks base method.
+func (m *MockWaitlistService) CreateEntry(ctx context.Context, entry entity.WaitlistEntry) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEntry", ctx, entry)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEntry indicates an expected call of CreateEntry.
+func (mr *MockWaitlistServiceMockRecorder) CreateEntry(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockWaitlistService)(nil).CreateEntry), ctx, entry)
+}
+
+// DeclineOffer mocks base method.
+func (m *MockWaitlistService) DeclineOffer(ctx context.Context, offerID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeclineOffer", ctx, offerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeclineOffer indicates an expected call of DeclineOffer.
+func (mr *MockWaitlistServiceMockRecorder) DeclineOffer(ctx, offerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclineOffer", reflect.TypeOf((*MockWaitlistService)(nil).DeclineOffer), ctx, offerID)
+}
+
+// DeleteEntry mocks base method.
+func (m *MockWaitlistService) DeleteEntry(ctx context.Context, entryID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEntry", ctx, entryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEntry indicates an expected call of DeleteEntry.
+func (mr *MockWaitlistServiceMockRecorder) DeleteEntry(ctx, entryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEntry", reflect.TypeOf((*MockWaitlistService)(nil).DeleteEntry), ctx, entryID)
+}
+
+// GetEntries mocks base method.
+func (m *MockWaitlistService) GetEntries(ctx context.Context, artistID int) ([]entity.WaitlistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntries", ctx, artistID)
+	ret0, _ := ret[0].([]entity.WaitlistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntries indicates an expected call of GetEntries.
+func (mr *MockWaitlistServiceMockRecorder) GetEntries(ctx, artistID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntries", reflect.TypeOf((*MockWaitlistService)(nil).GetEntries), ctx, artistID)
+}
+
+// GetOffers mocks base method.
+func (m *MockWaitlistService) GetOffers(ctx context.Context, artistID int) ([]entity.WaitlistOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOffers", ctx, artistID)
+	ret0, _ := ret[0].([]entity.WaitlistOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOffers indicates an expected call of GetOffers.
+func (mr *MockWaitlistServiceMockRecorder) GetOffers(ctx, artistID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOffers", reflect.TypeOf((*MockWaitlistService)(nil).GetOffers), ctx, artistID)
+}
@@ -0,0 +1,50 @@
+package rest
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"main.go/internal/entity"
+	"main.go/internal/logging"
+)
+
+//go:generate mockgen -source=rateLimit.go -destination=mocks/rateLimitMock.go
+type RateLimitService interface {
+	Allow(ctx context.Context, group, key string) (entity.RateDecision, error)
+}
+
+type RateLimitHandler struct {
+	service RateLimitService
+}
+
+func NewRateLimitHandler(service RateLimitService) *RateLimitHandler {
+	return &RateLimitHandler{service: service}
+}
+
+// rateLimit limits requests of the route group per user when userIdentity
+// has run before it and per client IP otherwise. Requests are let through
+// when the limiter store fails, so an outage there does not take the API down.
+func (h *RateLimitHandler) rateLimit(group string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := "ip:" + ctx.ClientIP()
+		if userID, ok := ctx.Get(userCtx); ok {
+			key = "user:" + strconv.Itoa(userID.(int)) //nolint:forcetypeassert // set by userIdentity
+		}
+
+		decision, err := h.service.Allow(ctx.Request.Context(), group, key)
+		if err != nil {
+			logging.FromContext(ctx.Request.Context()).Errorf("rate limiter failed, letting request through: %s", err.Error())
+			return
+		}
+
+		ctx.Header("X-RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+
+		if !decision.Allowed {
+			ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(decision.RetryAfter.Seconds()))))
+			newErrorResponse(ctx, http.StatusTooManyRequests, "too many requests")
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package rest //nolint:testpackage // need to use handler.rateLimit.
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/magiconair/properties/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	mock_service "main.go/internal/controller/rest/mocks"
+	"main.go/internal/entity"
+)
+
+func TestHandler_rateLimit(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockRateLimitService)
+
+	testTable := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedRetryAfter   string
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mock_service.MockRateLimitService) {
+				s.EXPECT().Allow(gomock.Any(), entity.RateLimitGroupAuth, "ip:192.0.2.1").
+					Return(entity.RateDecision{Allowed: true, Remaining: 4, RetryAfter: 0}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedRetryAfter:   "",
+			expectedResponseBody: "ok",
+		},
+		{
+			name: "Limited",
+			mockBehavior: func(s *mock_service.MockRateLimitService) {
+				s.EXPECT().Allow(gomock.Any(), entity.RateLimitGroupAuth, "ip:192.0.2.1").
+					Return(entity.RateDecision{Allowed: false, Remaining: 0, RetryAfter: 1500 * time.Millisecond}, nil)
+			},
+			expectedStatusCode:   429,
+			expectedRetryAfter:   "2",
+			expectedResponseBody: `{"message":"too many requests"}`,
+		},
+		{
+			name: "Store Failure",
+			mockBehavior: func(s *mock_service.MockRateLimitService) {
+				s.EXPECT().Allow(gomock.Any(), entity.RateLimitGroupAuth, "ip:192.0.2.1").
+					Return(entity.RateDecision{Allowed: false, Remaining: 0, RetryAfter: 0}, errors.New("connection refused"))
+			},
+			expectedStatusCode:   200,
+			expectedRetryAfter:   "",
+			expectedResponseBody: "ok",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init deps
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			limiter := mock_service.NewMockRateLimitService(ctrl)
+			testCase.mockBehavior(limiter)
+
+			handler := NewRateLimitHandler(limiter)
+
+			// Test server
+			engine := gin.New()
+			engine.POST("/limited", handler.rateLimit(entity.RateLimitGroupAuth), func(ctx *gin.Context) {
+				ctx.String(http.StatusOK, "ok")
+			})
+
+			// Test request
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/limited", nil)
+			request.RemoteAddr = "192.0.2.1:1234"
+
+			// Make request
+			engine.ServeHTTP(recorder, request)
+
+			// Assert
+			assert.Equal(t, recorder.Code, testCase.expectedStatusCode)
+			assert.Equal(t, recorder.Header().Get("Retry-After"), testCase.expectedRetryAfter)
+			assert.Equal(t, recorder.Body.String(), testCase.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandlers_InitRoutes_clientIP(t *testing.T) {
+	testTable := []struct {
+		name           string
+		trustedProxies []string
+		expectedKey    string
+	}{
+		{
+			name:           "Forwarded For Ignored By Default",
+			trustedProxies: nil,
+			expectedKey:    "ip:192.0.2.1",
+		},
+		{
+			name:           "Forwarded For From Trusted Proxy",
+			trustedProxies: []string{"192.0.2.0/24"},
+			expectedKey:    "ip:203.0.113.7",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init deps
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			limiter := mock_service.NewMockRateLimitService(ctrl)
+			limiter.EXPECT().Allow(gomock.Any(), entity.RateLimitGroupAuth, testCase.expectedKey).
+				Return(entity.RateDecision{Allowed: false, Remaining: 0, RetryAfter: time.Second}, nil)
+
+			handlers := &Handlers{
+				AuthorizationHandler: nil,
+				TimeslotListHandler:  nil,
+				TimeslotItemHandler:  nil,
+				SearchHandler:        nil,
+				WaitlistHandler:      nil,
+				BookingHandler:       nil,
+				RateLimitHandler:     NewRateLimitHandler(limiter),
+				IdempotencyHandler:   nil,
+				RecoveryHandler:      NewRecoveryHandler(nil),
+				HealthHandler:        nil,
+			}
+
+			router, err := handlers.InitRoutes(testCase.trustedProxies)
+			require.NoError(t, err)
+
+			// Test request
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/auth/sign-in", nil)
+			request.RemoteAddr = "192.0.2.1:1234"
+			request.Header.Set("X-Forwarded-For", "203.0.113.7")
+
+			// Make request
+			router.ServeHTTP(recorder, request)
+
+			// Assert
+			require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+		})
+	}
+}
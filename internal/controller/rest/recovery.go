@@ -0,0 +1,72 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"runtime/debug"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
+	"main.go/internal/logging"
+)
+
+// ErrorSink receives panics recovered from handlers, e.g. to forward them to
+// an error tracker.
+type ErrorSink interface {
+	ReportPanic(ctx context.Context, recovered any, stack []byte)
+}
+
+type nopErrorSink struct{}
+
+func (nopErrorSink) ReportPanic(context.Context, any, []byte) {}
+
+type RecoveryHandler struct {
+	sink ErrorSink
+}
+
+// NewRecoveryHandler builds the panic recovery. A nil sink only logs panics.
+func NewRecoveryHandler(sink ErrorSink) *RecoveryHandler {
+	if sink == nil {
+		sink = nopErrorSink{}
+	}
+
+	return &RecoveryHandler{sink: sink}
+}
+
+// recoverPanic turns a panic further down the chain into a logged stack trace
+// and a 500 errorResponse, so the middleware above it still sees the request
+// finish. Nothing is written when the client is gone or the response has
+// already started.
+func (h *RecoveryHandler) recoverPanic(ctx *gin.Context) {
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+
+		err, _ := recovered.(error)
+		if errors.Is(err, http.ErrAbortHandler) {
+			panic(recovered)
+		}
+
+		stack := debug.Stack()
+		requestCtx := ctx.Request.Context()
+
+		logging.FromContext(requestCtx).WithField("stack", string(stack)).Errorf("panic: %v", recovered)
+		h.sink.ReportPanic(requestCtx, recovered, stack)
+
+		if errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET) || ctx.Writer.Written() {
+			ctx.Abort()
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse{
+			Code:    "",
+			Message: http.StatusText(http.StatusInternalServerError),
+			Details: nil,
+		})
+	}()
+
+	ctx.Next()
+}
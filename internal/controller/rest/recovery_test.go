@@ -0,0 +1,96 @@
+package rest //nolint:testpackage // need to use handler.recoverPanic.
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+	"main.go/internal/logging"
+)
+
+type recordingSink struct {
+	recovered any
+	requestID string
+}
+
+func (s *recordingSink) ReportPanic(ctx context.Context, recovered any, _ []byte) {
+	s.recovered = recovered
+	s.requestID = logging.RequestID(ctx)
+}
+
+func TestHandler_recoverPanic(t *testing.T) {
+	testTable := []struct {
+		name                 string
+		handler              gin.HandlerFunc
+		expectedStatusCode   int
+		expectedResponseBody string
+		expectedRecovered    any
+	}{
+		{
+			name: "OK",
+			handler: func(ctx *gin.Context) {
+				ctx.String(http.StatusOK, "ok")
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: "ok",
+			expectedRecovered:    nil,
+		},
+		{
+			name: "Panic",
+			handler: func(*gin.Context) {
+				panic("boom")
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"Internal Server Error"}`,
+			expectedRecovered:    "boom",
+		},
+		{
+			name: "Panic After Write",
+			handler: func(ctx *gin.Context) {
+				ctx.String(http.StatusAccepted, "partial")
+				panic("boom")
+			},
+			expectedStatusCode:   202,
+			expectedResponseBody: "partial",
+			expectedRecovered:    "boom",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init deps
+			hook := test.NewGlobal()
+			defer hook.Reset()
+
+			sink := &recordingSink{recovered: nil, requestID: ""}
+			handler := NewRecoveryHandler(sink)
+
+			// Test server
+			engine := gin.New()
+			engine.GET("/panic", requestID, handler.recoverPanic, testCase.handler)
+
+			// Test request
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/panic", nil)
+			request.Header.Set(requestIDHeader, "req-1")
+
+			// Make request
+			engine.ServeHTTP(recorder, request)
+
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, recorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, recorder.Body.String())
+			require.Equal(t, testCase.expectedRecovered, sink.recovered)
+
+			if testCase.expectedRecovered != nil {
+				require.Equal(t, "req-1", sink.requestID)
+				require.Equal(t, "req-1", hook.LastEntry().Data["request_id"])
+				require.Contains(t, hook.LastEntry().Data["stack"], "recovery_test.go")
+			}
+		})
+	}
+}
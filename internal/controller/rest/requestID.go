@@ -0,0 +1,63 @@
+package rest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"main.go/internal/logging"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// requestID passes on the X-Request-ID the caller sent, or makes one up, and
+// puts it on the response and in the request context for the logs.
+func requestID(ctx *gin.Context) {
+	id := ctx.GetHeader(requestIDHeader)
+	if !logging.ValidRequestID(id) {
+		id = logging.NewRequestID()
+	}
+
+	ctx.Header(requestIDHeader, id)
+	ctx.Request = ctx.Request.WithContext(logging.WithRequestID(ctx.Request.Context(), id))
+
+	ctx.Next()
+}
+
+// accessLog writes one line per request once it has been handled.
+func accessLog(ctx *gin.Context) {
+	start := time.Now()
+
+	ctx.Next()
+
+	route := ctx.FullPath()
+	if route == "" {
+		route = "unmatched"
+	}
+
+	status := ctx.Writer.Status()
+	fields := logrus.Fields{
+		"method":     ctx.Request.Method,
+		"route":      route,
+		"path":       ctx.Request.URL.Path,
+		"status":     status,
+		"latency_ms": float64(time.Since(start).Microseconds()) / float64(time.Millisecond/time.Microsecond),
+		"client_ip":  ctx.ClientIP(),
+	}
+
+	if userID, ok := ctx.Get(userCtx); ok {
+		fields["user_id"] = userID
+	}
+
+	entry := logging.FromContext(ctx.Request.Context()).WithFields(fields)
+
+	switch {
+	case status >= http.StatusInternalServerError:
+		entry.Error("request handled")
+	case status >= http.StatusBadRequest:
+		entry.Warn("request handled")
+	default:
+		entry.Info("request handled")
+	}
+}
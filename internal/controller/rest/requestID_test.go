@@ -0,0 +1,115 @@
+package rest //nolint:testpackage // need to use requestID and accessLog.
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+	"main.go/internal/logging"
+)
+
+func TestRequestID(t *testing.T) {
+	testTable := []struct {
+		name            string
+		headerValue     string
+		expectGenerated bool
+	}{
+		{
+			name:            "Passed Through",
+			headerValue:     "abc-123",
+			expectGenerated: false,
+		},
+		{
+			name:            "Missing",
+			headerValue:     "",
+			expectGenerated: true,
+		},
+		{
+			name:            "Too Long",
+			headerValue:     strings.Repeat("a", logging.MaxRequestIDLength+1),
+			expectGenerated: true,
+		},
+		{
+			name:            "Control Characters",
+			headerValue:     "abc\tdef",
+			expectGenerated: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Test server
+			var seen string
+
+			engine := gin.New()
+			engine.GET("/ping", requestID, func(ctx *gin.Context) {
+				seen = logging.RequestID(ctx.Request.Context())
+				ctx.String(http.StatusOK, "ok")
+			})
+
+			// Test request
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/ping", nil)
+
+			if testCase.headerValue != "" {
+				request.Header.Set(requestIDHeader, testCase.headerValue)
+			}
+
+			// Make request
+			engine.ServeHTTP(recorder, request)
+
+			// Assert
+			got := recorder.Header().Get(requestIDHeader)
+			require.Equal(t, got, seen)
+
+			if testCase.expectGenerated {
+				require.Len(t, got, 2*logging.RequestIDBytes)
+			} else {
+				require.Equal(t, testCase.headerValue, got)
+			}
+		})
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	// Init deps
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	// Test server
+	engine := gin.New()
+	engine.Use(requestID, accessLog)
+	engine.GET("/lists/:id", func(ctx *gin.Context) {
+		ctx.Set(userCtx, 7)
+		logging.FromContext(ctx.Request.Context()).Error("repository failed")
+		ctx.String(http.StatusNotFound, "missing")
+	})
+
+	// Test request
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/lists/3", nil)
+	request.Header.Set(requestIDHeader, "req-1")
+
+	// Make request
+	engine.ServeHTTP(recorder, request)
+
+	// Assert
+	entries := hook.AllEntries()
+	require.Len(t, entries, 2)
+	require.Equal(t, "req-1", entries[0].Data["request_id"])
+
+	access := entries[1]
+	require.Equal(t, logrus.WarnLevel, access.Level)
+	require.Equal(t, "req-1", access.Data["request_id"])
+	require.Equal(t, http.MethodGet, access.Data["method"])
+	require.Equal(t, "/lists/:id", access.Data["route"])
+	require.Equal(t, "/lists/3", access.Data["path"])
+	require.Equal(t, http.StatusNotFound, access.Data["status"])
+	require.Equal(t, 7, access.Data["user_id"])
+	require.Contains(t, access.Data, "latency_ms")
+}
@@ -1,19 +1,65 @@
 package rest
 
 import (
+	"errors"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
-	"github.com/sirupsen/logrus"
+	apperrors "main.go/internal/errors"
+	"main.go/internal/logging"
 )
 
 type errorResponse struct {
-	Message string `json:"message"`
+	Code    string                 `json:"code,omitempty"`
+	Message string                 `json:"message"`
+	Details []apperrors.FieldError `json:"details,omitempty"`
 }
 
 type statusResponse struct {
 	Status string `json:"status"`
 }
 
+var errorKindStatus = map[apperrors.Kind]int{ //nolint:gochecknoglobals // lookup table
+	apperrors.KindNotFound:        http.StatusNotFound,
+	apperrors.KindConflict:        http.StatusConflict,
+	apperrors.KindValidation:      http.StatusUnprocessableEntity,
+	apperrors.KindForbidden:       http.StatusForbidden,
+	apperrors.KindUnauthenticated: http.StatusUnauthorized,
+	apperrors.KindRateLimited:     http.StatusTooManyRequests,
+	apperrors.KindUnsupported:     http.StatusNotImplemented,
+	apperrors.KindTimeout:         http.StatusGatewayTimeout,
+}
+
 func newErrorResponse(c *gin.Context, statusCode int, message string) {
-	logrus.Errorf(message)
-	c.AbortWithStatusJSON(statusCode, errorResponse{Message: message})
+	logging.FromContext(c.Request.Context()).Error(message)
+	c.AbortWithStatusJSON(statusCode, errorResponse{Code: "", Message: message, Details: nil})
+}
+
+// newServiceErrorResponse answers typed service errors with the status code
+// of their kind and their code, and anything else with 500. The client only
+// gets the message; the cause the service error wraps, such as the driver's
+// error, goes to the log.
+func newServiceErrorResponse(c *gin.Context, err error) {
+	var serviceErr *apperrors.ServiceError
+	if !errors.As(err, &serviceErr) {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	statusCode, ok := errorKindStatus[serviceErr.Kind]
+	if !ok {
+		statusCode = http.StatusInternalServerError
+	}
+
+	entry := logging.FromContext(c.Request.Context())
+	if cause := errors.Unwrap(serviceErr); cause != nil {
+		entry = entry.WithField("cause", cause.Error())
+	}
+
+	entry.Error(err.Error())
+	c.AbortWithStatusJSON(statusCode, errorResponse{
+		Code:    serviceErr.Code,
+		Message: err.Error(),
+		Details: serviceErr.Details,
+	})
 }
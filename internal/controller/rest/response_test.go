@@ -0,0 +1,95 @@
+package rest //nolint:testpackage // need to use newServiceErrorResponse.
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/magiconair/properties/assert"
+	"github.com/sirupsen/logrus/hooks/test"
+	apperrors "main.go/internal/errors"
+)
+
+func TestHandler_newServiceErrorResponse(t *testing.T) {
+	testTable := []struct {
+		name                 string
+		err                  error
+		expectedStatusCode   int
+		expectedResponseBody string
+		expectedCause        any
+	}{
+		{
+			name:                 "Not Found",
+			err:                  apperrors.NotFound("item_not_found", "item not found", sql.ErrNoRows),
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"code":"item_not_found","message":"item not found"}`,
+			expectedCause:        "sql: no rows in result set",
+		},
+		{
+			name: "Conflict",
+			err: apperrors.Conflict("color_taken", "color is already taken",
+				errors.New(`pq: duplicate key value violates unique constraint "users_color_key"`)),
+			expectedStatusCode:   409,
+			expectedResponseBody: `{"code":"color_taken","message":"color is already taken"}`,
+			expectedCause:        `pq: duplicate key value violates unique constraint "users_color_key"`,
+		},
+		{
+			name: "Wrapped Validation",
+			err: fmt.Errorf("%w: end must be after start",
+				apperrors.Validation("invalid_booking", "invalid booking request", nil)),
+			expectedStatusCode:   422,
+			expectedResponseBody: `{"code":"invalid_booking","message":"invalid booking request: end must be after start"}`,
+			expectedCause:        nil,
+		},
+		{
+			name:                 "Forbidden",
+			err:                  apperrors.Forbidden("list_forbidden", "list does not belong to the user", nil),
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"code":"list_forbidden","message":"list does not belong to the user"}`,
+			expectedCause:        nil,
+		},
+		{
+			name:                 "Unauthenticated",
+			err:                  apperrors.Unauthenticated("invalid_credentials", "invalid username or password", nil),
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"code":"invalid_credentials","message":"invalid username or password"}`,
+			expectedCause:        nil,
+		},
+		{
+			name:                 "Untyped",
+			err:                  errors.New("connection refused"),
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"connection refused"}`,
+			expectedCause:        nil,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			hook := test.NewGlobal()
+			defer hook.Reset()
+
+			// Test server
+			engine := gin.New()
+			engine.GET("/error", func(ctx *gin.Context) {
+				newServiceErrorResponse(ctx, testCase.err)
+			})
+
+			// Test request
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/error", nil)
+
+			// Make request
+			engine.ServeHTTP(recorder, request)
+
+			// Assert
+			assert.Equal(t, recorder.Code, testCase.expectedStatusCode)
+			assert.Equal(t, recorder.Body.String(), testCase.expectedResponseBody)
+			assert.Equal(t, hook.LastEntry().Data["cause"], testCase.expectedCause)
+		})
+	}
+}
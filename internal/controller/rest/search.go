@@ -0,0 +1,62 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"main.go/internal/entity"
+)
+
+//go:generate mockgen -source=search.go -destination=mocks/searchMock.go
+type SearchService interface {
+	Search(ctx context.Context, userID int, input entity.SearchInput) ([]entity.SearchResult, error)
+}
+
+type SearchHandler struct {
+	service SearchService
+}
+
+func NewSearchHandler(service SearchService) *SearchHandler {
+	return &SearchHandler{service: service}
+}
+
+type searchResponse struct {
+	Data []entity.SearchResult `json:"data"`
+}
+
+// @Summary Search
+// @Security ApiKeyAuth
+// @Tags search
+// @Description full-text search over the user's items and lists
+// @ID search
+// @Accept  json
+// @Produce  json
+// @Param q query string true "search text, words match as prefixes"
+// @Param limit query int false "max results"
+// @Success 200 {object} searchResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/search [get].
+func (h *SearchHandler) search(ctx *gin.Context) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, "user userID not found")
+		return
+	}
+
+	var input entity.SearchInput
+	if err = ctx.ShouldBindQuery(&input); err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, err := h.service.Search(ctx.Request.Context(), userID, input)
+	if err != nil {
+		newServiceErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, searchResponse{Data: results})
+}
@@ -0,0 +1,216 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"main.go/internal/entity"
+)
+
+//go:generate mockgen -source=waitlist.go -destination=mocks/waitlistMock.go
+type WaitlistService interface {
+	CreateEntry(ctx context.Context, entry entity.WaitlistEntry) (int, error)
+	GetEntries(ctx context.Context, artistID int) ([]entity.WaitlistEntry, error)
+	DeleteEntry(ctx context.Context, entryID int) error
+	GetOffers(ctx context.Context, artistID int) ([]entity.WaitlistOffer, error)
+	AcceptOffer(ctx context.Context, offerID int) (int, error)
+	DeclineOffer(ctx context.Context, offerID int) error
+}
+
+type WaitlistHandler struct {
+	service WaitlistService
+}
+
+func NewWaitlistHandler(service WaitlistService) *WaitlistHandler {
+	return &WaitlistHandler{service: service}
+}
+
+type artistQuery struct {
+	ArtistID int `form:"artist_id"`
+}
+
+// @Summary Create waitlist entry
+// @Security ApiKeyAuth
+// @Tags waitlist
+// @Description put a client on an artist's waitlist
+// @ID create-waitlist-entry
+// @Accept  json
+// @Produce  json
+// @Param input body entity.WaitlistEntry true "entry info"
+// @Success 200 {integer} integer 1
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/waitlist [post].
+func (h *WaitlistHandler) createWaitlistEntry(ctx *gin.Context) {
+	var input entity.WaitlistEntry
+	if err := ctx.BindJSON(&input); err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	entryID, err := h.service.CreateEntry(ctx.Request.Context(), input)
+	if err != nil {
+		newServiceErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"id": entryID,
+	})
+}
+
+type getWaitlistEntriesResponse struct {
+	Data []entity.WaitlistEntry `json:"data"`
+}
+
+// @Summary Get waitlist entries
+// @Security ApiKeyAuth
+// @Tags waitlist
+// @Description get waitlist entries in offering order
+// @ID get-waitlist-entries
+// @Accept  json
+// @Produce  json
+// @Param artist_id query int false "only entries of this artist"
+// @Success 200 {object} getWaitlistEntriesResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/waitlist [get].
+func (h *WaitlistHandler) getWaitlistEntries(ctx *gin.Context) {
+	var query artistQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	entries, err := h.service.GetEntries(ctx.Request.Context(), query.ArtistID)
+	if err != nil {
+		newServiceErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, getWaitlistEntriesResponse{Data: entries})
+}
+
+// @Summary Delete waitlist entry
+// @Security ApiKeyAuth
+// @Tags waitlist
+// @Description remove a client from the waitlist
+// @ID delete-waitlist-entry
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} statusResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/waitlist/:id [delete].
+func (h *WaitlistHandler) deleteWaitlistEntry(ctx *gin.Context) {
+	entryID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, "invalid id parameter")
+		return
+	}
+
+	if err = h.service.DeleteEntry(ctx.Request.Context(), entryID); err != nil {
+		newServiceErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, statusResponse{Status: "ok"})
+}
+
+type getWaitlistOffersResponse struct {
+	Data []entity.WaitlistOffer `json:"data"`
+}
+
+// @Summary Get waitlist offers
+// @Security ApiKeyAuth
+// @Tags waitlist
+// @Description get pending offers of freed slots
+// @ID get-waitlist-offers
+// @Accept  json
+// @Produce  json
+// @Param artist_id query int false "only offers of this artist"
+// @Success 200 {object} getWaitlistOffersResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/waitlist/offers [get].
+func (h *WaitlistHandler) getWaitlistOffers(ctx *gin.Context) {
+	var query artistQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	offers, err := h.service.GetOffers(ctx.Request.Context(), query.ArtistID)
+	if err != nil {
+		newServiceErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, getWaitlistOffersResponse{Data: offers})
+}
+
+// @Summary Accept waitlist offer
+// @Security ApiKeyAuth
+// @Tags waitlist
+// @Description book the held slot as a new item
+// @ID accept-waitlist-offer
+// @Accept  json
+// @Produce  json
+// @Success 200 {integer} integer 1
+// @Failure 400,404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/waitlist/offers/:id/accept [post].
+func (h *WaitlistHandler) acceptWaitlistOffer(ctx *gin.Context) {
+	offerID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, "invalid id parameter")
+		return
+	}
+
+	itemID, err := h.service.AcceptOffer(ctx.Request.Context(), offerID)
+	if err != nil {
+		newServiceErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"id": itemID,
+	})
+}
+
+// @Summary Decline waitlist offer
+// @Security ApiKeyAuth
+// @Tags waitlist
+// @Description release the held slot to the next client on the waitlist
+// @ID decline-waitlist-offer
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} statusResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/waitlist/offers/:id/decline [post].
+func (h *WaitlistHandler) declineWaitlistOffer(ctx *gin.Context) {
+	offerID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, "invalid id parameter")
+		return
+	}
+
+	err = h.service.DeclineOffer(ctx.Request.Context(), offerID)
+	if err != nil {
+		newServiceErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, statusResponse{Status: "ok"})
+}
@@ -0,0 +1,106 @@
+package entity
+
+import (
+	"errors"
+	"net/mail"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	BookingStatusRequested = "requested"
+	BookingStatusApproved  = "approved"
+	BookingStatusRejected  = "rejected"
+)
+
+// The longest texts of a booking request, in characters, as the columns of
+// booking_requests allow.
+const (
+	maxClientNameLength  = 255
+	maxClientEmailLength = 255
+	maxClientPhoneLength = 64
+	maxReferenceLength   = 255
+)
+
+// The stores return these when a booking request can't be approved into the
+// list asked for.
+var (
+	ErrBookingSlotTaken   = errors.New("slot overlaps a booked item") //nolint:gochecknoglobals // sentinel error
+	ErrBookingListForeign = errors.New("list is not the artist's")    //nolint:gochecknoglobals // sentinel error
+)
+
+// Artist is the public view of a user clients can book.
+type Artist struct {
+	ID    int    `json:"id"    db:"id"`
+	Name  string `json:"name"  db:"name"`
+	Color string `json:"color" db:"color"`
+}
+
+type TimeRange struct {
+	Start time.Time `json:"start" db:"beginning"`
+	End   time.Time `json:"end"   db:"finish"`
+}
+
+type AvailabilityInput struct {
+	Start time.Time `form:"start" binding:"required"`
+	End   time.Time `form:"end"   binding:"required"`
+}
+
+// BookingRequest is an appointment a client asked for through the public
+// portal. Website is a honeypot that real clients never see or fill in.
+type BookingRequest struct {
+	ID          int       `json:"id"                db:"id"`
+	ArtistID    int       `json:"artist_id"         db:"artist_id"    binding:"required"`
+	ClientName  string    `json:"client_name"       db:"client_name"  binding:"required"`
+	ClientEmail string    `json:"client_email"      db:"client_email" binding:"required"`
+	ClientPhone string    `json:"client_phone"      db:"client_phone"`
+	Reference   string    `json:"reference"         db:"reference"`
+	Start       time.Time `json:"start"             db:"beginning"    binding:"required"`
+	End         time.Time `json:"end"               db:"finish"       binding:"required"`
+	Status      string    `json:"status"            db:"status"`
+	ItemID      *int      `json:"item_id,omitempty" db:"item_id"`
+	ClientIP    string    `json:"-"                 db:"client_ip"`
+	Website     string    `json:"website,omitempty" db:"-"`
+	CreatedAt   time.Time `json:"created_at"        db:"created_at"`
+}
+
+func (r *BookingRequest) Validate() error {
+	if utf8.RuneCountInString(r.ClientName) > maxClientNameLength {
+		return errors.New("client name is too long")
+	}
+
+	if utf8.RuneCountInString(r.ClientEmail) > maxClientEmailLength {
+		return errors.New("client email is too long")
+	}
+
+	if _, err := mail.ParseAddress(r.ClientEmail); err != nil {
+		return errors.New("invalid client email")
+	}
+
+	if utf8.RuneCountInString(r.ClientPhone) > maxClientPhoneLength {
+		return errors.New("client phone is too long")
+	}
+
+	if utf8.RuneCountInString(r.Reference) > maxReferenceLength {
+		return errors.New("reference description is too long")
+	}
+
+	if !r.End.After(r.Start) {
+		return errors.New("end must be after start")
+	}
+
+	if !r.Start.After(time.Now()) {
+		return errors.New("start must be in the future")
+	}
+
+	return nil
+}
+
+type BookingsFilter struct {
+	ArtistID int    `form:"artist_id"`
+	Status   string `form:"status"`
+}
+
+type ApproveBookingInput struct {
+	ListID int `json:"list_id" binding:"required"`
+}
@@ -0,0 +1,112 @@
+package entity_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"main.go/internal/entity"
+)
+
+func TestBookingRequest_Validate(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour)
+
+	newRequest := func() entity.BookingRequest {
+		return entity.BookingRequest{
+			ID:          0,
+			ArtistID:    1,
+			ClientName:  "Maria",
+			ClientEmail: "maria@example.com",
+			ClientPhone: "+100000000",
+			Reference:   "dragon",
+			Start:       start,
+			End:         start.Add(2 * time.Hour),
+			Status:      "",
+			ItemID:      nil,
+			ClientIP:    "",
+			Website:     "",
+			CreatedAt:   time.Time{},
+		}
+	}
+
+	testTable := []struct {
+		name          string
+		change        func(request *entity.BookingRequest)
+		expectedError string
+	}{
+		{
+			name:          "OK",
+			change:        func(*entity.BookingRequest) {},
+			expectedError: "",
+		},
+		{
+			name: "Texts Of Column Length",
+			change: func(request *entity.BookingRequest) {
+				request.ClientName = strings.Repeat("ü", 255)
+				request.ClientEmail = strings.Repeat("a", 243) + "@example.com"
+				request.ClientPhone = strings.Repeat("1", 64)
+				request.Reference = strings.Repeat("ü", 255)
+			},
+			expectedError: "",
+		},
+		{
+			name:          "Client Name Too Long",
+			change:        func(request *entity.BookingRequest) { request.ClientName = strings.Repeat("a", 256) },
+			expectedError: "client name is too long",
+		},
+		{
+			name: "Client Email Too Long",
+			change: func(request *entity.BookingRequest) {
+				request.ClientEmail = strings.Repeat("a", 244) + "@example.com"
+			},
+			expectedError: "client email is too long",
+		},
+		{
+			name:          "Client Email Invalid",
+			change:        func(request *entity.BookingRequest) { request.ClientEmail = "maria" },
+			expectedError: "invalid client email",
+		},
+		{
+			name:          "Client Phone Too Long",
+			change:        func(request *entity.BookingRequest) { request.ClientPhone = strings.Repeat("1", 65) },
+			expectedError: "client phone is too long",
+		},
+		{
+			name:          "Reference Too Long",
+			change:        func(request *entity.BookingRequest) { request.Reference = strings.Repeat("a", 256) },
+			expectedError: "reference description is too long",
+		},
+		{
+			name:          "End Before Start",
+			change:        func(request *entity.BookingRequest) { request.End = request.Start },
+			expectedError: "end must be after start",
+		},
+		{
+			name: "Start In The Past",
+			change: func(request *entity.BookingRequest) {
+				request.Start = time.Now().Add(-time.Hour)
+				request.End = time.Now().Add(time.Hour)
+			},
+			expectedError: "start must be in the future",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init deps
+			request := newRequest()
+			testCase.change(&request)
+
+			// Call
+			err := request.Validate()
+
+			// Assert
+			if testCase.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, testCase.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// IdempotencyKey remembers the response to a request sent with an
+// Idempotency-Key header. StatusCode stays zero while the first request is
+// still being handled.
+type IdempotencyKey struct {
+	UserID      int       `db:"user_id"`
+	Key         string    `db:"key"`
+	RequestHash string    `db:"request_hash"`
+	StatusCode  int       `db:"status_code"`
+	Response    []byte    `db:"response"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+func (k IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
@@ -0,0 +1,155 @@
+package entity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+const (
+	ItemStatusOpen      = "open"
+	ItemStatusDone      = "done"
+	ItemStatusCancelled = "cancelled"
+)
+
+// Cursor marks the last row of a page. Items are ordered by (Start, ID),
+// lists only by ID, so Start stays zero for list cursors.
+type Cursor struct {
+	Start time.Time `json:"s,omitempty"`
+	ID    int       `json:"id"`
+}
+
+func (c Cursor) IsZero() bool {
+	return c.ID == 0
+}
+
+func (c Cursor) Encode() string {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(value string) (Cursor, error) {
+	var cursor Cursor
+
+	if value == "" {
+		return cursor, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor, errors.New("invalid cursor")
+	}
+
+	if err = json.Unmarshal(raw, &cursor); err != nil || cursor.ID <= 0 {
+		return Cursor{}, errors.New("invalid cursor")
+	}
+
+	return cursor, nil
+}
+
+type PageParams struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+	After  Cursor `form:"-"`
+}
+
+// Prepare decodes the cursor and clamps the limit to sane bounds.
+func (p *PageParams) Prepare() error {
+	if p.Limit < 0 {
+		return errors.New("limit must not be negative")
+	}
+
+	if p.Limit == 0 {
+		p.Limit = DefaultPageLimit
+	}
+
+	if p.Limit > MaxPageLimit {
+		p.Limit = MaxPageLimit
+	}
+
+	after, err := DecodeCursor(p.Cursor)
+	if err != nil {
+		return err
+	}
+
+	p.After = after
+
+	return nil
+}
+
+type ListsFilter struct {
+	PageParams
+	Title string `form:"title"`
+}
+
+func (f *ListsFilter) Prepare() error {
+	return f.PageParams.Prepare()
+}
+
+type ItemsFilter struct {
+	PageParams
+	Title  string `form:"title"`
+	Status string `form:"status"`
+	Artist string `form:"artist"`
+}
+
+func (f *ItemsFilter) Prepare() error {
+	switch f.Status {
+	case "", ItemStatusOpen, ItemStatusDone, ItemStatusCancelled:
+	default:
+		return errors.New("unknown status filter")
+	}
+
+	return f.PageParams.Prepare()
+}
+
+type ListsPage struct {
+	Lists      []TimeslotsList
+	NextCursor string
+}
+
+// NewListsPage trims lists fetched with one extra row down to limit and
+// sets NextCursor when that extra row was present.
+func NewListsPage(lists []TimeslotsList, limit int) ListsPage {
+	if limit <= 0 || len(lists) <= limit {
+		return ListsPage{Lists: lists, NextCursor: ""}
+	}
+
+	lists = lists[:limit]
+
+	return ListsPage{
+		Lists:      lists,
+		NextCursor: Cursor{Start: time.Time{}, ID: lists[limit-1].ID}.Encode(),
+	}
+}
+
+type ItemsPage struct {
+	Items      []TimeslotItem
+	NextCursor string
+}
+
+// NewItemsPage trims items fetched with one extra row down to limit and
+// sets NextCursor when that extra row was present.
+func NewItemsPage(items []TimeslotItem, limit int) ItemsPage {
+	if limit <= 0 || len(items) <= limit {
+		return ItemsPage{Items: items, NextCursor: ""}
+	}
+
+	items = items[:limit]
+	last := items[limit-1]
+
+	return ItemsPage{
+		Items:      items,
+		NextCursor: Cursor{Start: last.Start, ID: last.ID}.Encode(),
+	}
+}
@@ -0,0 +1,39 @@
+package entity
+
+import "time"
+
+const (
+	RateLimitGroupAuth   = "auth"
+	RateLimitGroupPublic = "public"
+	RateLimitGroupAPI    = "api"
+)
+
+// RateLimit is a token bucket that holds up to Burst tokens and refills
+// Requests tokens every Per.
+type RateLimit struct {
+	Requests int           `mapstructure:"requests"`
+	Per      time.Duration `mapstructure:"per"`
+	Burst    int           `mapstructure:"burst"`
+}
+
+// RefillRate returns how many tokens the bucket gains per second.
+func (l RateLimit) RefillRate() float64 {
+	return float64(l.Requests) / l.Per.Seconds()
+}
+
+type RateDecision struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// NewRateDecision turns the tokens left in a bucket after a take into a
+// decision, waiting for one whole token when the take was refused.
+func NewRateDecision(allowed bool, tokens float64, limit RateLimit) RateDecision {
+	decision := RateDecision{Allowed: allowed, Remaining: int(tokens), RetryAfter: 0}
+	if !allowed {
+		decision.RetryAfter = time.Duration((1 - tokens) / limit.RefillRate() * float64(time.Second))
+	}
+
+	return decision
+}
@@ -0,0 +1,22 @@
+package entity
+
+const (
+	SearchKindItem    = "item"
+	SearchKindList    = "list"
+	SearchKindBooking = "booking"
+)
+
+type SearchInput struct {
+	Query string `form:"q"     binding:"required"`
+	Limit int    `form:"limit"`
+}
+
+// SearchResult is one hit of a search. Snippet is HTML: the matching text,
+// escaped, with the matches in <mark> tags.
+type SearchResult struct {
+	Kind    string  `json:"type"    db:"kind"`
+	ID      int     `json:"id"      db:"id"`
+	Title   string  `json:"title"   db:"title"`
+	Snippet string  `json:"snippet" db:"snippet"`
+	Rank    float64 `json:"rank"    db:"rank"`
+}
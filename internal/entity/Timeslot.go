@@ -1,15 +1,32 @@
 package entity
 
 import (
-	"errors"
 	"reflect"
 	"time"
+
+	apperrors "main.go/internal/errors"
 )
 
+var errUpdateHasNoValues = apperrors.Validation( //nolint:gochecknoglobals // sentinel error
+	"empty_update", "update structure has no values", nil)
+
 type TimeslotsList struct {
 	ID          int    `db:"id"          json:"id"`
-	Title       string `db:"title"       json:"title"       binding:"required"`
+	Title       string `db:"title"       json:"title"`
 	Description string `db:"description" json:"description"`
+	Version     int    `db:"version"     json:"version"`
+}
+
+func (l *TimeslotsList) Validate() error {
+	var fields fieldErrors
+
+	if fields.required("title", l.Title) {
+		fields.maxLength("title", l.Title, maxTextLength)
+	}
+
+	fields.maxLength("description", l.Description, maxTextLength)
+
+	return fields.err("invalid_list")
 }
 
 type UsersList struct {
@@ -19,17 +36,34 @@ type UsersList struct {
 }
 
 type TimeslotItem struct {
-	ID          int       `json:"id"          db:"id"`
-	Title       string    `json:"title"       db:"title"       binding:"required"`
-	Description string    `json:"description" db:"description"`
-	Start       time.Time `json:"start"       db:"beginning"   binding:"required"`
-	End         time.Time `json:"end"         db:"finish"      binding:"required"`
-	Done        bool      `json:"done"        db:"done"`
-	Username    string    `json:"username"    db:"username"`
-	Color       string    `json:"color"       db:"color"`
+	ID          int       `json:"id"                db:"id"`
+	Title       string    `json:"title"             db:"title"`
+	Description string    `json:"description"       db:"description"`
+	Start       time.Time `json:"start"             db:"beginning"`
+	End         time.Time `json:"end"               db:"finish"`
+	Done        bool      `json:"done"              db:"done"`
+	Cancelled   bool      `json:"cancelled"         db:"cancelled"`
+	Username    string    `json:"username"          db:"username"`
+	Color       string    `json:"color"             db:"color"`
+	ListID      int       `json:"list_id,omitempty" db:"list_id"`
+	Version     int       `json:"version"           db:"version"`
+}
+
+func (i *TimeslotItem) Validate() error {
+	var fields fieldErrors
+
+	if fields.required("title", i.Title) {
+		fields.maxLength("title", i.Title, maxTextLength)
+	}
+
+	fields.maxLength("description", i.Description, maxTextLength)
+	fields.timeRange(i.Start, i.End)
+
+	return fields.err("invalid_item")
 }
 
 type ItemsByRange struct {
+	ItemsFilter
 	Start time.Time `form:"start"`
 	End   time.Time `form:"end"`
 }
@@ -46,14 +80,21 @@ type UpdateListInput struct {
 }
 
 func (i *UpdateListInput) Validate() error {
-	val := reflect.ValueOf(i).Elem()
-	for j := 0; j < val.NumField(); j++ {
-		if !val.Field(j).IsNil() {
-			return nil
-		}
+	if !hasValues(i) {
+		return errUpdateHasNoValues
 	}
 
-	return errors.New("update structure has no values")
+	var fields fieldErrors
+
+	if i.Title != nil && fields.required("title", *i.Title) {
+		fields.maxLength("title", *i.Title, maxTextLength)
+	}
+
+	if i.Description != nil {
+		fields.maxLength("description", *i.Description, maxTextLength)
+	}
+
+	return fields.err("invalid_list_update")
 }
 
 type UpdateItemInput struct {
@@ -62,15 +103,59 @@ type UpdateItemInput struct {
 	Start       *time.Time `json:"start"       db:"beginning"`
 	End         *time.Time `json:"end"         db:"finish"`
 	Done        *bool      `json:"done"        db:"done"`
+	Cancelled   *bool      `json:"cancelled"   db:"cancelled"`
 }
 
 func (i *UpdateItemInput) Validate() error {
-	val := reflect.ValueOf(i).Elem()
+	if !hasValues(i) {
+		return errUpdateHasNoValues
+	}
+
+	var fields fieldErrors
+
+	if i.Title != nil && fields.required("title", *i.Title) {
+		fields.maxLength("title", *i.Title, maxTextLength)
+	}
+
+	if i.Description != nil {
+		fields.maxLength("description", *i.Description, maxTextLength)
+	}
+
+	if i.Start != nil && i.End != nil {
+		fields.timeRange(*i.Start, *i.End)
+	}
+
+	return fields.err("invalid_item_update")
+}
+
+// ValidateRange checks the item's time range after an update that changes
+// only one of its ends.
+func (i *UpdateItemInput) ValidateRange(current TimeslotItem) error {
+	start, end := current.Start, current.End
+	if i.Start != nil {
+		start = *i.Start
+	}
+
+	if i.End != nil {
+		end = *i.End
+	}
+
+	var fields fieldErrors
+
+	fields.timeRange(start, end)
+
+	return fields.err("invalid_item_update")
+}
+
+// hasValues reports whether an update input with pointer fields sets any
+// of them.
+func hasValues(input interface{}) bool {
+	val := reflect.ValueOf(input).Elem()
 	for j := 0; j < val.NumField(); j++ {
 		if !val.Field(j).IsNil() {
-			return nil
+			return true
 		}
 	}
 
-	return errors.New("update structure has no values")
+	return false
 }
@@ -0,0 +1,185 @@
+package entity_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"main.go/internal/entity"
+)
+
+func TestTimeslotsList_Validate(t *testing.T) {
+	testTable := []struct {
+		name           string
+		list           entity.TimeslotsList
+		expectedFields [][2]string
+	}{
+		{
+			name:           "OK",
+			list:           entity.TimeslotsList{ID: 0, Title: "Week", Description: "", Version: 0},
+			expectedFields: nil,
+		},
+		{
+			name:           "Title Missing",
+			list:           entity.TimeslotsList{ID: 0, Title: "", Description: "", Version: 0},
+			expectedFields: [][2]string{{"title", entity.FieldCodeRequired}},
+		},
+		{
+			name: "Texts Of 255 Characters",
+			list: entity.TimeslotsList{
+				ID:          0,
+				Title:       strings.Repeat("a", 255),
+				Description: strings.Repeat("ü", 255),
+				Version:     0,
+			},
+			expectedFields: nil,
+		},
+		{
+			name: "Texts Too Long",
+			list: entity.TimeslotsList{
+				ID:          0,
+				Title:       strings.Repeat("a", 256),
+				Description: strings.Repeat("ü", 256),
+				Version:     0,
+			},
+			expectedFields: [][2]string{
+				{"title", entity.FieldCodeTooLong},
+				{"description", entity.FieldCodeTooLong},
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Call
+			err := testCase.list.Validate()
+
+			// Assert
+			require.Equal(t, testCase.expectedFields, fieldCodes(t, err))
+		})
+	}
+}
+
+func TestTimeslotItem_Validate(t *testing.T) {
+	start := time.Date(2024, time.March, 4, 10, 0, 0, 0, time.UTC)
+
+	newItem := func(title, description string, start, end time.Time) entity.TimeslotItem {
+		return entity.TimeslotItem{
+			ID:          0,
+			Title:       title,
+			Description: description,
+			Start:       start,
+			End:         end,
+			Done:        false,
+			Cancelled:   false,
+			Username:    "",
+			Color:       "",
+			ListID:      0,
+			Version:     0,
+		}
+	}
+
+	testTable := []struct {
+		name           string
+		item           entity.TimeslotItem
+		expectedFields [][2]string
+	}{
+		{
+			name:           "OK",
+			item:           newItem("Session", "", start, start.Add(time.Hour)),
+			expectedFields: nil,
+		},
+		{
+			name:           "End Before Start",
+			item:           newItem("Session", "", start, start.Add(-time.Hour)),
+			expectedFields: [][2]string{{"end", entity.FieldCodeBeforeStart}},
+		},
+		{
+			name:           "End At Start",
+			item:           newItem("Session", "", start, start),
+			expectedFields: [][2]string{{"end", entity.FieldCodeBeforeStart}},
+		},
+		{
+			name:           "Times Missing",
+			item:           newItem("Session", "", time.Time{}, time.Time{}),
+			expectedFields: [][2]string{{"start", entity.FieldCodeRequired}, {"end", entity.FieldCodeRequired}},
+		},
+		{
+			name:           "Description Of 255 Characters",
+			item:           newItem("Session", strings.Repeat("d", 255), start, start.Add(time.Hour)),
+			expectedFields: nil,
+		},
+		{
+			name: "Several Fields",
+			item: newItem("", strings.Repeat("d", 256), start, start.Add(-time.Minute)),
+			expectedFields: [][2]string{
+				{"title", entity.FieldCodeRequired},
+				{"description", entity.FieldCodeTooLong},
+				{"end", entity.FieldCodeBeforeStart},
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Call
+			err := testCase.item.Validate()
+
+			// Assert
+			require.Equal(t, testCase.expectedFields, fieldCodes(t, err))
+		})
+	}
+}
+
+func TestUpdateItemInput_Validate(t *testing.T) {
+	start := time.Date(2024, time.March, 4, 10, 0, 0, 0, time.UTC)
+	before := start.Add(-time.Hour)
+	empty := ""
+	long := strings.Repeat("a", 256)
+
+	testTable := []struct {
+		name           string
+		input          entity.UpdateItemInput
+		expectedFields [][2]string
+	}{
+		{
+			name: "Only End",
+			input: entity.UpdateItemInput{
+				Title:       nil,
+				Description: nil,
+				Start:       nil,
+				End:         &before,
+				Done:        nil,
+				Cancelled:   nil,
+			},
+			expectedFields: nil,
+		},
+		{
+			name: "Several Fields",
+			input: entity.UpdateItemInput{
+				Title:       &empty,
+				Description: &long,
+				Start:       &start,
+				End:         &before,
+				Done:        nil,
+				Cancelled:   nil,
+			},
+			expectedFields: [][2]string{
+				{"title", entity.FieldCodeRequired},
+				{"description", entity.FieldCodeTooLong},
+				{"end", entity.FieldCodeBeforeStart},
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Call
+			err := testCase.input.Validate()
+
+			// Assert
+			require.Equal(t, testCase.expectedFields, fieldCodes(t, err))
+		})
+	}
+}
@@ -1,9 +1,87 @@
 package entity
 
+import "unicode/utf8"
+
 type User struct {
 	ID       int    `json:"-"        db:"id"`
-	Name     string `json:"name"     db:"name"          binding:"required"`
-	Color    string `json:"color"    db:"color"         binding:"required"`
-	Username string `json:"username" db:"username"      binding:"required"`
-	Password string `json:"password" db:"password_hash" binding:"required"`
+	Name     string `json:"name"     db:"name"`
+	Color    string `json:"color"    db:"color"`
+	Username string `json:"username" db:"username"`
+	Password string `json:"password" db:"password_hash"`
+}
+
+// Validate checks a new user. Color is a hex RGB value without the leading
+// hash, as stored in users.color.
+func (u *User) Validate() error {
+	var fields fieldErrors
+
+	if fields.required("name", u.Name) {
+		fields.maxLength("name", u.Name, maxTextLength)
+	}
+
+	if fields.required("color", u.Color) && !colorPattern.MatchString(u.Color) {
+		fields.add("color", FieldCodeInvalidFormat, "color must be six hex digits")
+	}
+
+	if fields.required("username", u.Username) {
+		switch {
+		case len(u.Username) < minUsernameLength:
+			fields.add("username", FieldCodeTooShort, "username is shorter than 3 characters")
+		case len(u.Username) > maxUsernameLength:
+			fields.add("username", FieldCodeTooLong, "username is longer than 32 characters")
+		case !usernamePattern.MatchString(u.Username):
+			fields.add("username", FieldCodeInvalidFormat,
+				"username may only contain letters, digits, dots, dashes and underscores")
+		}
+	}
+
+	if fields.required("password", u.Password) && utf8.RuneCountInString(u.Password) < minPasswordLength {
+		fields.add("password", FieldCodeTooShort, "password is shorter than 6 characters")
+	}
+
+	return fields.err("invalid_user")
+}
+
+// UserInfo is a user as the admin tools see it.
+type UserInfo struct {
+	ID       int    `json:"id"       db:"id"`
+	Name     string `json:"name"     db:"name"`
+	Color    string `json:"color"    db:"color"`
+	Username string `json:"username" db:"username"`
+	Disabled bool   `json:"disabled" db:"disabled"`
+	Lists    int    `json:"lists"    db:"lists"`
+}
+
+// StudioStats counts what the studio has on its books.
+type StudioStats struct {
+	Users           int `json:"users"             db:"users"`
+	DisabledUsers   int `json:"disabled_users"    db:"disabled_users"`
+	Lists           int `json:"lists"             db:"lists"`
+	Items           int `json:"items"             db:"items"`
+	UpcomingItems   int `json:"upcoming_items"    db:"upcoming_items"`
+	PendingBookings int `json:"pending_bookings"  db:"pending_bookings"`
+	WaitingClients  int `json:"waiting_clients"   db:"waiting_clients"`
+	PendingOffers   int `json:"pending_offers"    db:"pending_offers"`
+}
+
+// ValidatePassword checks a new password on its own, as when it is reset.
+func ValidatePassword(password string) error {
+	var fields fieldErrors
+
+	if fields.required("password", password) && utf8.RuneCountInString(password) < minPasswordLength {
+		fields.add("password", FieldCodeTooShort, "password is shorter than 6 characters")
+	}
+
+	return fields.err("invalid_password")
+}
+
+// ValidateColor checks a color on its own, as when an artist's is changed.
+func ValidateColor(color string) error {
+	var fields fieldErrors
+
+	if fields.required("color", color) && !colorPattern.MatchString(color) {
+		fields.add("color", FieldCodeInvalidFormat, "color must be six hex digits")
+	}
+
+	return fields.err("invalid_color")
 }
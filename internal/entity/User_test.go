@@ -0,0 +1,156 @@
+package entity_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"main.go/internal/entity"
+	apperrors "main.go/internal/errors"
+)
+
+// fieldCodes returns the field and code of every detail of a validation
+// error, or nil when err is nil.
+func fieldCodes(t *testing.T, err error) [][2]string {
+	t.Helper()
+
+	if err == nil {
+		return nil
+	}
+
+	var serviceErr *apperrors.ServiceError
+	require.ErrorAs(t, err, &serviceErr)
+	require.ErrorIs(t, err, apperrors.ErrValidation)
+
+	codes := make([][2]string, 0, len(serviceErr.Details))
+	for _, detail := range serviceErr.Details {
+		codes = append(codes, [2]string{detail.Field, detail.Code})
+	}
+
+	return codes
+}
+
+func TestUser_Validate(t *testing.T) {
+	valid := entity.User{ID: 0, Name: "Alice", Color: "ff00AA", Username: "alice.b-c_1", Password: "secret"}
+
+	testTable := []struct {
+		name           string
+		modify         func(user *entity.User)
+		expectedFields [][2]string
+	}{
+		{
+			name:           "OK",
+			modify:         func(_ *entity.User) {},
+			expectedFields: nil,
+		},
+		{
+			name: "All Missing",
+			modify: func(user *entity.User) {
+				*user = entity.User{ID: 0, Name: "", Color: "", Username: "", Password: ""}
+			},
+			expectedFields: [][2]string{
+				{"name", entity.FieldCodeRequired},
+				{"color", entity.FieldCodeRequired},
+				{"username", entity.FieldCodeRequired},
+				{"password", entity.FieldCodeRequired},
+			},
+		},
+		{
+			name:           "Name Of 255 Characters",
+			modify:         func(user *entity.User) { user.Name = strings.Repeat("ä", 255) },
+			expectedFields: nil,
+		},
+		{
+			name:           "Name Too Long",
+			modify:         func(user *entity.User) { user.Name = strings.Repeat("a", 256) },
+			expectedFields: [][2]string{{"name", entity.FieldCodeTooLong}},
+		},
+		{
+			name:           "Color Not Hex",
+			modify:         func(user *entity.User) { user.Color = "red" },
+			expectedFields: [][2]string{{"color", entity.FieldCodeInvalidFormat}},
+		},
+		{
+			name:           "Color With Hash",
+			modify:         func(user *entity.User) { user.Color = "#ff0000" },
+			expectedFields: [][2]string{{"color", entity.FieldCodeInvalidFormat}},
+		},
+		{
+			name:           "Color Not Hex Digits",
+			modify:         func(user *entity.User) { user.Color = "gg0000" },
+			expectedFields: [][2]string{{"color", entity.FieldCodeInvalidFormat}},
+		},
+		{
+			name:           "Username Too Short",
+			modify:         func(user *entity.User) { user.Username = "ab" },
+			expectedFields: [][2]string{{"username", entity.FieldCodeTooShort}},
+		},
+		{
+			name:           "Username Of 32 Characters",
+			modify:         func(user *entity.User) { user.Username = strings.Repeat("a", 32) },
+			expectedFields: nil,
+		},
+		{
+			name:           "Username Too Long",
+			modify:         func(user *entity.User) { user.Username = strings.Repeat("a", 33) },
+			expectedFields: [][2]string{{"username", entity.FieldCodeTooLong}},
+		},
+		{
+			name:           "Username With Space",
+			modify:         func(user *entity.User) { user.Username = "alice b" },
+			expectedFields: [][2]string{{"username", entity.FieldCodeInvalidFormat}},
+		},
+		{
+			name:           "Username Not ASCII",
+			modify:         func(user *entity.User) { user.Username = "älice" },
+			expectedFields: [][2]string{{"username", entity.FieldCodeInvalidFormat}},
+		},
+		{
+			name:           "Password Too Short",
+			modify:         func(user *entity.User) { user.Password = "12345" },
+			expectedFields: [][2]string{{"password", entity.FieldCodeTooShort}},
+		},
+		{
+			name: "Several Fields",
+			modify: func(user *entity.User) {
+				user.Color = "blue"
+				user.Username = "a"
+				user.Password = "1"
+			},
+			expectedFields: [][2]string{
+				{"color", entity.FieldCodeInvalidFormat},
+				{"username", entity.FieldCodeTooShort},
+				{"password", entity.FieldCodeTooShort},
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init deps
+			user := valid
+			testCase.modify(&user)
+
+			// Call
+			err := user.Validate()
+
+			// Assert
+			require.Equal(t, testCase.expectedFields, fieldCodes(t, err))
+		})
+	}
+}
+
+func TestUser_Validate_message(t *testing.T) {
+	user := entity.User{ID: 0, Name: "", Color: "red", Username: "alice", Password: "secret"}
+
+	err := user.Validate()
+
+	var serviceErr *apperrors.ServiceError
+	require.ErrorAs(t, err, &serviceErr)
+	require.Equal(t, "invalid_user", serviceErr.Code)
+	require.Equal(t, "invalid fields: name, color", serviceErr.Message)
+	require.Equal(t, []apperrors.FieldError{
+		{Field: "name", Code: entity.FieldCodeRequired, Message: "name is required"},
+		{Field: "color", Code: entity.FieldCodeInvalidFormat, Message: "color must be six hex digits"},
+	}, serviceErr.Details)
+}
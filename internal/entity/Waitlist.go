@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	WaitlistStatusWaiting = "waiting"
+	WaitlistStatusOffered = "offered"
+	WaitlistStatusBooked  = "booked"
+)
+
+const (
+	OfferStatusPending  = "pending"
+	OfferStatusAccepted = "accepted"
+	OfferStatusDeclined = "declined"
+	OfferStatusExpired  = "expired"
+)
+
+type WaitlistEntry struct {
+	ID              int       `json:"id"               db:"id"`
+	ClientName      string    `json:"client_name"      db:"client_name"      binding:"required"`
+	ClientContact   string    `json:"client_contact"   db:"client_contact"   binding:"required"`
+	ArtistID        int       `json:"artist_id"        db:"artist_id"        binding:"required"`
+	WindowStart     time.Time `json:"window_start"     db:"window_start"     binding:"required"`
+	WindowEnd       time.Time `json:"window_end"       db:"window_end"       binding:"required"`
+	DurationMinutes int       `json:"duration_minutes" db:"duration_minutes" binding:"required"`
+	Status          string    `json:"status"           db:"status"`
+	CreatedAt       time.Time `json:"created_at"       db:"created_at"`
+}
+
+func (e *WaitlistEntry) Validate() error {
+	if e.DurationMinutes <= 0 {
+		return errors.New("duration must be positive")
+	}
+
+	if !e.WindowEnd.After(e.WindowStart) {
+		return errors.New("window end must be after window start")
+	}
+
+	if e.WindowEnd.Sub(e.WindowStart) < time.Duration(e.DurationMinutes)*time.Minute {
+		return errors.New("window is shorter than the requested duration")
+	}
+
+	return nil
+}
+
+// FreedSlot is the time an item occupied in a list before it was cancelled
+// or deleted. Once the slot has started, NotBefore keeps appointments cut from
+// it out of the past; Start and End stay the slot's own bounds, which tell
+// whether an entry was offered the slot before.
+type FreedSlot struct {
+	ListID    int
+	Start     time.Time
+	End       time.Time
+	NotBefore time.Time
+}
+
+// Opening is the earliest an appointment cut from the slot may begin.
+func (s FreedSlot) Opening() time.Time {
+	if s.NotBefore.After(s.Start) {
+		return s.NotBefore
+	}
+
+	return s.Start
+}
+
+// WaitlistOffer holds the freed slot for one waitlist entry until ExpiresAt.
+// Start and End are the proposed appointment, SlotStart and SlotEnd the whole
+// freed slot it was cut from.
+type WaitlistOffer struct {
+	ID         int       `json:"id"          db:"id"`
+	EntryID    int       `json:"entry_id"    db:"entry_id"`
+	ListID     int       `json:"list_id"     db:"list_id"`
+	ClientName string    `json:"client_name" db:"client_name"`
+	SlotStart  time.Time `json:"-"           db:"slot_start"`
+	SlotEnd    time.Time `json:"-"           db:"slot_end"`
+	Start      time.Time `json:"start"       db:"beginning"`
+	End        time.Time `json:"end"         db:"finish"`
+	Status     string    `json:"status"      db:"status"`
+	ExpiresAt  time.Time `json:"expires_at"  db:"expires_at"`
+}
+
+func (o WaitlistOffer) Slot() FreedSlot {
+	return FreedSlot{ListID: o.ListID, Start: o.SlotStart, End: o.SlotEnd, NotBefore: time.Time{}}
+}
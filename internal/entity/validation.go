@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	apperrors "main.go/internal/errors"
+)
+
+const (
+	maxTextLength     = 255
+	minUsernameLength = 3
+	maxUsernameLength = 32
+	minPasswordLength = 6
+)
+
+const (
+	FieldCodeRequired      = "required"
+	FieldCodeTooLong       = "too_long"
+	FieldCodeTooShort      = "too_short"
+	FieldCodeInvalidFormat = "invalid_format"
+	FieldCodeBeforeStart   = "before_start"
+)
+
+var (
+	colorPattern    = regexp.MustCompile(`^[0-9a-fA-F]{6}$`)  //nolint:gochecknoglobals // compiled once
+	usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`) //nolint:gochecknoglobals // compiled once
+)
+
+// fieldErrors collects every failing field of an input so that clients can
+// fix them all at once.
+type fieldErrors []apperrors.FieldError
+
+func (f *fieldErrors) add(field, code, message string) {
+	*f = append(*f, apperrors.FieldError{Field: field, Code: code, Message: message})
+}
+
+func (f *fieldErrors) required(field, value string) bool {
+	if value == "" {
+		f.add(field, FieldCodeRequired, field+" is required")
+		return false
+	}
+
+	return true
+}
+
+func (f *fieldErrors) maxLength(field, value string, limit int) {
+	if utf8.RuneCountInString(value) > limit {
+		f.add(field, FieldCodeTooLong, field+" is longer than "+strconv.Itoa(limit)+" characters")
+	}
+}
+
+func (f *fieldErrors) timeRange(start, end time.Time) {
+	if start.IsZero() {
+		f.add("start", FieldCodeRequired, "start is required")
+	}
+
+	if end.IsZero() {
+		f.add("end", FieldCodeRequired, "end is required")
+	}
+
+	if !start.IsZero() && !end.IsZero() && !end.After(start) {
+		f.add("end", FieldCodeBeforeStart, "end must be after start")
+	}
+}
+
+func (f *fieldErrors) err(code string) error {
+	if len(*f) == 0 {
+		return nil
+	}
+
+	return apperrors.InvalidFields(code, *f)
+}
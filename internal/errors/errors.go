@@ -1,5 +1,117 @@
 package errors
 
-type ServiceError struct {
+import "strings"
+
+// Kind says what went wrong in terms a client can act on. The REST layer
+// picks the status code from it.
+type Kind string
+
+const (
+	KindNotFound        Kind = "not_found"
+	KindConflict        Kind = "conflict"
+	KindValidation      Kind = "validation"
+	KindForbidden       Kind = "forbidden"
+	KindUnauthenticated Kind = "unauthenticated"
+	KindRateLimited     Kind = "rate_limited"
+	KindUnsupported     Kind = "unsupported"
+	KindTimeout         Kind = "timeout"
+)
+
+// Errors of a kind without a code. errors.Is matches any ServiceError of the
+// same kind against them.
+var (
+	ErrNotFound        = newError(KindNotFound, "", "not found", nil)
+	ErrConflict        = newError(KindConflict, "", "conflict", nil)
+	ErrValidation      = newError(KindValidation, "", "invalid input", nil)
+	ErrForbidden       = newError(KindForbidden, "", "forbidden", nil)
+	ErrUnauthenticated = newError(KindUnauthenticated, "", "unauthenticated", nil)
+	ErrRateLimited     = newError(KindRateLimited, "", "rate limited", nil)
+	ErrUnsupported     = newError(KindUnsupported, "", "unsupported", nil)
+	ErrTimeout         = newError(KindTimeout, "", "timeout", nil)
+)
+
+// FieldError tells which input field failed validation and why.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
 	Message string `json:"message"`
 }
+
+// ServiceError is an error the client caused or can fix. Code is a stable
+// machine-readable name such as "item_not_found", Details lists the failing
+// fields of invalid input and Err keeps the cause for errors.Is and logs.
+type ServiceError struct {
+	Kind    Kind         `json:"-"`
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Details []FieldError `json:"details,omitempty"`
+	Err     error        `json:"-"`
+}
+
+func (e *ServiceError) Error() string {
+	return e.Message
+}
+
+func (e *ServiceError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches errors with the same code, or of the same kind when target has
+// no code.
+func (e *ServiceError) Is(target error) bool {
+	other, ok := target.(*ServiceError)
+	if !ok || other.Kind != e.Kind {
+		return false
+	}
+
+	return other.Code == "" || other.Code == e.Code
+}
+
+func newError(kind Kind, code, message string, err error) *ServiceError {
+	return &ServiceError{Kind: kind, Code: code, Message: message, Details: nil, Err: err}
+}
+
+func NotFound(code, message string, err error) *ServiceError {
+	return newError(KindNotFound, code, message, err)
+}
+
+func Conflict(code, message string, err error) *ServiceError {
+	return newError(KindConflict, code, message, err)
+}
+
+func Validation(code, message string, err error) *ServiceError {
+	return newError(KindValidation, code, message, err)
+}
+
+// InvalidFields is a validation error listing every failing field.
+func InvalidFields(code string, details []FieldError) *ServiceError {
+	fields := make([]string, 0, len(details))
+	for _, detail := range details {
+		fields = append(fields, detail.Field)
+	}
+
+	err := newError(KindValidation, code, "invalid fields: "+strings.Join(fields, ", "), nil)
+	err.Details = details
+
+	return err
+}
+
+func Forbidden(code, message string, err error) *ServiceError {
+	return newError(KindForbidden, code, message, err)
+}
+
+func Unauthenticated(code, message string, err error) *ServiceError {
+	return newError(KindUnauthenticated, code, message, err)
+}
+
+func RateLimited(code, message string, err error) *ServiceError {
+	return newError(KindRateLimited, code, message, err)
+}
+
+func Unsupported(code, message string, err error) *ServiceError {
+	return newError(KindUnsupported, code, message, err)
+}
+
+func Timeout(code, message string, err error) *ServiceError {
+	return newError(KindTimeout, code, message, err)
+}
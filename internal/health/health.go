@@ -0,0 +1,109 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// missedBeats is how many intervals a worker may stay silent before it is
+// considered stuck.
+const missedBeats = 2
+
+var ErrDraining = errors.New("shutting down")
+
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+type worker struct {
+	interval time.Duration
+	lastBeat time.Time
+}
+
+// Checker decides whether the app should get traffic: the database answers,
+// every background worker reports in on time and shutdown hasn't started.
+type Checker struct {
+	db       Pinger
+	now      func() time.Time
+	draining atomic.Bool
+
+	mu      sync.Mutex
+	workers map[string]*worker
+}
+
+// NewChecker pings db on every readiness check; a nil db is skipped, for the
+// in-memory store.
+func NewChecker(db Pinger) *Checker {
+	return &Checker{
+		db:       db,
+		now:      time.Now,
+		draining: atomic.Bool{},
+		mu:       sync.Mutex{},
+		workers:  make(map[string]*worker),
+	}
+}
+
+type heartbeatKey struct{}
+
+// Watch registers a worker that runs every interval and returns the context
+// to run it with. The worker reports in by calling Beat with that context.
+func (c *Checker) Watch(ctx context.Context, name string, interval time.Duration) context.Context {
+	c.mu.Lock()
+	c.workers[name] = &worker{interval: interval, lastBeat: c.now()}
+	c.mu.Unlock()
+
+	return context.WithValue(ctx, heartbeatKey{}, func() {
+		c.mu.Lock()
+		c.workers[name].lastBeat = c.now()
+		c.mu.Unlock()
+	})
+}
+
+// Beat tells the Checker watching the worker running with ctx, if any, that
+// it is still alive.
+func Beat(ctx context.Context) {
+	if beat, ok := ctx.Value(heartbeatKey{}).(func()); ok {
+		beat()
+	}
+}
+
+// Drain fails every later readiness check, so the app is taken out of
+// rotation before it stops serving.
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// Ready returns nil when the app can take traffic and every reason it can't
+// otherwise.
+func (c *Checker) Ready(ctx context.Context) error {
+	if c.draining.Load() {
+		return ErrDraining
+	}
+
+	var problems []error
+
+	if c.db != nil {
+		if err := c.db.PingContext(ctx); err != nil {
+			problems = append(problems, fmt.Errorf("database: %w", err))
+		}
+	}
+
+	now := c.now()
+
+	c.mu.Lock()
+	for name, w := range c.workers {
+		if silence := now.Sub(w.lastBeat); silence > missedBeats*w.interval {
+			problems = append(problems, fmt.Errorf("worker %s: no heartbeat for %s", name, silence.Round(time.Second)))
+		}
+	}
+	c.mu.Unlock()
+
+	sort.Slice(problems, func(i, j int) bool { return problems[i].Error() < problems[j].Error() })
+
+	return errors.Join(problems...)
+}
@@ -0,0 +1,98 @@
+package health //nolint:testpackage // need to set checker.now.
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type pingerFunc func(ctx context.Context) error
+
+func (f pingerFunc) PingContext(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestChecker_Ready(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name          string
+		pingErr       error
+		beat          bool
+		elapsed       time.Duration
+		drain         bool
+		expectedError string
+	}{
+		{
+			name:          "OK",
+			pingErr:       nil,
+			beat:          false,
+			elapsed:       90 * time.Second,
+			drain:         false,
+			expectedError: "",
+		},
+		{
+			name:          "Worker Beat",
+			pingErr:       nil,
+			beat:          true,
+			elapsed:       3 * time.Minute,
+			drain:         false,
+			expectedError: "",
+		},
+		{
+			name:          "Worker Stuck",
+			pingErr:       nil,
+			beat:          false,
+			elapsed:       3 * time.Minute,
+			drain:         false,
+			expectedError: "worker waitlist: no heartbeat for 3m0s",
+		},
+		{
+			name:          "Database Down",
+			pingErr:       errors.New("connection refused"),
+			beat:          false,
+			elapsed:       3 * time.Minute,
+			drain:         false,
+			expectedError: "database: connection refused\nworker waitlist: no heartbeat for 3m0s",
+		},
+		{
+			name:          "Draining",
+			pingErr:       nil,
+			beat:          false,
+			elapsed:       0,
+			drain:         true,
+			expectedError: "shutting down",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init deps
+			now := start
+			checker := NewChecker(pingerFunc(func(context.Context) error { return testCase.pingErr }))
+			checker.now = func() time.Time { return now }
+
+			workerCtx := checker.Watch(context.Background(), "waitlist", time.Minute)
+			now = now.Add(testCase.elapsed)
+
+			if testCase.beat {
+				Beat(workerCtx)
+			}
+
+			if testCase.drain {
+				checker.Drain()
+			}
+
+			// Assert
+			err := checker.Ready(context.Background())
+			if testCase.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, testCase.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// MaxRequestIDLength is the longest request ID taken from a caller.
+	MaxRequestIDLength = 128
+	// RequestIDBytes is the size of the random request IDs made up here.
+	RequestIDBytes = 16
+)
+
+type requestIDKey struct{}
+
+// ValidRequestID accepts IDs of printable ASCII that are short enough to
+// log, so callers can't inject line breaks or flood the logs.
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > MaxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < ' ' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NewRequestID makes up an ID for a request the caller sent none for.
+func NewRequestID() string {
+	id := make([]byte, RequestIDBytes)
+	if _, err := rand.Read(id); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(id)
+}
+
+// WithRequestID returns a copy of ctx that carries the ID of the request it
+// serves.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request ID in ctx, or "" outside of a request.
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+
+	return requestID
+}
+
+// FromContext returns a logger whose lines carry the request and trace IDs
+// found in ctx, so that everything logged while handling one request can be
+// found together.
+func FromContext(ctx context.Context) *logrus.Entry {
+	fields := logrus.Fields{}
+
+	if requestID := RequestID(ctx); requestID != "" {
+		fields["request_id"] = requestID
+	}
+
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		fields["trace_id"] = spanContext.TraceID().String()
+	}
+
+	return logrus.WithContext(ctx).WithFields(fields)
+}
@@ -0,0 +1,149 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "webstudio"
+
+// Metrics owns a registry of its own, so that nothing registered by
+// libraries in the global one leaks into /metrics and tests can build as
+// many as they like.
+type Metrics struct {
+	registry        *prometheus.Registry
+	httpRequests    *prometheus.CounterVec
+	httpDuration    *prometheus.HistogramVec
+	grpcCalls       *prometheus.CounterVec
+	grpcDuration    *prometheus.HistogramVec
+	queryDuration   *prometheus.HistogramVec
+	bookingsCreated *prometheus.CounterVec
+	cancellations   prometheus.Counter
+	upcomingToday   prometheus.Gauge
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by route, method and status.",
+		}, []string{"route", "method", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by route, method and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		grpcCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_calls_total",
+			Help:      "gRPC calls by service, method and code.",
+		}, []string{"service", "method", "code"}),
+		grpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "grpc_call_duration_seconds",
+			Help:      "gRPC call latency by service, method and code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"service", "method", "code"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "repository_query_duration_seconds",
+			Help:      "Repository call latency by repository, method and outcome.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"repository", "method", "outcome"}),
+		bookingsCreated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "bookings_created_total",
+			Help:      "Appointments booked, by where they came from.",
+		}, []string{"source"}),
+		cancellations: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "appointment_cancellations_total",
+			Help:      "Appointments cancelled or deleted before they ended.",
+		}),
+		upcomingToday: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "appointments_upcoming_today",
+			Help:      "Appointments that have not started yet and start today.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.grpcCalls,
+		m.grpcDuration,
+		m.queryDuration,
+		m.bookingsCreated,
+		m.cancellations,
+		m.upcomingToday,
+	)
+
+	return m
+}
+
+// RegisterDB exports the connection pool stats of db. The stats stay
+// readable after db is closed, so scrapes during shutdown still work.
+func (m *Metrics) RegisterDB(db *sql.DB, name string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Middleware counts and times requests by their route template, so that
+// /api/items/1 and /api/items/2 share a series.
+func (m *Metrics) Middleware(ctx *gin.Context) {
+	start := time.Now()
+
+	ctx.Next()
+
+	route := ctx.FullPath()
+	if route == "" {
+		route = "unmatched"
+	}
+
+	status := strconv.Itoa(ctx.Writer.Status())
+	m.httpRequests.WithLabelValues(route, ctx.Request.Method, status).Inc()
+	m.httpDuration.WithLabelValues(route, ctx.Request.Method, status).Observe(time.Since(start).Seconds())
+}
+
+// ObserveCall is Middleware for the gRPC calls, which the server reports
+// once they end.
+func (m *Metrics) ObserveCall(service, method, code string, duration time.Duration) {
+	m.grpcCalls.WithLabelValues(service, method, code).Inc()
+	m.grpcDuration.WithLabelValues(service, method, code).Observe(duration.Seconds())
+}
+
+func (m *Metrics) ObserveQuery(repository, method string, duration time.Duration, err error) {
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+
+	m.queryDuration.WithLabelValues(repository, method, outcome).Observe(duration.Seconds())
+}
+
+func (m *Metrics) BookingCreated(source string) {
+	m.bookingsCreated.WithLabelValues(source).Inc()
+}
+
+func (m *Metrics) AppointmentCancelled() {
+	m.cancellations.Inc()
+}
+
+func (m *Metrics) SetUpcomingToday(count int) {
+	m.upcomingToday.Set(float64(count))
+}
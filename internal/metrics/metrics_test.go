@@ -0,0 +1,47 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"main.go/internal/metrics"
+)
+
+func TestMetrics_Middleware(t *testing.T) {
+	// Init deps
+	appMetrics := metrics.New()
+
+	// Test server
+	router := gin.New()
+	router.Use(appMetrics.Middleware)
+	router.GET("/api/items/:id", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	// Make requests
+	for _, target := range []string{"/api/items/1", "/api/items/2", "/missing"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	appMetrics.BookingCreated("portal")
+	appMetrics.ObserveCall("timeslots.v1.ItemService", "Get", "NotFound", time.Millisecond)
+
+	scrape := httptest.NewRecorder()
+	appMetrics.Handler().ServeHTTP(scrape, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	// Assert
+	body := scrape.Body.String()
+	require.Equal(t, http.StatusOK, scrape.Code)
+	require.True(t, strings.Contains(body,
+		`webstudio_http_requests_total{method="GET",route="/api/items/:id",status="204"} 2`), body)
+	require.True(t, strings.Contains(body,
+		`webstudio_http_requests_total{method="GET",route="unmatched",status="404"} 1`), body)
+	require.True(t, strings.Contains(body, `webstudio_bookings_created_total{source="portal"} 1`), body)
+	require.True(t, strings.Contains(body,
+		`webstudio_grpc_calls_total{code="NotFound",method="Get",service="timeslots.v1.ItemService"} 1`), body)
+}
@@ -0,0 +1,302 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"main.go/internal/entity"
+)
+
+const (
+	// maxCachedDays bounds the ranges Cached keeps, each day of a range
+	// costing a cache lookup.
+	maxCachedDays = 31
+	tokenBytes    = 8
+)
+
+// Cache stores the results of range queries. The in-process cache.LRU is
+// the default; a shared cache lets replicas of the app share results and
+// invalidations. A failing cache should answer misses rather than errors.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool)
+	Set(ctx context.Context, key string, value []byte)
+}
+
+// Cached serves the TimeslotItem range queries of repo, which the studio
+// dashboard polls, from cache. A cached range is keyed by the query and by a
+// generation of each of its days; writing an item starts a new generation of
+// the days it covered and now covers, so only the ranges holding it are
+// missed. Writes elsewhere that change what ranges return, such as booking an
+// item or recoloring an artist, start a new generation of every day.
+//
+// Misses are read from the primary, so a lagging replica is never cached.
+// The cache doesn't see writes made by other programs, such as the admin CLI,
+// which only show once the cached ranges expire.
+func Cached(repo *Repository, cache Cache) *Repository {
+	generations := &generations{cache: cache}
+
+	cached := *repo
+	cached.TimeslotItem = &cachedTimeslotItem{TimeslotItem: repo.TimeslotItem, cache: cache, generations: generations}
+	cached.TimeslotList = &cachedTimeslotList{TimeslotList: repo.TimeslotList, generations: generations}
+	cached.Waitlist = &cachedWaitlist{Waitlist: repo.Waitlist, generations: generations}
+	cached.Booking = &cachedBooking{Booking: repo.Booking, generations: generations}
+	cached.Users = &cachedUsers{Users: repo.Users, generations: generations}
+
+	return &cached
+}
+
+const allDaysKey = "items:days"
+
+// generations hands out the generation tokens of days. A token missing from
+// the cache, never set or evicted, is replaced by a new one rather than
+// taken as empty, so entries cached under an older token can't come back.
+type generations struct {
+	cache Cache
+}
+
+func (g *generations) get(ctx context.Context, key string) string {
+	if token, ok := g.cache.Get(ctx, key); ok {
+		return string(token)
+	}
+
+	return g.bump(ctx, key)
+}
+
+func (g *generations) bump(ctx context.Context, key string) string {
+	random := make([]byte, tokenBytes)
+	_, _ = rand.Read(random)
+
+	token := hex.EncodeToString(random)
+	g.cache.Set(ctx, key, []byte(token))
+
+	return token
+}
+
+// bumpItem starts a new generation of the days item spans.
+func (g *generations) bumpItem(ctx context.Context, start, end time.Time) {
+	for _, day := range days(start, end) {
+		g.bump(ctx, dayKey(day))
+	}
+}
+
+func (g *generations) bumpAll(ctx context.Context) {
+	g.bump(ctx, allDaysKey)
+}
+
+// days lists the UTC dates [start, end) overlaps, at least start's. Items
+// have a length, so one within a range overlaps one of the range's dates.
+func days(start, end time.Time) []time.Time {
+	year, month, day := start.UTC().Date()
+	first := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	dates := []time.Time{first}
+	for date := first.AddDate(0, 0, 1); date.Before(end); date = date.AddDate(0, 0, 1) {
+		dates = append(dates, date)
+	}
+
+	return dates
+}
+
+func dayKey(day time.Time) string {
+	return "items:day:" + day.Format(time.DateOnly)
+}
+
+type cachedTimeslotItem struct {
+	TimeslotItem
+	cache       Cache
+	generations *generations
+}
+
+func (r *cachedTimeslotItem) GetByRange(ctx context.Context, input entity.ItemsByRange) ([]entity.TimeslotItem, error) {
+	dates := days(input.Start, input.End)
+	if len(dates) > maxCachedDays {
+		return r.TimeslotItem.GetByRange(ctx, input)
+	}
+
+	key, err := r.key(ctx, input, dates)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached, ok := r.cache.Get(ctx, key); ok {
+		var items []entity.TimeslotItem
+		if err = json.Unmarshal(cached, &items); err == nil {
+			return items, nil
+		}
+	}
+
+	items, err := r.TimeslotItem.GetByRange(onPrimary(ctx), input)
+	if err != nil {
+		return nil, err
+	}
+
+	if encoded, err := json.Marshal(items); err == nil {
+		r.cache.Set(ctx, key, encoded)
+	}
+
+	return items, nil
+}
+
+// key identifies the query and the generations of its days. The generations
+// are read before the query runs, so a write landing in between leaves the
+// result under a key nobody asks for again.
+func (r *cachedTimeslotItem) key(ctx context.Context, input entity.ItemsByRange, dates []time.Time) (string, error) {
+	query, err := json.Marshal(input)
+	if err != nil {
+		return "", err
+	}
+
+	tokens := []string{string(query), r.generations.get(ctx, allDaysKey)}
+	for _, date := range dates {
+		tokens = append(tokens, r.generations.get(ctx, dayKey(date)))
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(tokens, "\n")))
+
+	return "items:range:" + hex.EncodeToString(sum[:]), nil
+}
+
+func (r *cachedTimeslotItem) Create(ctx context.Context, listID int, item entity.TimeslotItem) (int, error) {
+	itemID, err := r.TimeslotItem.Create(ctx, listID, item)
+	if err != nil {
+		return 0, err
+	}
+
+	r.generations.bumpItem(ctx, item.Start, item.End)
+
+	return itemID, nil
+}
+
+func (r *cachedTimeslotItem) Update(
+	ctx context.Context,
+	userID, itemID int,
+	input entity.UpdateItemInput,
+	version int,
+) error {
+	before, lookupErr := r.TimeslotItem.GetByID(onPrimary(ctx), userID, itemID)
+
+	if err := r.TimeslotItem.Update(ctx, userID, itemID, input, version); err != nil {
+		return err
+	}
+
+	if lookupErr != nil {
+		r.generations.bumpAll(ctx)
+
+		return nil
+	}
+
+	after := before
+	if input.Start != nil {
+		after.Start = *input.Start
+	}
+
+	if input.End != nil {
+		after.End = *input.End
+	}
+
+	r.generations.bumpItem(ctx, before.Start, before.End)
+	r.generations.bumpItem(ctx, after.Start, after.End)
+
+	return nil
+}
+
+func (r *cachedTimeslotItem) Delete(ctx context.Context, userID, itemID int) error {
+	before, lookupErr := r.TimeslotItem.GetByID(onPrimary(ctx), userID, itemID)
+
+	if err := r.TimeslotItem.Delete(ctx, userID, itemID); err != nil {
+		return err
+	}
+
+	if lookupErr != nil {
+		r.generations.bumpAll(ctx)
+
+		return nil
+	}
+
+	r.generations.bumpItem(ctx, before.Start, before.End)
+
+	return nil
+}
+
+// cachedTimeslotList drops every range on list deletes, which take the
+// list's items along.
+type cachedTimeslotList struct {
+	TimeslotList
+	generations *generations
+}
+
+func (r *cachedTimeslotList) Delete(ctx context.Context, userID, listID int) error {
+	if err := r.TimeslotList.Delete(ctx, userID, listID); err != nil {
+		return err
+	}
+
+	r.generations.bumpAll(ctx)
+
+	return nil
+}
+
+// cachedWaitlist drops every range on accepted offers, which book an item.
+type cachedWaitlist struct {
+	Waitlist
+	generations *generations
+}
+
+func (r *cachedWaitlist) AcceptOffer(ctx context.Context, offerID int) (int, error) {
+	itemID, err := r.Waitlist.AcceptOffer(ctx, offerID)
+	if err != nil {
+		return 0, err
+	}
+
+	r.generations.bumpAll(ctx)
+
+	return itemID, nil
+}
+
+// cachedBooking drops every range on approved requests, which book an item.
+type cachedBooking struct {
+	Booking
+	generations *generations
+}
+
+func (r *cachedBooking) ApproveRequest(ctx context.Context, requestID, listID int) (int, error) {
+	itemID, err := r.Booking.ApproveRequest(ctx, requestID, listID)
+	if err != nil {
+		return 0, err
+	}
+
+	r.generations.bumpAll(ctx)
+
+	return itemID, nil
+}
+
+// cachedUsers drops every range when the owner or color shown with items
+// changes.
+type cachedUsers struct {
+	Users
+	generations *generations
+}
+
+func (r *cachedUsers) DeleteUser(ctx context.Context, userID int) error {
+	return r.bumpAfter(ctx, r.Users.DeleteUser(ctx, userID))
+}
+
+func (r *cachedUsers) SetColor(ctx context.Context, userID int, color string) error {
+	return r.bumpAfter(ctx, r.Users.SetColor(ctx, userID, color))
+}
+
+func (r *cachedUsers) TransferList(ctx context.Context, listID, userID int) error {
+	return r.bumpAfter(ctx, r.Users.TransferList(ctx, listID, userID))
+}
+
+func (r *cachedUsers) bumpAfter(ctx context.Context, err error) error {
+	if err == nil {
+		r.generations.bumpAll(ctx)
+	}
+
+	return err
+}
@@ -0,0 +1,100 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"main.go/internal/cache"
+	"main.go/internal/entity"
+	"main.go/internal/repository"
+)
+
+var monday = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) //nolint:gochecknoglobals // test fixture
+
+func newItem(title string, start time.Time) entity.TimeslotItem {
+	return entity.TimeslotItem{
+		ID:          0,
+		Title:       title,
+		Description: "",
+		Start:       start,
+		End:         start.Add(time.Hour),
+		Done:        false,
+		Cancelled:   false,
+		Username:    "",
+		Color:       "",
+		ListID:      0,
+		Version:     0,
+	}
+}
+
+func dayRange(day time.Time) entity.ItemsByRange {
+	return entity.ItemsByRange{
+		ItemsFilter: entity.ItemsFilter{
+			PageParams: entity.PageParams{Cursor: "", Limit: entity.DefaultPageLimit, After: entity.Cursor{}},
+			Title:      "",
+			Status:     "",
+			Artist:     "",
+		},
+		Start: day,
+		End:   day.Add(24 * time.Hour),
+	}
+}
+
+func titles(t *testing.T, repo *repository.Repository, input entity.ItemsByRange) []string {
+	t.Helper()
+
+	items, err := repo.TimeslotItem.GetByRange(context.Background(), input)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.Title)
+	}
+
+	return names
+}
+
+func TestCached(t *testing.T) {
+	ctx := context.Background()
+	tuesday := monday.Add(24 * time.Hour)
+
+	// The cache sits on store; writes to store directly go unseen, which is
+	// how the tests tell a cached answer from a fresh one.
+	store, listID := newStore(t, "Monday")
+	repo := repository.Cached(store, cache.NewLRU(100, time.Minute))
+
+	require.Equal(t, []string{"Monday"}, titles(t, repo, dayRange(monday)))
+	require.Empty(t, titles(t, repo, dayRange(tuesday)))
+
+	_, err := store.TimeslotItem.Create(ctx, listID, newItem("Unseen", monday.Add(12*time.Hour)))
+	require.NoError(t, err)
+	require.Equal(t, []string{"Monday"}, titles(t, repo, dayRange(monday)), "served from cache")
+
+	_, err = repo.TimeslotItem.Create(ctx, listID, newItem("Tuesday", tuesday.Add(10*time.Hour)))
+	require.NoError(t, err)
+	require.Equal(t, []string{"Monday"}, titles(t, repo, dayRange(monday)), "other days stay cached")
+	require.Equal(t, []string{"Tuesday"}, titles(t, repo, dayRange(tuesday)))
+
+	tuesdayID, err := repo.TimeslotItem.Create(ctx, listID, newItem("Moved", tuesday.Add(14*time.Hour)))
+	require.NoError(t, err)
+	require.Equal(t, []string{"Tuesday", "Moved"}, titles(t, repo, dayRange(tuesday)))
+
+	start, end := monday.Add(15*time.Hour), monday.Add(16*time.Hour)
+	input := entity.UpdateItemInput{Title: nil, Description: nil, Start: &start, End: &end, Done: nil, Cancelled: nil}
+	require.NoError(t, repo.TimeslotItem.Update(ctx, 1, tuesdayID, input, 1))
+	require.Equal(t, []string{"Tuesday"}, titles(t, repo, dayRange(tuesday)), "the old day is invalidated")
+	require.Equal(t, []string{"Monday", "Unseen", "Moved"}, titles(t, repo, dayRange(monday)), "and the new one")
+
+	require.NoError(t, repo.TimeslotItem.Delete(ctx, 1, tuesdayID))
+	require.Equal(t, []string{"Monday", "Unseen"}, titles(t, repo, dayRange(monday)))
+
+	_, err = store.TimeslotItem.Create(ctx, listID, newItem("Late", tuesday.Add(20*time.Hour)))
+	require.NoError(t, err)
+	require.Equal(t, []string{"Tuesday"}, titles(t, repo, dayRange(tuesday)))
+
+	require.NoError(t, repo.TimeslotList.Delete(ctx, 1, listID))
+	require.Empty(t, titles(t, repo, dayRange(monday)), "list deletes invalidate every day")
+	require.Empty(t, titles(t, repo, dayRange(tuesday)))
+}
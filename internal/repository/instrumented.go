@@ -0,0 +1,444 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"main.go/internal/entity"
+)
+
+var tracer = otel.Tracer("main.go/internal/repository")
+
+// QueryObserver is told how long every repository call took.
+type QueryObserver interface {
+	ObserveQuery(repository, method string, duration time.Duration, err error)
+}
+
+// Instrument wraps every repository of repo so that its calls are traced and
+// reported to observer. It wraps whatever implementations repo holds, so
+// swap in alternative stores before calling it.
+func Instrument(repo *Repository, observer QueryObserver) *Repository {
+	return &Repository{
+		Authorization: &instrumentedAuthorization{next: repo.Authorization, observer: observer},
+		TimeslotList:  &instrumentedTimeslotList{next: repo.TimeslotList, observer: observer},
+		TimeslotItem:  &instrumentedTimeslotItem{next: repo.TimeslotItem, observer: observer},
+		Search:        &instrumentedSearch{next: repo.Search, observer: observer},
+		Waitlist:      &instrumentedWaitlist{next: repo.Waitlist, observer: observer},
+		Booking:       &instrumentedBooking{next: repo.Booking, observer: observer},
+		RateLimit:     &instrumentedRateLimit{next: repo.RateLimit, observer: observer},
+		Idempotency:   &instrumentedIdempotency{next: repo.Idempotency, observer: observer},
+		Users:         &instrumentedUsers{next: repo.Users, observer: observer},
+	}
+}
+
+func observe[T any](
+	ctx context.Context,
+	observer QueryObserver,
+	repository, method string,
+	call func(ctx context.Context) (T, error),
+) (T, error) {
+	ctx, span := tracer.Start(ctx, repository+"."+method)
+	defer span.End()
+
+	start := time.Now()
+	result, err := call(ctx)
+	observer.ObserveQuery(repository, method, time.Since(start), err)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return result, err
+}
+
+func observeErr(
+	ctx context.Context,
+	observer QueryObserver,
+	repository, method string,
+	call func(ctx context.Context) error,
+) error {
+	_, err := observe(ctx, observer, repository, method, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, call(ctx)
+	})
+
+	return err
+}
+
+type instrumentedAuthorization struct {
+	next     Authorization
+	observer QueryObserver
+}
+
+func (r *instrumentedAuthorization) IsActive(ctx context.Context, userID int) (bool, error) {
+	return observe(ctx, r.observer, "authorization", "IsActive", func(ctx context.Context) (bool, error) {
+		return r.next.IsActive(ctx, userID)
+	})
+}
+
+func (r *instrumentedAuthorization) CreateUser(ctx context.Context, user entity.User) (int, error) {
+	return observe(ctx, r.observer, "authorization", "CreateUser", func(ctx context.Context) (int, error) {
+		return r.next.CreateUser(ctx, user)
+	})
+}
+
+func (r *instrumentedAuthorization) GetUser(ctx context.Context, username, password string) (entity.User, error) {
+	return observe(ctx, r.observer, "authorization", "GetUser", func(ctx context.Context) (entity.User, error) {
+		return r.next.GetUser(ctx, username, password)
+	})
+}
+
+type instrumentedTimeslotList struct {
+	next     TimeslotList
+	observer QueryObserver
+}
+
+func (r *instrumentedTimeslotList) Create(ctx context.Context, userID int, list entity.TimeslotsList) (int, error) {
+	return observe(ctx, r.observer, "timeslotList", "Create", func(ctx context.Context) (int, error) {
+		return r.next.Create(ctx, userID, list)
+	})
+}
+
+func (r *instrumentedTimeslotList) GetAll(
+	ctx context.Context,
+	userID int,
+	filter entity.ListsFilter,
+) ([]entity.TimeslotsList, error) {
+	return observe(ctx, r.observer, "timeslotList", "GetAll", func(ctx context.Context) ([]entity.TimeslotsList, error) {
+		return r.next.GetAll(ctx, userID, filter)
+	})
+}
+
+func (r *instrumentedTimeslotList) GetByID(ctx context.Context, userID, listID int) (entity.TimeslotsList, error) {
+	return observe(ctx, r.observer, "timeslotList", "GetByID", func(ctx context.Context) (entity.TimeslotsList, error) {
+		return r.next.GetByID(ctx, userID, listID)
+	})
+}
+
+func (r *instrumentedTimeslotList) Delete(ctx context.Context, userID, listID int) error {
+	return observeErr(ctx, r.observer, "timeslotList", "Delete", func(ctx context.Context) error {
+		return r.next.Delete(ctx, userID, listID)
+	})
+}
+
+func (r *instrumentedTimeslotList) Update(
+	ctx context.Context,
+	userID, listID int,
+	input entity.UpdateListInput,
+	version int,
+) error {
+	return observeErr(ctx, r.observer, "timeslotList", "Update", func(ctx context.Context) error {
+		return r.next.Update(ctx, userID, listID, input, version)
+	})
+}
+
+type instrumentedTimeslotItem struct {
+	next     TimeslotItem
+	observer QueryObserver
+}
+
+func (r *instrumentedTimeslotItem) Create(ctx context.Context, listID int, item entity.TimeslotItem) (int, error) {
+	return observe(ctx, r.observer, "timeslotItem", "Create", func(ctx context.Context) (int, error) {
+		return r.next.Create(ctx, listID, item)
+	})
+}
+
+func (r *instrumentedTimeslotItem) GetAll(
+	ctx context.Context,
+	userID, listID int,
+	filter entity.ItemsFilter,
+) ([]entity.TimeslotItem, error) {
+	return observe(ctx, r.observer, "timeslotItem", "GetAll", func(ctx context.Context) ([]entity.TimeslotItem, error) {
+		return r.next.GetAll(ctx, userID, listID, filter)
+	})
+}
+
+func (r *instrumentedTimeslotItem) GetByID(ctx context.Context, userID, itemID int) (entity.TimeslotItem, error) {
+	return observe(ctx, r.observer, "timeslotItem", "GetByID", func(ctx context.Context) (entity.TimeslotItem, error) {
+		return r.next.GetByID(ctx, userID, itemID)
+	})
+}
+
+func (r *instrumentedTimeslotItem) Delete(ctx context.Context, userID, itemID int) error {
+	return observeErr(ctx, r.observer, "timeslotItem", "Delete", func(ctx context.Context) error {
+		return r.next.Delete(ctx, userID, itemID)
+	})
+}
+
+func (r *instrumentedTimeslotItem) Update(
+	ctx context.Context,
+	userID, itemID int,
+	input entity.UpdateItemInput,
+	version int,
+) error {
+	return observeErr(ctx, r.observer, "timeslotItem", "Update", func(ctx context.Context) error {
+		return r.next.Update(ctx, userID, itemID, input, version)
+	})
+}
+
+func (r *instrumentedTimeslotItem) GetByRange(
+	ctx context.Context,
+	input entity.ItemsByRange,
+) ([]entity.TimeslotItem, error) {
+	return observe(
+		ctx, r.observer, "timeslotItem", "GetByRange",
+		func(ctx context.Context) ([]entity.TimeslotItem, error) {
+			return r.next.GetByRange(ctx, input)
+		},
+	)
+}
+
+func (r *instrumentedTimeslotItem) CountUpcoming(ctx context.Context, from, to time.Time) (int, error) {
+	return observe(ctx, r.observer, "timeslotItem", "CountUpcoming", func(ctx context.Context) (int, error) {
+		return r.next.CountUpcoming(ctx, from, to)
+	})
+}
+
+type instrumentedSearch struct {
+	next     Search
+	observer QueryObserver
+}
+
+func (r *instrumentedSearch) Search(
+	ctx context.Context,
+	userID int,
+	tsQuery string,
+	limit int,
+) ([]entity.SearchResult, error) {
+	return observe(ctx, r.observer, "search", "Search", func(ctx context.Context) ([]entity.SearchResult, error) {
+		return r.next.Search(ctx, userID, tsQuery, limit)
+	})
+}
+
+type instrumentedWaitlist struct {
+	next     Waitlist
+	observer QueryObserver
+}
+
+func (r *instrumentedWaitlist) CreateEntry(ctx context.Context, entry entity.WaitlistEntry) (int, error) {
+	return observe(ctx, r.observer, "waitlist", "CreateEntry", func(ctx context.Context) (int, error) {
+		return r.next.CreateEntry(ctx, entry)
+	})
+}
+
+func (r *instrumentedWaitlist) GetEntries(ctx context.Context, artistID int) ([]entity.WaitlistEntry, error) {
+	return observe(ctx, r.observer, "waitlist", "GetEntries", func(ctx context.Context) ([]entity.WaitlistEntry, error) {
+		return r.next.GetEntries(ctx, artistID)
+	})
+}
+
+func (r *instrumentedWaitlist) DeleteEntry(ctx context.Context, entryID int) error {
+	return observeErr(ctx, r.observer, "waitlist", "DeleteEntry", func(ctx context.Context) error {
+		return r.next.DeleteEntry(ctx, entryID)
+	})
+}
+
+func (r *instrumentedWaitlist) CreateOffer(
+	ctx context.Context,
+	slot entity.FreedSlot,
+	hold time.Duration,
+) (entity.WaitlistOffer, error) {
+	return observe(ctx, r.observer, "waitlist", "CreateOffer", func(ctx context.Context) (entity.WaitlistOffer, error) {
+		return r.next.CreateOffer(ctx, slot, hold)
+	})
+}
+
+func (r *instrumentedWaitlist) GetOffers(ctx context.Context, artistID int) ([]entity.WaitlistOffer, error) {
+	return observe(ctx, r.observer, "waitlist", "GetOffers", func(ctx context.Context) ([]entity.WaitlistOffer, error) {
+		return r.next.GetOffers(ctx, artistID)
+	})
+}
+
+func (r *instrumentedWaitlist) AcceptOffer(ctx context.Context, offerID int) (int, error) {
+	return observe(ctx, r.observer, "waitlist", "AcceptOffer", func(ctx context.Context) (int, error) {
+		return r.next.AcceptOffer(ctx, offerID)
+	})
+}
+
+func (r *instrumentedWaitlist) DeclineOffer(ctx context.Context, offerID int) (entity.WaitlistOffer, error) {
+	return observe(ctx, r.observer, "waitlist", "DeclineOffer", func(ctx context.Context) (entity.WaitlistOffer, error) {
+		return r.next.DeclineOffer(ctx, offerID)
+	})
+}
+
+func (r *instrumentedWaitlist) ExpireOffers(ctx context.Context) ([]entity.WaitlistOffer, error) {
+	return observe(ctx, r.observer, "waitlist", "ExpireOffers", func(ctx context.Context) ([]entity.WaitlistOffer, error) {
+		return r.next.ExpireOffers(ctx)
+	})
+}
+
+type instrumentedBooking struct {
+	next     Booking
+	observer QueryObserver
+}
+
+func (r *instrumentedBooking) GetArtists(ctx context.Context) ([]entity.Artist, error) {
+	return observe(ctx, r.observer, "booking", "GetArtists", func(ctx context.Context) ([]entity.Artist, error) {
+		return r.next.GetArtists(ctx)
+	})
+}
+
+func (r *instrumentedBooking) GetBusy(
+	ctx context.Context,
+	artistID int,
+	start, end time.Time,
+) ([]entity.TimeRange, error) {
+	return observe(ctx, r.observer, "booking", "GetBusy", func(ctx context.Context) ([]entity.TimeRange, error) {
+		return r.next.GetBusy(ctx, artistID, start, end)
+	})
+}
+
+func (r *instrumentedBooking) CreateRequest(ctx context.Context, request entity.BookingRequest) (int, error) {
+	return observe(ctx, r.observer, "booking", "CreateRequest", func(ctx context.Context) (int, error) {
+		return r.next.CreateRequest(ctx, request)
+	})
+}
+
+func (r *instrumentedBooking) CountRecentByIP(ctx context.Context, clientIP string, since time.Time) (int, error) {
+	return observe(ctx, r.observer, "booking", "CountRecentByIP", func(ctx context.Context) (int, error) {
+		return r.next.CountRecentByIP(ctx, clientIP, since)
+	})
+}
+
+func (r *instrumentedBooking) CountPendingByEmail(ctx context.Context, clientEmail string) (int, error) {
+	return observe(ctx, r.observer, "booking", "CountPendingByEmail", func(ctx context.Context) (int, error) {
+		return r.next.CountPendingByEmail(ctx, clientEmail)
+	})
+}
+
+func (r *instrumentedBooking) GetRequests(
+	ctx context.Context,
+	filter entity.BookingsFilter,
+) ([]entity.BookingRequest, error) {
+	return observe(ctx, r.observer, "booking", "GetRequests", func(ctx context.Context) ([]entity.BookingRequest, error) {
+		return r.next.GetRequests(ctx, filter)
+	})
+}
+
+func (r *instrumentedBooking) ApproveRequest(ctx context.Context, requestID, listID int) (int, error) {
+	return observe(ctx, r.observer, "booking", "ApproveRequest", func(ctx context.Context) (int, error) {
+		return r.next.ApproveRequest(ctx, requestID, listID)
+	})
+}
+
+func (r *instrumentedBooking) RejectRequest(ctx context.Context, requestID int) error {
+	return observeErr(ctx, r.observer, "booking", "RejectRequest", func(ctx context.Context) error {
+		return r.next.RejectRequest(ctx, requestID)
+	})
+}
+
+type instrumentedRateLimit struct {
+	next     RateLimit
+	observer QueryObserver
+}
+
+func (r *instrumentedRateLimit) Take(
+	ctx context.Context,
+	key string,
+	limit entity.RateLimit,
+	now time.Time,
+) (entity.RateDecision, error) {
+	return observe(ctx, r.observer, "rateLimit", "Take", func(ctx context.Context) (entity.RateDecision, error) {
+		return r.next.Take(ctx, key, limit, now)
+	})
+}
+
+func (r *instrumentedRateLimit) DeleteStale(ctx context.Context, before time.Time) error {
+	return observeErr(ctx, r.observer, "rateLimit", "DeleteStale", func(ctx context.Context) error {
+		return r.next.DeleteStale(ctx, before)
+	})
+}
+
+type instrumentedIdempotency struct {
+	next     Idempotency
+	observer QueryObserver
+}
+
+func (r *instrumentedIdempotency) Create(ctx context.Context, record entity.IdempotencyKey) (bool, error) {
+	return observe(ctx, r.observer, "idempotency", "Create", func(ctx context.Context) (bool, error) {
+		return r.next.Create(ctx, record)
+	})
+}
+
+func (r *instrumentedIdempotency) Reclaim(
+	ctx context.Context,
+	record entity.IdempotencyKey,
+	claimedBefore time.Time,
+) (bool, error) {
+	return observe(ctx, r.observer, "idempotency", "Reclaim", func(ctx context.Context) (bool, error) {
+		return r.next.Reclaim(ctx, record, claimedBefore)
+	})
+}
+
+func (r *instrumentedIdempotency) Get(ctx context.Context, userID int, key string) (entity.IdempotencyKey, error) {
+	return observe(ctx, r.observer, "idempotency", "Get", func(ctx context.Context) (entity.IdempotencyKey, error) {
+		return r.next.Get(ctx, userID, key)
+	})
+}
+
+func (r *instrumentedIdempotency) SaveResponse(ctx context.Context, record entity.IdempotencyKey) error {
+	return observeErr(ctx, r.observer, "idempotency", "SaveResponse", func(ctx context.Context) error {
+		return r.next.SaveResponse(ctx, record)
+	})
+}
+
+func (r *instrumentedIdempotency) Delete(ctx context.Context, userID int, key string) error {
+	return observeErr(ctx, r.observer, "idempotency", "Delete", func(ctx context.Context) error {
+		return r.next.Delete(ctx, userID, key)
+	})
+}
+
+func (r *instrumentedIdempotency) DeleteStale(ctx context.Context, before time.Time) error {
+	return observeErr(ctx, r.observer, "idempotency", "DeleteStale", func(ctx context.Context) error {
+		return r.next.DeleteStale(ctx, before)
+	})
+}
+
+type instrumentedUsers struct {
+	next     Users
+	observer QueryObserver
+}
+
+func (r *instrumentedUsers) GetUsers(ctx context.Context) ([]entity.UserInfo, error) {
+	return observe(ctx, r.observer, "users", "GetUsers", func(ctx context.Context) ([]entity.UserInfo, error) {
+		return r.next.GetUsers(ctx)
+	})
+}
+
+func (r *instrumentedUsers) SetDisabled(ctx context.Context, userID int, disabled bool) error {
+	return observeErr(ctx, r.observer, "users", "SetDisabled", func(ctx context.Context) error {
+		return r.next.SetDisabled(ctx, userID, disabled)
+	})
+}
+
+func (r *instrumentedUsers) DeleteUser(ctx context.Context, userID int) error {
+	return observeErr(ctx, r.observer, "users", "DeleteUser", func(ctx context.Context) error {
+		return r.next.DeleteUser(ctx, userID)
+	})
+}
+
+func (r *instrumentedUsers) SetPassword(ctx context.Context, userID int, passwordHash string) error {
+	return observeErr(ctx, r.observer, "users", "SetPassword", func(ctx context.Context) error {
+		return r.next.SetPassword(ctx, userID, passwordHash)
+	})
+}
+
+func (r *instrumentedUsers) SetColor(ctx context.Context, userID int, color string) error {
+	return observeErr(ctx, r.observer, "users", "SetColor", func(ctx context.Context) error {
+		return r.next.SetColor(ctx, userID, color)
+	})
+}
+
+func (r *instrumentedUsers) TransferList(ctx context.Context, listID, userID int) error {
+	return observeErr(ctx, r.observer, "users", "TransferList", func(ctx context.Context) error {
+		return r.next.TransferList(ctx, listID, userID)
+	})
+}
+
+func (r *instrumentedUsers) GetStats(ctx context.Context, now time.Time) (entity.StudioStats, error) {
+	return observe(ctx, r.observer, "users", "GetStats", func(ctx context.Context) (entity.StudioStats, error) {
+		return r.next.GetStats(ctx, now)
+	})
+}
@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+
+	"main.go/internal/entity"
+	apperrors "main.go/internal/errors"
+)
+
+type AuthorizationMemory struct {
+	store *Store
+}
+
+func NewAuthorizationMemory(store *Store) *AuthorizationMemory {
+	return &AuthorizationMemory{store: store}
+}
+
+// CreateUser stores the user, whose Password already holds the hash.
+// Usernames and colors are unique as in the users table.
+func (r *AuthorizationMemory) CreateUser(ctx context.Context, user entity.User) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.users {
+		if existing.Username == user.Username {
+			return 0, fieldConflict("username", "username_taken", "username is already taken")
+		}
+
+		if existing.Color == user.Color {
+			return 0, fieldConflict("color", "color_taken", "color is already taken")
+		}
+	}
+
+	user.ID = r.store.nextUserID
+	r.store.nextUserID++
+	r.store.users[user.ID] = user
+
+	return user.ID, nil
+}
+
+func (r *AuthorizationMemory) GetUser(ctx context.Context, username, password string) (entity.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, user := range r.store.users {
+		if user.Username == username && user.Password == password {
+			return entity.User{ID: user.ID, Name: "", Color: "", Username: "", Password: ""}, nil
+		}
+	}
+
+	return entity.User{}, notFound("user")
+}
+
+// IsActive reports whether the user exists; the memory store can't disable
+// users.
+func (r *AuthorizationMemory) IsActive(ctx context.Context, userID int) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	_, ok := r.store.users[userID]
+
+	return ok, nil
+}
+
+// fieldConflict is the error the postgres package returns for a unique
+// violation on the field.
+func fieldConflict(field, code, message string) error {
+	err := apperrors.Conflict(code, message, nil)
+	err.Details = []apperrors.FieldError{{Field: field, Code: code, Message: message}}
+
+	return err
+}
@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"main.go/internal/entity"
+)
+
+type idempotencyKey struct {
+	userID int
+	key    string
+}
+
+// IdempotencyMemory keeps idempotency keys in process memory. Like
+// RateLimitMemory, it only fits deployments with a single replica.
+type IdempotencyMemory struct {
+	mu      sync.Mutex
+	records map[idempotencyKey]entity.IdempotencyKey
+}
+
+func NewIdempotencyMemory() *IdempotencyMemory {
+	return &IdempotencyMemory{mu: sync.Mutex{}, records: make(map[idempotencyKey]entity.IdempotencyKey)}
+}
+
+// Create claims the key for the user and reports whether it was free.
+func (r *IdempotencyMemory) Create(ctx context.Context, record entity.IdempotencyKey) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := idempotencyKey{userID: record.UserID, key: record.Key}
+	if _, ok := r.records[id]; ok {
+		return false, nil
+	}
+
+	record.StatusCode = 0
+	record.Response = nil
+	record.CreatedAt = time.Now()
+	r.records[id] = record
+
+	return true, nil
+}
+
+// Reclaim takes over an unfinished claim on the key for the same request made
+// before claimedBefore and reports whether there was one.
+func (r *IdempotencyMemory) Reclaim(
+	ctx context.Context,
+	record entity.IdempotencyKey,
+	claimedBefore time.Time,
+) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := idempotencyKey{userID: record.UserID, key: record.Key}
+
+	stored, ok := r.records[id]
+	if !ok || stored.RequestHash != record.RequestHash || stored.Completed() ||
+		!stored.CreatedAt.Before(claimedBefore) {
+		return false, nil
+	}
+
+	stored.CreatedAt = time.Now()
+	r.records[id] = stored
+
+	return true, nil
+}
+
+// Get returns sql.ErrNoRows for a key nobody claimed, as the databases do.
+func (r *IdempotencyMemory) Get(ctx context.Context, userID int, key string) (entity.IdempotencyKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.records[idempotencyKey{userID: userID, key: key}]
+	if !ok {
+		return entity.IdempotencyKey{}, sql.ErrNoRows
+	}
+
+	stored.Response = append([]byte(nil), stored.Response...)
+
+	return stored, nil
+}
+
+func (r *IdempotencyMemory) SaveResponse(ctx context.Context, record entity.IdempotencyKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := idempotencyKey{userID: record.UserID, key: record.Key}
+
+	stored, ok := r.records[id]
+	if !ok {
+		return nil
+	}
+
+	stored.StatusCode = record.StatusCode
+	stored.Response = append([]byte(nil), record.Response...)
+	r.records[id] = stored
+
+	return nil
+}
+
+func (r *IdempotencyMemory) Delete(ctx context.Context, userID int, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.records, idempotencyKey{userID: userID, key: key})
+
+	return nil
+}
+
+func (r *IdempotencyMemory) DeleteStale(ctx context.Context, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, stored := range r.records {
+		if stored.CreatedAt.Before(before) {
+			delete(r.records, id)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,14 @@
+package memory_test
+
+import (
+	"testing"
+
+	"main.go/internal/repository"
+	"main.go/internal/repository/repotest"
+)
+
+func TestRepository(t *testing.T) {
+	repotest.RunCore(t, func(*testing.T) *repository.Repository {
+		return repository.NewMemoryRepository()
+	})
+}
@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"main.go/internal/entity"
+)
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// RateLimitMemory keeps token buckets in process memory. Every instance
+// counts on its own, so it only fits deployments with a single replica.
+type RateLimitMemory struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+func NewRateLimitMemory() *RateLimitMemory {
+	return &RateLimitMemory{mu: sync.Mutex{}, buckets: make(map[string]*bucket)}
+}
+
+func (r *RateLimitMemory) Take(
+	ctx context.Context,
+	key string,
+	limit entity.RateLimit,
+	now time.Time,
+) (entity.RateDecision, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.buckets[key]
+	if !ok {
+		current = &bucket{tokens: float64(limit.Burst), updatedAt: now}
+		r.buckets[key] = current
+	}
+
+	elapsed := math.Max(now.Sub(current.updatedAt).Seconds(), 0)
+	current.tokens = math.Min(float64(limit.Burst), current.tokens+elapsed*limit.RefillRate())
+	current.updatedAt = now
+
+	allowed := current.tokens >= 1
+	if allowed {
+		current.tokens--
+	}
+
+	return entity.NewRateDecision(allowed, current.tokens, limit), nil
+}
+
+func (r *RateLimitMemory) DeleteStale(ctx context.Context, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, current := range r.buckets {
+		if current.updatedAt.Before(before) {
+			delete(r.buckets, key)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"database/sql"
+	"sync"
+
+	"main.go/internal/entity"
+	apperrors "main.go/internal/errors"
+)
+
+type listRecord struct {
+	list   entity.TimeslotsList
+	userID int
+}
+
+type itemRecord struct {
+	item   entity.TimeslotItem
+	listID int
+}
+
+// Store holds the users, lists and items the memory repositories share, so
+// that ownership works across them as the Postgres joins do. Data lives
+// until the process stops.
+type Store struct {
+	mu         sync.RWMutex
+	users      map[int]entity.User
+	lists      map[int]*listRecord
+	items      map[int]*itemRecord
+	nextUserID int
+	nextListID int
+	nextItemID int
+}
+
+func NewStore() *Store {
+	return &Store{
+		mu:         sync.RWMutex{},
+		users:      make(map[int]entity.User),
+		lists:      make(map[int]*listRecord),
+		items:      make(map[int]*itemRecord),
+		nextUserID: 1,
+		nextListID: 1,
+		nextItemID: 1,
+	}
+}
+
+// ownedList returns the list when userID owns it. Callers hold the lock.
+func (s *Store) ownedList(userID, listID int) (*listRecord, bool) {
+	record, ok := s.lists[listID]
+	if !ok || record.userID != userID {
+		return nil, false
+	}
+
+	return record, true
+}
+
+// ownedItem returns the item when userID owns its list. Callers hold the
+// lock.
+func (s *Store) ownedItem(userID, itemID int) (*itemRecord, bool) {
+	record, ok := s.items[itemID]
+	if !ok {
+		return nil, false
+	}
+
+	if _, ok = s.ownedList(userID, record.listID); !ok {
+		return nil, false
+	}
+
+	return record, true
+}
+
+// notFound matches what translateError makes of sql.ErrNoRows, so services
+// can't tell the stores apart.
+func notFound(resource string) error {
+	return apperrors.NotFound(resource+"_not_found", resource+" not found", sql.ErrNoRows)
+}
+
+// limit cuts rows down to n the way LIMIT does.
+func limit[T any](rows []T, n int) []T {
+	if n < 0 || len(rows) <= n {
+		return rows
+	}
+
+	return rows[:n]
+}
@@ -0,0 +1,254 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"main.go/internal/entity"
+	apperrors "main.go/internal/errors"
+)
+
+type TimeslotItemMemory struct {
+	store *Store
+}
+
+func NewTimeslotItemMemory(store *Store) *TimeslotItemMemory {
+	return &TimeslotItemMemory{store: store}
+}
+
+// Create adds the item to the list. Ownership of the list is checked by the
+// service, as for Postgres; only a missing list is refused.
+func (r *TimeslotItemMemory) Create(ctx context.Context, listID int, item entity.TimeslotItem) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.lists[listID]; !ok {
+		return 0, apperrors.Validation("item_invalid_reference", "list does not exist", nil)
+	}
+
+	stored := entity.TimeslotItem{
+		ID:          r.store.nextItemID,
+		Title:       item.Title,
+		Description: item.Description,
+		Start:       item.Start,
+		End:         item.End,
+		Done:        false,
+		Cancelled:   false,
+		Username:    "",
+		Color:       "",
+		ListID:      0,
+		Version:     1,
+	}
+	r.store.nextItemID++
+	r.store.items[stored.ID] = &itemRecord{item: stored, listID: listID}
+
+	return stored.ID, nil
+}
+
+func (r *TimeslotItemMemory) GetAll(
+	ctx context.Context,
+	userID, listID int,
+	filter entity.ItemsFilter,
+) ([]entity.TimeslotItem, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if _, ok := r.store.ownedList(userID, listID); !ok {
+		return nil, nil
+	}
+
+	var items []entity.TimeslotItem
+
+	for _, record := range r.store.items {
+		if record.listID != listID {
+			continue
+		}
+
+		item := r.withOwner(record, false)
+		if matches(item, filter) {
+			items = append(items, item)
+		}
+	}
+
+	return limit(sortItems(items), filter.Limit), nil
+}
+
+func (r *TimeslotItemMemory) GetByID(ctx context.Context, userID, itemID int) (entity.TimeslotItem, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	record, ok := r.store.ownedItem(userID, itemID)
+	if !ok {
+		return entity.TimeslotItem{}, notFound("item")
+	}
+
+	item := r.withOwner(record, true)
+	item.ListID = record.listID
+
+	return item, nil
+}
+
+// Update changes the item when its version still equals version, a zero
+// version skips the check.
+func (r *TimeslotItemMemory) Update(
+	ctx context.Context,
+	userID, itemID int,
+	input entity.UpdateItemInput,
+	version int,
+) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	record, ok := r.store.ownedItem(userID, itemID)
+	if !ok || (version != 0 && record.item.Version != version) {
+		return notFound("item")
+	}
+
+	item := &record.item
+
+	if input.Title != nil {
+		item.Title = *input.Title
+	}
+
+	if input.Description != nil {
+		item.Description = *input.Description
+	}
+
+	if input.Start != nil {
+		item.Start = *input.Start
+	}
+
+	if input.End != nil {
+		item.End = *input.End
+	}
+
+	if input.Done != nil {
+		item.Done = *input.Done
+	}
+
+	if input.Cancelled != nil {
+		item.Cancelled = *input.Cancelled
+	}
+
+	item.Version++
+
+	return nil
+}
+
+func (r *TimeslotItemMemory) Delete(ctx context.Context, userID, itemID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.ownedItem(userID, itemID); ok {
+		delete(r.store.items, itemID)
+	}
+
+	return nil
+}
+
+// GetByRange returns the items of every user lying within the range, as the
+// studio calendar shows them.
+func (r *TimeslotItemMemory) GetByRange(
+	ctx context.Context,
+	input entity.ItemsByRange,
+) ([]entity.TimeslotItem, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var items []entity.TimeslotItem
+
+	for _, record := range r.store.items {
+		if record.item.Start.Before(input.Start) || record.item.End.After(input.End) {
+			continue
+		}
+
+		item := r.withOwner(record, true)
+		if matches(item, input.ItemsFilter) {
+			items = append(items, item)
+		}
+	}
+
+	return limit(sortItems(items), input.Limit), nil
+}
+
+// CountUpcoming counts the items that are neither done nor cancelled and
+// start within [from, to).
+func (r *TimeslotItemMemory) CountUpcoming(ctx context.Context, from, to time.Time) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	count := 0
+
+	for _, record := range r.store.items {
+		item := record.item
+		if !item.Start.Before(from) && item.Start.Before(to) && !item.Done && !item.Cancelled {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// withOwner copies the item with the username, and the color when asked,
+// of the user owning its list. Callers hold the lock.
+func (r *TimeslotItemMemory) withOwner(record *itemRecord, withColor bool) entity.TimeslotItem {
+	item := record.item
+	owner := r.store.users[r.store.lists[record.listID].userID]
+
+	item.Username = owner.Username
+	if withColor {
+		item.Color = owner.Color
+	}
+
+	return item
+}
+
+// matches applies the optional filters and the keyset condition of
+// itemsFilterConditions.
+func matches(item entity.TimeslotItem, filter entity.ItemsFilter) bool {
+	if !containsFold(item.Title, filter.Title) {
+		return false
+	}
+
+	switch filter.Status {
+	case entity.ItemStatusDone:
+		if !item.Done {
+			return false
+		}
+	case entity.ItemStatusOpen:
+		if item.Done || item.Cancelled {
+			return false
+		}
+	case entity.ItemStatusCancelled:
+		if !item.Cancelled {
+			return false
+		}
+	}
+
+	if filter.Artist != "" && item.Username != filter.Artist {
+		return false
+	}
+
+	if !filter.After.IsZero() {
+		after := item.Start.After(filter.After.Start) ||
+			(item.Start.Equal(filter.After.Start) && item.ID > filter.After.ID)
+		if !after {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sortItems(items []entity.TimeslotItem) []entity.TimeslotItem {
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].Start.Equal(items[j].Start) {
+			return items[i].Start.Before(items[j].Start)
+		}
+
+		return items[i].ID < items[j].ID
+	})
+
+	return items
+}
@@ -0,0 +1,121 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"main.go/internal/entity"
+)
+
+type TimeslotListMemory struct {
+	store *Store
+}
+
+func NewTimeslotListMemory(store *Store) *TimeslotListMemory {
+	return &TimeslotListMemory{store: store}
+}
+
+func (r *TimeslotListMemory) Create(ctx context.Context, userID int, list entity.TimeslotsList) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	list.ID = r.store.nextListID
+	list.Version = 1
+	r.store.nextListID++
+	r.store.lists[list.ID] = &listRecord{list: list, userID: userID}
+
+	return list.ID, nil
+}
+
+func (r *TimeslotListMemory) GetAll(
+	ctx context.Context,
+	userID int,
+	filter entity.ListsFilter,
+) ([]entity.TimeslotsList, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var lists []entity.TimeslotsList
+
+	for _, record := range r.store.lists {
+		if record.userID != userID ||
+			!containsFold(record.list.Title, filter.Title) ||
+			record.list.ID <= filter.After.ID {
+			continue
+		}
+
+		lists = append(lists, record.list)
+	}
+
+	sort.Slice(lists, func(i, j int) bool { return lists[i].ID < lists[j].ID })
+
+	return limit(lists, filter.Limit), nil
+}
+
+func (r *TimeslotListMemory) GetByID(ctx context.Context, userID, listID int) (entity.TimeslotsList, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	record, ok := r.store.ownedList(userID, listID)
+	if !ok {
+		return entity.TimeslotsList{}, notFound("list")
+	}
+
+	return record.list, nil
+}
+
+// Update changes the list when its version still equals version, a zero
+// version skips the check.
+func (r *TimeslotListMemory) Update(
+	ctx context.Context,
+	userID, listID int,
+	input entity.UpdateListInput,
+	version int,
+) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	record, ok := r.store.ownedList(userID, listID)
+	if !ok || (version != 0 && record.list.Version != version) {
+		return notFound("list")
+	}
+
+	if input.Title != nil {
+		record.list.Title = *input.Title
+	}
+
+	if input.Description != nil {
+		record.list.Description = *input.Description
+	}
+
+	record.list.Version++
+
+	return nil
+}
+
+// Delete removes the list and its items. Deleting a list the user doesn't
+// own is not an error, as in Postgres.
+func (r *TimeslotListMemory) Delete(ctx context.Context, userID, listID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.ownedList(userID, listID); !ok {
+		return nil
+	}
+
+	delete(r.store.lists, listID)
+
+	for itemID, record := range r.store.items {
+		if record.listID == listID {
+			delete(r.store.items, itemID)
+		}
+	}
+
+	return nil
+}
+
+// containsFold matches like the ILIKE '%' || pattern || '%' filters.
+func containsFold(s, pattern string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(pattern))
+}
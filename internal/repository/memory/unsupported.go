@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"main.go/internal/entity"
+	apperrors "main.go/internal/errors"
+)
+
+//nolint:gochecknoglobals // sentinel error
+var errUnsupported = apperrors.Unsupported(
+	"unsupported_by_store", "not available with the configured store", nil)
+
+// Unsupported stands in for the repositories a store doesn't implement, such
+// as search, bookings and the waitlist in memory. Calls fail with an
+// unsupported error, except for the cleanup the workers run, which has
+// nothing to clean.
+type Unsupported struct{}
+
+func (Unsupported) Search(context.Context, int, string, int) ([]entity.SearchResult, error) {
+	return nil, errUnsupported
+}
+
+func (Unsupported) CreateEntry(context.Context, entity.WaitlistEntry) (int, error) {
+	return 0, errUnsupported
+}
+
+func (Unsupported) GetEntries(context.Context, int) ([]entity.WaitlistEntry, error) {
+	return nil, errUnsupported
+}
+
+func (Unsupported) DeleteEntry(context.Context, int) error {
+	return errUnsupported
+}
+
+func (Unsupported) CreateOffer(context.Context, entity.FreedSlot, time.Duration) (entity.WaitlistOffer, error) {
+	return entity.WaitlistOffer{}, errUnsupported
+}
+
+func (Unsupported) GetOffers(context.Context, int) ([]entity.WaitlistOffer, error) {
+	return nil, errUnsupported
+}
+
+func (Unsupported) AcceptOffer(context.Context, int) (int, error) {
+	return 0, errUnsupported
+}
+
+func (Unsupported) DeclineOffer(context.Context, int) (entity.WaitlistOffer, error) {
+	return entity.WaitlistOffer{}, errUnsupported
+}
+
+func (Unsupported) ExpireOffers(context.Context) ([]entity.WaitlistOffer, error) {
+	return nil, nil
+}
+
+func (Unsupported) GetArtists(context.Context) ([]entity.Artist, error) {
+	return nil, errUnsupported
+}
+
+func (Unsupported) GetBusy(context.Context, int, time.Time, time.Time) ([]entity.TimeRange, error) {
+	return nil, errUnsupported
+}
+
+func (Unsupported) CreateRequest(context.Context, entity.BookingRequest) (int, error) {
+	return 0, errUnsupported
+}
+
+func (Unsupported) CountRecentByIP(context.Context, string, time.Time) (int, error) {
+	return 0, errUnsupported
+}
+
+func (Unsupported) CountPendingByEmail(context.Context, string) (int, error) {
+	return 0, errUnsupported
+}
+
+func (Unsupported) GetRequests(context.Context, entity.BookingsFilter) ([]entity.BookingRequest, error) {
+	return nil, errUnsupported
+}
+
+func (Unsupported) ApproveRequest(context.Context, int, int) (int, error) {
+	return 0, errUnsupported
+}
+
+func (Unsupported) RejectRequest(context.Context, int) error {
+	return errUnsupported
+}
+
+func (Unsupported) GetUsers(context.Context) ([]entity.UserInfo, error) {
+	return nil, errUnsupported
+}
+
+func (Unsupported) SetDisabled(context.Context, int, bool) error {
+	return errUnsupported
+}
+
+func (Unsupported) DeleteUser(context.Context, int) error {
+	return errUnsupported
+}
+
+func (Unsupported) SetPassword(context.Context, int, string) error {
+	return errUnsupported
+}
+
+func (Unsupported) SetColor(context.Context, int, string) error {
+	return errUnsupported
+}
+
+func (Unsupported) TransferList(context.Context, int, int) error {
+	return errUnsupported
+}
+
+func (Unsupported) GetStats(context.Context, time.Time) (entity.StudioStats, error) {
+	return entity.StudioStats{}, errUnsupported
+}
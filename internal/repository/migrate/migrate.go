@@ -0,0 +1,322 @@
+// Package migrate applies numbered up and down SQL files, keeping the
+// version in the table the migrate CLI uses.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// migrationsTable is the table the migrate CLI keeps its version in, so
+// databases it set up carry on from where it left them.
+const migrationsTable = "schema_migrations"
+
+//nolint:gochecknoglobals // compiled once
+var migrationFile = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+var ErrDirty = errors.New("database is dirty")
+
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Version uint
+	Dirty   bool
+	Latest  uint
+	Pending []Migration
+}
+
+// Dialect holds what differs between databases. Lock and Unlock take and
+// release a lock that keeps replicas starting together from migrating at the
+// same time, they are skipped when empty. TableExists gets the table name as
+// its only argument.
+type Dialect struct {
+	Lock        string
+	Unlock      string
+	TableExists string
+}
+
+// Migrator moves the schema between the versions of the migrations it was
+// built with. Every step runs in its own transaction together with the
+// version bump.
+type Migrator struct {
+	db         *sqlx.DB
+	dialect    Dialect
+	migrations []Migration
+}
+
+func New(db *sqlx.DB, fsys fs.FS, dialect Dialect) (*Migrator, error) {
+	migrations, err := readMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, dialect: dialect, migrations: migrations}, nil
+}
+
+func readMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[uint]*Migration)
+
+	for _, entry := range entries {
+		match := migrationFile.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 0)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("bad migration version in %s", entry.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[uint(version)]
+		if !ok {
+			migration = &Migration{Version: uint(version), Name: match[2], Up: "", Down: ""}
+			byVersion[uint(version)] = migration
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d needs both an up and a down file", migration.Version)
+		}
+
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+func (m *Migrator) Latest() uint {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+func (m *Migrator) Status(ctx context.Context) (Status, error) {
+	version, dirty, err := m.version(ctx)
+	if err != nil {
+		return Status{}, err
+	}
+
+	var pending []Migration
+
+	for _, migration := range m.migrations {
+		if migration.Version > version {
+			pending = append(pending, migration)
+		}
+	}
+
+	return Status{Version: version, Dirty: dirty, Latest: m.Latest(), Pending: pending}, nil
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.To(ctx, m.Latest())
+}
+
+// Down reverts the last applied migration.
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.locked(ctx, func() error {
+		version, err := m.cleanVersion(ctx)
+		if err != nil || version == 0 {
+			return err
+		}
+
+		return m.migrate(ctx, version, m.previous(version))
+	})
+}
+
+// To migrates up or down to version; 0 reverts everything.
+func (m *Migrator) To(ctx context.Context, version uint) error {
+	if version != 0 && m.index(version) < 0 {
+		return fmt.Errorf("no migration with version %d", version)
+	}
+
+	return m.locked(ctx, func() error {
+		current, err := m.cleanVersion(ctx)
+		if err != nil {
+			return err
+		}
+
+		return m.migrate(ctx, current, version)
+	})
+}
+
+func (m *Migrator) migrate(ctx context.Context, from, to uint) error {
+	for from != to {
+		var (
+			migration Migration
+			query     string
+			next      uint
+		)
+
+		if from < to {
+			migration = m.migrations[m.index(from)+1]
+			query, next = migration.Up, migration.Version
+		} else {
+			migration = m.migrations[m.index(from)]
+			query, next = migration.Down, m.previous(from)
+		}
+
+		if err := m.step(ctx, query, next); err != nil {
+			return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		from = next
+	}
+
+	return nil
+}
+
+func (m *Migrator) step(ctx context.Context, query string, version uint) error {
+	tx, err := m.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, query); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", migrationsTable)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if version > 0 {
+		query := fmt.Sprintf("INSERT INTO %s (version, dirty) VALUES ($1, false)", migrationsTable)
+		if _, err = tx.ExecContext(ctx, query, version); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// locked runs fn holding the migration lock, waiting for it if another
+// replica has it.
+func (m *Migrator) locked(ctx context.Context, fn func() error) error {
+	if m.dialect.Lock != "" {
+		conn, err := m.db.Connx(ctx)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		if _, err = conn.ExecContext(ctx, m.dialect.Lock); err != nil {
+			return err
+		}
+
+		defer func() {
+			_, _ = conn.ExecContext(context.WithoutCancel(ctx), m.dialect.Unlock)
+		}()
+	}
+
+	query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (version bigint PRIMARY KEY, dirty boolean NOT NULL)",
+		migrationsTable)
+	if _, err := m.db.ExecContext(ctx, query); err != nil {
+		return err
+	}
+
+	return fn()
+}
+
+func (m *Migrator) version(ctx context.Context) (uint, bool, error) {
+	var exists bool
+
+	if err := m.db.GetContext(ctx, &exists, m.dialect.TableExists, migrationsTable); err != nil {
+		return 0, false, err
+	}
+
+	if !exists {
+		return 0, false, nil
+	}
+
+	var row struct {
+		Version uint `db:"version"`
+		Dirty   bool `db:"dirty"`
+	}
+
+	query := fmt.Sprintf("SELECT version, dirty FROM %s LIMIT 1", migrationsTable)
+	if err := m.db.GetContext(ctx, &row, query); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, nil
+		}
+
+		return 0, false, err
+	}
+
+	return row.Version, row.Dirty, nil
+}
+
+// cleanVersion returns the current version, refusing to go on from a
+// migration the migrate CLI left half applied.
+func (m *Migrator) cleanVersion(ctx context.Context) (uint, error) {
+	version, dirty, err := m.version(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if dirty {
+		return 0, fmt.Errorf("%w at version %d, fix it by hand first", ErrDirty, version)
+	}
+
+	if version != 0 && m.index(version) < 0 {
+		return 0, fmt.Errorf("database is at version %d, which this build doesn't know", version)
+	}
+
+	return version, nil
+}
+
+func (m *Migrator) index(version uint) int {
+	for i, migration := range m.migrations {
+		if migration.Version == version {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (m *Migrator) previous(version uint) uint {
+	if i := m.index(version); i > 0 {
+		return m.migrations[i-1].Version
+	}
+
+	return 0
+}
@@ -1,26 +1,29 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
 	"main.go/internal/entity"
+	"main.go/internal/repository/sqltrace"
 )
 
 type Authorization interface {
-	CreateUser(user entity.User) (int, error)
-	GetUser(username, password string) (entity.User, error)
+	CreateUser(ctx context.Context, user entity.User) (int, error)
+	GetUser(ctx context.Context, username, password string) (entity.User, error)
+	IsActive(ctx context.Context, userID int) (bool, error)
 }
 
 type AuthorizationPostgres struct {
-	db *sqlx.DB
+	db *sqltrace.DB
 }
 
-func NewAuthorizationPostgres(db *sqlx.DB) *AuthorizationPostgres {
-	return &AuthorizationPostgres{db: db}
+func NewAuthorizationPostgres(db *sqlx.DB, timeouts sqltrace.Timeouts) *AuthorizationPostgres {
+	return &AuthorizationPostgres{db: newTracedDB(db, timeouts)}
 }
 
-func (r *AuthorizationPostgres) CreateUser(user entity.User) (int, error) {
+func (r *AuthorizationPostgres) CreateUser(ctx context.Context, user entity.User) (int, error) {
 	var userID int
 
 	query := fmt.Sprintf(
@@ -31,16 +34,16 @@ func (r *AuthorizationPostgres) CreateUser(user entity.User) (int, error) {
 			    id`,
 		UsersTable,
 	)
-	row := r.db.QueryRow(query, user.Name, user.Color, user.Username, user.Password)
+	row := r.db.QueryRowContext(ctx, query, user.Name, user.Color, user.Username, user.Password)
 
 	if err := row.Scan(&userID); err != nil {
-		return 0, err
+		return 0, translateError(err, "user")
 	}
 
 	return userID, nil
 }
 
-func (r *AuthorizationPostgres) GetUser(username, password string) (entity.User, error) {
+func (r *AuthorizationPostgres) GetUser(ctx context.Context, username, password string) (entity.User, error) {
 	var user entity.User
 
 	query := fmt.Sprintf(`
@@ -50,8 +53,19 @@ func (r *AuthorizationPostgres) GetUser(username, password string) (entity.User,
 		    %s
 		WHERE
 		    username = $1
-		    AND password_hash = $2`, UsersTable)
-	err := r.db.Get(&user, query, username, password)
+		    AND password_hash = $2
+		    AND NOT disabled`, UsersTable)
+	err := r.db.GetContext(ctx, &user, query, username, password)
 
-	return user, err
+	return user, translateError(err, "user")
+}
+
+// IsActive reports whether the user still exists and is not disabled.
+func (r *AuthorizationPostgres) IsActive(ctx context.Context, userID int) (bool, error) {
+	var active bool
+
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE id = $1 AND NOT disabled)`, UsersTable)
+	err := r.db.GetContext(ctx, &active, query, userID)
+
+	return active, err
 }
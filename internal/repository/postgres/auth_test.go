@@ -1,13 +1,16 @@
 package postgres_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
 
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 	sqlmock "github.com/zhashkevych/go-sqlxmock"
 	"main.go/internal/entity"
+	apperrors "main.go/internal/errors"
 	"main.go/internal/repository"
 	"main.go/internal/repository/postgres"
 )
@@ -19,7 +22,7 @@ func TestAuthPostgres_CreateUser(t *testing.T) {
 	}
 	defer dataBase.Close()
 
-	rep := repository.NewRepository(dataBase)
+	rep := repository.NewRepository(dataBase, repository.QueryTimeouts{})
 	query := fmt.Sprintf( //nolint:perfsprint // general style for queries
 		`
 			INSERT INTO %s`,
@@ -36,25 +39,28 @@ func TestAuthPostgres_CreateUser(t *testing.T) {
 		input        input
 		want         int
 		wantErr      bool
+		wantErrIs    error
 	}{
 		{
 			name: "OK",
 			mockBehavior: func(input input, userID int) {
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(userID)
 				mock.ExpectQuery(query).
-					WithArgs(input.user.Name, input.user.Username, input.user.Password).
+					WithArgs(input.user.Name, input.user.Color, input.user.Username, input.user.Password).
 					WillReturnRows(rows)
 			},
 			input: input{
 				user: entity.User{
 					ID:       0,
 					Name:     "name",
+					Color:    "red",
 					Username: "username",
 					Password: "password",
 				},
 			},
-			want:    2,
-			wantErr: false,
+			want:      2,
+			wantErr:   false,
+			wantErrIs: nil,
 		},
 		{
 			name: "Empty Fields",
@@ -63,19 +69,45 @@ func TestAuthPostgres_CreateUser(t *testing.T) {
 					AddRow(userID).
 					RowError(0, errors.New("some error"))
 				mock.ExpectQuery(query).
-					WithArgs(input.user.Name, input.user.Username, input.user.Password).
+					WithArgs(input.user.Name, input.user.Color, input.user.Username, input.user.Password).
 					WillReturnRows(rows)
 			},
 			input: input{
 				user: entity.User{
 					ID:       0,
 					Name:     "",
+					Color:    "red",
 					Username: "username",
 					Password: "password",
 				},
 			},
-			want:    0,
-			wantErr: true,
+			want:      0,
+			wantErr:   true,
+			wantErrIs: nil,
+		},
+		{
+			name: "Color Taken",
+			mockBehavior: func(input input, _ int) {
+				mock.ExpectQuery(query).
+					WithArgs(input.user.Name, input.user.Color, input.user.Username, input.user.Password).
+					WillReturnError(&pq.Error{
+						Code:       "23505",
+						Detail:     "Key (color)=(red) already exists.",
+						Constraint: "users_color_key",
+					})
+			},
+			input: input{
+				user: entity.User{
+					ID:       0,
+					Name:     "name",
+					Color:    "red",
+					Username: "username",
+					Password: "password",
+				},
+			},
+			want:      0,
+			wantErr:   true,
+			wantErrIs: apperrors.Conflict("color_taken", "", nil),
 		},
 	}
 
@@ -83,9 +115,12 @@ func TestAuthPostgres_CreateUser(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mockBehavior(testCase.input, testCase.want)
 
-			got, err1 := rep.CreateUser(testCase.input.user)
+			got, err1 := rep.CreateUser(context.Background(), testCase.input.user)
 			if testCase.wantErr {
 				require.Error(t, err1)
+				if testCase.wantErrIs != nil {
+					require.ErrorIs(t, err1, testCase.wantErrIs)
+				}
 			} else {
 				require.NoError(t, err1)
 				require.Equal(t, testCase.want, got)
@@ -102,7 +137,7 @@ func TestAuthPostgres_GetUser(t *testing.T) {
 	}
 	defer dataBase.Close()
 
-	rep := repository.NewRepository(dataBase)
+	rep := repository.NewRepository(dataBase, repository.QueryTimeouts{})
 	query := fmt.Sprintf(`
 		SELECT
 		    (.+)
@@ -194,7 +229,7 @@ func TestAuthPostgres_GetUser(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mockBehavior(testCase.input)
 
-			got, err1 := rep.GetUser(testCase.input.username, testCase.input.passwordHash)
+			got, err1 := rep.GetUser(context.Background(), testCase.input.username, testCase.input.passwordHash)
 			if testCase.wantErr {
 				require.Error(t, err1)
 			} else {
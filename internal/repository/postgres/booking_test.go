@@ -0,0 +1,138 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"main.go/internal/entity"
+	"main.go/internal/repository"
+	"main.go/internal/repository/postgres"
+)
+
+func TestBookingPostgres_GetBusy(t *testing.T) {
+	dataBase, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer dataBase.Close()
+
+	rep := repository.NewRepository(dataBase, repository.QueryTimeouts{})
+	query := fmt.Sprintf(
+		`
+			SELECT
+			    (.+)
+			FROM
+			    %s ti
+			    INNER JOIN %s li ON (.+)
+			    INNER JOIN %s ul ON (.+)
+			WHERE (.+) AND NOT ti.cancelled`,
+		postgres.TimeslotsItemsTable,
+		postgres.ListsItemsTable,
+		postgres.UsersListsTable,
+	)
+
+	timeNow := time.Now()
+
+	testTable := []struct {
+		name         string
+		mockBehavior func()
+		want         []entity.TimeRange
+		wantErr      bool
+	}{
+		{
+			name: "OK",
+			mockBehavior: func() {
+				rows := sqlmock.NewRows([]string{"beginning", "finish"}).
+					AddRow(timeNow, timeNow.Add(time.Hour))
+
+				mock.ExpectQuery(query).
+					WithArgs(1, timeNow, timeNow.Add(24*time.Hour)).
+					WillReturnRows(rows)
+			},
+			want: []entity.TimeRange{
+				{Start: timeNow, End: timeNow.Add(time.Hour)},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Free Day",
+			mockBehavior: func() {
+				mock.ExpectQuery(query).
+					WithArgs(1, timeNow, timeNow.Add(24*time.Hour)).
+					WillReturnRows(sqlmock.NewRows([]string{"beginning", "finish"}))
+			},
+			want:    nil,
+			wantErr: false,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior()
+
+			got, err1 := rep.Booking.GetBusy(context.Background(), 1, timeNow, timeNow.Add(24*time.Hour))
+			if testCase.wantErr {
+				require.Error(t, err1)
+			} else {
+				require.NoError(t, err1)
+				require.Equal(t, testCase.want, got)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestBookingPostgres_RejectRequest(t *testing.T) {
+	dataBase, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer dataBase.Close()
+
+	rep := repository.NewRepository(dataBase, repository.QueryTimeouts{})
+	query := fmt.Sprintf(`UPDATE %s SET status`, postgres.BookingRequestsTable) //nolint:perfsprint // general style for queries
+
+	testTable := []struct {
+		name         string
+		mockBehavior func()
+		wantErr      error
+	}{
+		{
+			name: "OK",
+			mockBehavior: func() {
+				mock.ExpectQuery(query).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Not Pending",
+			mockBehavior: func() {
+				mock.ExpectQuery(query).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior()
+
+			err1 := rep.Booking.RejectRequest(context.Background(), 1)
+			if testCase.wantErr != nil {
+				require.ErrorIs(t, err1, testCase.wantErr)
+			} else {
+				require.NoError(t, err1)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	apperrors "main.go/internal/errors"
+)
+
+const (
+	pqUniqueViolation     = "23505"
+	pqForeignKeyViolation = "23503"
+	pqCheckViolation      = "23514"
+	pqNotNullViolation    = "23502"
+)
+
+// fieldConflicts maps unique constraints a client can get around by changing
+// a single field to the error telling them which.
+//
+//nolint:gochecknoglobals // lookup table
+var fieldConflicts = map[string]apperrors.FieldError{
+	"users_username_key": {Field: "username", Code: "username_taken", Message: "username is already taken"},
+	"users_color_key":    {Field: "color", Code: "color_taken", Message: "color is already taken"},
+}
+
+// translateError turns driver errors about the resource into typed errors
+// the service and REST layers understand. Other errors pass through. The
+// client only sees the resource and, for known constraints, the field; the
+// driver's detail, which quotes the offending values, stays in the wrapped
+// error for the logs.
+func translateError(err error, resource string) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperrors.NotFound(resource+"_not_found", resource+" not found", err)
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	if pqErr.Detail != "" {
+		err = fmt.Errorf("%w: %s", err, pqErr.Detail)
+	}
+
+	switch pqErr.Code {
+	case pqUniqueViolation:
+		if field, ok := fieldConflicts[pqErr.Constraint]; ok {
+			conflict := apperrors.Conflict(field.Code, field.Message, err)
+			conflict.Details = []apperrors.FieldError{field}
+
+			return conflict
+		}
+
+		return apperrors.Conflict(resource+"_already_exists", resource+" already exists", err)
+	case pqForeignKeyViolation:
+		return apperrors.Validation(resource+"_invalid_reference", resource+" refers to a missing record", err)
+	case pqCheckViolation, pqNotNullViolation:
+		return apperrors.Validation(resource+"_invalid", "invalid "+resource, err)
+	default:
+		return err
+	}
+}
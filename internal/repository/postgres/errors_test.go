@@ -0,0 +1,84 @@
+package postgres //nolint:testpackage // need to use translateError.
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	apperrors "main.go/internal/errors"
+)
+
+func TestTranslateError(t *testing.T) {
+	testTable := []struct {
+		name            string
+		err             *pq.Error
+		expectedCode    string
+		expectedMessage string
+		expectedDetails []apperrors.FieldError
+	}{
+		{
+			name: "Username Taken",
+			err: &pq.Error{
+				Code:       pqUniqueViolation,
+				Detail:     "Key (username)=(alice) already exists.",
+				Constraint: "users_username_key",
+			},
+			expectedCode:    "username_taken",
+			expectedMessage: "username is already taken",
+			expectedDetails: []apperrors.FieldError{
+				{Field: "username", Code: "username_taken", Message: "username is already taken"},
+			},
+		},
+		{
+			name: "Color Taken",
+			err: &pq.Error{
+				Code:       pqUniqueViolation,
+				Detail:     "Key (color)=(ff0000) already exists.",
+				Constraint: "users_color_key",
+			},
+			expectedCode:    "color_taken",
+			expectedMessage: "color is already taken",
+			expectedDetails: []apperrors.FieldError{
+				{Field: "color", Code: "color_taken", Message: "color is already taken"},
+			},
+		},
+		{
+			name: "Other Unique Constraint",
+			err: &pq.Error{
+				Code:       pqUniqueViolation,
+				Detail:     "Key (id)=(1) already exists.",
+				Constraint: "users_id_key",
+			},
+			expectedCode:    "user_already_exists",
+			expectedMessage: "user already exists",
+			expectedDetails: nil,
+		},
+		{
+			name: "Foreign Key",
+			err: &pq.Error{
+				Code:       pqForeignKeyViolation,
+				Detail:     "Key (list_id)=(7) is not present.",
+				Constraint: "",
+			},
+			expectedCode:    "user_invalid_reference",
+			expectedMessage: "user refers to a missing record",
+			expectedDetails: nil,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Call
+			err := translateError(testCase.err, "user")
+
+			// Assert
+			var serviceErr *apperrors.ServiceError
+			require.ErrorAs(t, err, &serviceErr)
+			require.Equal(t, testCase.expectedCode, serviceErr.Code)
+			require.Equal(t, testCase.expectedMessage, serviceErr.Message)
+			require.Equal(t, testCase.expectedDetails, serviceErr.Details)
+			require.ErrorIs(t, err, testCase.err)
+			require.Contains(t, serviceErr.Err.Error(), testCase.err.Detail)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"main.go/internal/entity"
+	"main.go/internal/repository/sqltrace"
+)
+
+const IdempotencyKeysTable = "idempotency_keys"
+
+type Idempotency interface {
+	Create(ctx context.Context, record entity.IdempotencyKey) (bool, error)
+	Reclaim(ctx context.Context, record entity.IdempotencyKey, claimedBefore time.Time) (bool, error)
+	Get(ctx context.Context, userID int, key string) (entity.IdempotencyKey, error)
+	SaveResponse(ctx context.Context, record entity.IdempotencyKey) error
+	Delete(ctx context.Context, userID int, key string) error
+	DeleteStale(ctx context.Context, before time.Time) error
+}
+
+type IdempotencyPostgres struct {
+	db *sqltrace.DB
+}
+
+func NewIdempotencyPostgres(db *sqlx.DB, timeouts sqltrace.Timeouts) *IdempotencyPostgres {
+	return &IdempotencyPostgres{db: newTracedDB(db, timeouts)}
+}
+
+// Create claims the key for the user and reports whether it was free.
+func (r *IdempotencyPostgres) Create(ctx context.Context, record entity.IdempotencyKey) (bool, error) {
+	query := fmt.Sprintf(
+		`
+			INSERT INTO %s (user_id, key, request_hash)
+			    VALUES ($1, $2, $3)
+			ON CONFLICT (user_id, key)
+			    DO NOTHING`,
+		IdempotencyKeysTable,
+	)
+
+	result, err := r.db.ExecContext(ctx, query, record.UserID, record.Key, record.RequestHash)
+	if err != nil {
+		return false, err
+	}
+
+	created, err := result.RowsAffected()
+
+	return created == 1, err
+}
+
+// Reclaim takes over an unfinished claim on the key for the same request made
+// before claimedBefore and reports whether there was one.
+func (r *IdempotencyPostgres) Reclaim(
+	ctx context.Context,
+	record entity.IdempotencyKey,
+	claimedBefore time.Time,
+) (bool, error) {
+	query := fmt.Sprintf(
+		`
+			UPDATE
+			    %s
+			SET
+			    created_at = now()
+			WHERE
+			    user_id = $1
+			    AND key = $2
+			    AND request_hash = $3
+			    AND status_code = 0
+			    AND created_at < $4`,
+		IdempotencyKeysTable,
+	)
+
+	result, err := r.db.ExecContext(ctx, query, record.UserID, record.Key, record.RequestHash, claimedBefore)
+	if err != nil {
+		return false, err
+	}
+
+	reclaimed, err := result.RowsAffected()
+
+	return reclaimed == 1, err
+}
+
+func (r *IdempotencyPostgres) Get(ctx context.Context, userID int, key string) (entity.IdempotencyKey, error) {
+	var record entity.IdempotencyKey
+
+	query := fmt.Sprintf(
+		`
+			SELECT
+			    user_id,
+			    key,
+			    request_hash,
+			    status_code,
+			    response,
+			    created_at
+			FROM
+			    %s
+			WHERE
+			    user_id = $1
+			    AND key = $2`,
+		IdempotencyKeysTable,
+	)
+	err := r.db.GetContext(ctx, &record, query, userID, key)
+
+	return record, err
+}
+
+func (r *IdempotencyPostgres) SaveResponse(ctx context.Context, record entity.IdempotencyKey) error {
+	query := fmt.Sprintf(
+		`UPDATE %s SET status_code = $3, response = $4 WHERE user_id = $1 AND key = $2`,
+		IdempotencyKeysTable,
+	)
+	_, err := r.db.ExecContext(ctx, query, record.UserID, record.Key, record.StatusCode, record.Response)
+
+	return err
+}
+
+func (r *IdempotencyPostgres) Delete(ctx context.Context, userID int, key string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE user_id = $1 AND key = $2`, IdempotencyKeysTable)
+	_, err := r.db.ExecContext(ctx, query, userID, key)
+
+	return err
+}
+
+func (r *IdempotencyPostgres) DeleteStale(ctx context.Context, before time.Time) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE created_at < $1`, IdempotencyKeysTable)
+	_, err := r.db.ExecContext(ctx, query, before)
+
+	return err
+}
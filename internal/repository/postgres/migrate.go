@@ -0,0 +1,24 @@
+package postgres
+
+import (
+	"fmt"
+	"io/fs"
+
+	"github.com/jmoiron/sqlx"
+	"main.go/internal/repository/migrate"
+)
+
+// migrationLockID keys the advisory lock that keeps replicas starting
+// together from migrating at the same time.
+const migrationLockID = 7_346_201_118
+
+//nolint:gochecknoglobals // constant queries
+var dialect = migrate.Dialect{
+	Lock:        fmt.Sprintf("SELECT pg_advisory_lock(%d)", migrationLockID),
+	Unlock:      fmt.Sprintf("SELECT pg_advisory_unlock(%d)", migrationLockID),
+	TableExists: "SELECT to_regclass($1) IS NOT NULL",
+}
+
+func NewMigrator(db *sqlx.DB, fsys fs.FS) (*migrate.Migrator, error) {
+	return migrate.New(db, fsys, dialect)
+}
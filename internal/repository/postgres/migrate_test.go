@@ -0,0 +1,149 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"main.go/internal/repository/migrate"
+	"main.go/internal/repository/postgres"
+	"main.go/schema"
+)
+
+func testMigrations() fstest.MapFS {
+	return fstest.MapFS{
+		"000001_init.up.sql":     {Data: []byte("create table a (id int);")},
+		"000001_init.down.sql":   {Data: []byte("drop table a;")},
+		"000002_second.up.sql":   {Data: []byte("create table b (id int);")},
+		"000002_second.down.sql": {Data: []byte("drop table b;")},
+		"schema.go":              {Data: []byte("package schema")},
+	}
+}
+
+func expectVersion(mock sqlmock.Sqlmock, version uint, dirty bool) {
+	mock.ExpectQuery(`SELECT to_regclass`).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`SELECT version, dirty FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(version, dirty))
+}
+
+func expectStep(mock sqlmock.Sqlmock, query string, version uint) {
+	mock.ExpectBegin()
+	mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM schema_migrations`).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if version > 0 {
+		mock.ExpectExec(`INSERT INTO schema_migrations`).WithArgs(version).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+
+	mock.ExpectCommit()
+}
+
+func TestMigrator(t *testing.T) {
+	testTable := []struct {
+		name         string
+		mockBehavior func(mock sqlmock.Sqlmock)
+		run          func(ctx context.Context, migrator *migrate.Migrator) error
+		wantErr      bool
+	}{
+		{
+			name: "Up From Scratch",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				expectVersion(mock, 0, false)
+				expectStep(mock, `create table a`, 1)
+				expectStep(mock, `create table b`, 2)
+			},
+			run: func(ctx context.Context, migrator *migrate.Migrator) error {
+				return migrator.Up(ctx)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Down One Step",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				expectVersion(mock, 2, false)
+				expectStep(mock, `drop table b`, 1)
+			},
+			run: func(ctx context.Context, migrator *migrate.Migrator) error {
+				return migrator.Down(ctx)
+			},
+			wantErr: false,
+		},
+		{
+			name: "To Zero",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				expectVersion(mock, 2, false)
+				expectStep(mock, `drop table b`, 1)
+				expectStep(mock, `drop table a`, 0)
+			},
+			run: func(ctx context.Context, migrator *migrate.Migrator) error {
+				return migrator.To(ctx, 0)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Dirty",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				expectVersion(mock, 1, true)
+			},
+			run: func(ctx context.Context, migrator *migrate.Migrator) error {
+				return migrator.Up(ctx)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			dataBase, mock, err := sqlmock.Newx()
+			require.NoError(t, err)
+			defer dataBase.Close()
+
+			migrator, err := postgres.NewMigrator(dataBase, testMigrations())
+			require.NoError(t, err)
+
+			mock.ExpectExec(`SELECT pg_advisory_lock`).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_migrations`).WillReturnResult(sqlmock.NewResult(0, 0))
+			testCase.mockBehavior(mock)
+			mock.ExpectExec(`SELECT pg_advisory_unlock`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+			err = testCase.run(context.Background(), migrator)
+			if testCase.wantErr {
+				require.ErrorIs(t, err, migrate.ErrDirty)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMigrator_Status(t *testing.T) {
+	dataBase, mock, err := sqlmock.Newx()
+	require.NoError(t, err)
+	defer dataBase.Close()
+
+	migrator, err := postgres.NewMigrator(dataBase, testMigrations())
+	require.NoError(t, err)
+
+	expectVersion(mock, 1, false)
+
+	status, err := migrator.Status(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint(1), status.Version)
+	require.Equal(t, uint(2), status.Latest)
+	require.Len(t, status.Pending, 1)
+	require.Equal(t, "second", status.Pending[0].Name)
+}
+
+func TestMigrator_Embedded(t *testing.T) {
+	dataBase, _, err := sqlmock.Newx()
+	require.NoError(t, err)
+	defer dataBase.Close()
+
+	migrator, err := postgres.NewMigrator(dataBase, schema.Migrations)
+	require.NoError(t, err)
+	require.Equal(t, uint(10), migrator.Latest())
+}
@@ -1,9 +1,15 @@
 package postgres
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"main.go/internal/repository/sqltrace"
 )
 
 const (
@@ -14,15 +20,35 @@ const (
 	ListsItemsTable     = "lists_items"
 )
 
+// Backoff between connection attempts at startup.
+const (
+	firstRetry = 250 * time.Millisecond
+	maxRetry   = 5 * time.Second
+)
+
+// Config.MaxOpenConns and MaxIdleConns size the pool, zero meaning no limit
+// and no idle connections. ConnMaxLifetime and ConnMaxIdleTime recycle
+// connections, zero meaning never. ConnectTimeout is how long startup waits
+// for Postgres to come up, and StatsInterval how often the pool is logged.
+// Replica optionally takes reads off the primary.
 type Config struct {
-	Host     string
-	Port     string
-	Username string
-	Password string
-	DBName   string
-	SSLMode  string
+	Host            string
+	Port            string
+	Username        string
+	Password        string
+	DBName          string
+	SSLMode         string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	ConnectTimeout  time.Duration
+	StatsInterval   time.Duration
+	Replica         ReplicaConfig
 }
 
+// NewPostgresDB opens the pool and waits for Postgres to answer, so the app
+// can start alongside its database.
 func NewPostgresDB(cfg Config) (*sqlx.DB, error) {
 	dataBase, err := sqlx.Open(
 		"postgres",
@@ -33,9 +59,62 @@ func NewPostgresDB(cfg Config) (*sqlx.DB, error) {
 		return nil, err
 	}
 
-	if err = dataBase.Ping(); err != nil {
+	dataBase.SetMaxOpenConns(cfg.MaxOpenConns)
+	dataBase.SetMaxIdleConns(cfg.MaxIdleConns)
+	dataBase.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	dataBase.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	if err = waitForDB(dataBase, cfg.ConnectTimeout); err != nil {
+		_ = dataBase.Close()
+
 		return nil, err
 	}
 
 	return dataBase, nil
 }
+
+// waitForDB pings until Postgres answers or timeout passes, doubling the
+// wait between attempts up to maxRetry.
+func waitForDB(db *sqlx.DB, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	retry := firstRetry
+
+	for {
+		err := db.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+
+		logrus.Warnf("database not ready, retrying in %s: %s", retry, err.Error())
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("database not ready after %s: %w", timeout, err)
+		case <-time.After(retry):
+		}
+
+		retry = min(2*retry, maxRetry)
+	}
+}
+
+// newTracedDB is what the repositories run their statements through, each
+// bounded by timeouts.
+func newTracedDB(db *sqlx.DB, timeouts sqltrace.Timeouts) *sqltrace.DB {
+	return sqltrace.Wrap(db, semconv.DBSystemPostgreSQL, timeouts)
+}
+
+// checkUpdated turns an update that matched no rows into sql.ErrNoRows.
+func checkUpdated(result sql.Result) error {
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package postgres_test
+
+import (
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"main.go/internal/repository/postgres"
+)
+
+func TestNewPostgresDB_GivesUp(t *testing.T) {
+	// A port nothing listens on.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	port := listener.Addr().(*net.TCPAddr).Port //nolint:forcetypeassert // tcp listener
+	require.NoError(t, listener.Close())
+
+	start := time.Now()
+
+	_, err = postgres.NewPostgresDB(postgres.Config{
+		Host:            "127.0.0.1",
+		Port:            strconv.Itoa(port),
+		Username:        "postgres",
+		Password:        "",
+		DBName:          "postgres",
+		SSLMode:         "disable",
+		MaxOpenConns:    1,
+		MaxIdleConns:    1,
+		ConnMaxLifetime: 0,
+		ConnMaxIdleTime: 0,
+		ConnectTimeout:  600 * time.Millisecond,
+		StatsInterval:   time.Minute,
+	})
+	require.ErrorContains(t, err, "database not ready after 600ms")
+	require.GreaterOrEqual(t, time.Since(start), 600*time.Millisecond, "retried until the deadline")
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"main.go/internal/entity"
+	"main.go/internal/repository/sqltrace"
+)
+
+const RateLimitBucketsTable = "rate_limit_buckets"
+
+type RateLimit interface {
+	Take(ctx context.Context, key string, limit entity.RateLimit, now time.Time) (entity.RateDecision, error)
+	DeleteStale(ctx context.Context, before time.Time) error
+}
+
+type RateLimitPostgres struct {
+	db *sqltrace.DB
+}
+
+func NewRateLimitPostgres(db *sqlx.DB, timeouts sqltrace.Timeouts) *RateLimitPostgres {
+	return &RateLimitPostgres{db: newTracedDB(db, timeouts)}
+}
+
+// Take refills the key's bucket for the time since its last take and takes
+// one token if there is one, in a single statement so that replicas sharing
+// the table never hand out the same token twice.
+func (r *RateLimitPostgres) Take(
+	ctx context.Context,
+	key string,
+	limit entity.RateLimit,
+	now time.Time,
+) (entity.RateDecision, error) {
+	var bucket struct {
+		Tokens  float64 `db:"tokens"`
+		Allowed bool    `db:"allowed"`
+	}
+
+	refilled := "LEAST($2::double precision, b.tokens + " +
+		"GREATEST(EXTRACT(EPOCH FROM $3::timestamptz - b.updated_at), 0) * $4::double precision)"
+
+	query := fmt.Sprintf(
+		`
+			INSERT INTO %[1]s AS b (key, tokens, allowed, updated_at)
+			    VALUES ($1, $2::double precision - 1, TRUE, $3::timestamptz)
+			ON CONFLICT (key)
+			    DO UPDATE SET
+			        tokens = CASE WHEN %[2]s >= 1 THEN %[2]s - 1 ELSE %[2]s END,
+			        allowed = %[2]s >= 1,
+			        updated_at = $3::timestamptz
+			RETURNING
+			    tokens,
+			    allowed`,
+		RateLimitBucketsTable,
+		refilled,
+	)
+
+	err := r.db.GetContext(ctx, &bucket, query, key, float64(limit.Burst), now, limit.RefillRate())
+	if err != nil {
+		return entity.RateDecision{}, err
+	}
+
+	return entity.NewRateDecision(bucket.Allowed, bucket.Tokens, limit), nil
+}
+
+// DeleteStale drops buckets untouched since before. Callers pick a time by
+// which every bucket would have refilled, so dropping them changes nothing.
+func (r *RateLimitPostgres) DeleteStale(ctx context.Context, before time.Time) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE updated_at < $1`, RateLimitBucketsTable)
+	_, err := r.db.ExecContext(ctx, query, before)
+
+	return err
+}
@@ -0,0 +1,75 @@
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"main.go/internal/entity"
+	"main.go/internal/repository"
+	"main.go/internal/repository/postgres"
+)
+
+func TestRateLimitPostgres_Take(t *testing.T) {
+	dataBase, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer dataBase.Close()
+
+	rep := repository.NewRepository(dataBase, repository.QueryTimeouts{})
+	query := fmt.Sprintf(`INSERT INTO %s AS b (.+) ON CONFLICT (.+) RETURNING`, postgres.RateLimitBucketsTable) //nolint:perfsprint // general style for queries
+
+	timeNow := time.Now()
+	limit := entity.RateLimit{Requests: 1, Per: time.Second, Burst: 5}
+
+	testTable := []struct {
+		name         string
+		mockBehavior func()
+		want         entity.RateDecision
+		wantErr      bool
+	}{
+		{
+			name: "OK",
+			mockBehavior: func() {
+				rows := sqlmock.NewRows([]string{"tokens", "allowed"}).AddRow(4.0, true)
+
+				mock.ExpectQuery(query).
+					WithArgs("auth:ip:192.0.2.1", 5.0, timeNow, 1.0).
+					WillReturnRows(rows)
+			},
+			want:    entity.RateDecision{Allowed: true, Remaining: 4, RetryAfter: 0},
+			wantErr: false,
+		},
+		{
+			name: "Empty Bucket",
+			mockBehavior: func() {
+				rows := sqlmock.NewRows([]string{"tokens", "allowed"}).AddRow(0.25, false)
+
+				mock.ExpectQuery(query).
+					WithArgs("auth:ip:192.0.2.1", 5.0, timeNow, 1.0).
+					WillReturnRows(rows)
+			},
+			want:    entity.RateDecision{Allowed: false, Remaining: 0, RetryAfter: 750 * time.Millisecond},
+			wantErr: false,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior()
+
+			got, err1 := rep.RateLimit.Take(context.Background(), "auth:ip:192.0.2.1", limit, timeNow)
+			if testCase.wantErr {
+				require.Error(t, err1)
+			} else {
+				require.NoError(t, err1)
+				require.Equal(t, testCase.want, got)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"main.go/internal/repository/sqltrace"
+)
+
+// ReplicaConfig.DSN names a streaming replica to send reads to, none when
+// empty. Reads go back to the primary while the replica is more than MaxLag
+// behind, which is checked every CheckInterval.
+type ReplicaConfig struct {
+	DSN           string
+	MaxLag        time.Duration
+	CheckInterval time.Duration
+}
+
+// NewReplicaDB opens a pool on the replica, sized like the primary's. It
+// doesn't wait for the replica: until it answers, reads stay on the primary.
+func NewReplicaDB(cfg Config) (*sqlx.DB, error) {
+	dataBase, err := sqlx.Open("postgres", cfg.Replica.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	dataBase.SetMaxOpenConns(cfg.MaxOpenConns)
+	dataBase.SetMaxIdleConns(cfg.MaxIdleConns)
+	dataBase.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	dataBase.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	return dataBase, nil
+}
+
+// ErrReplicaNotStreaming is returned by Lag while the replica isn't
+// receiving WAL from the primary. Its replay position stands still then, so
+// it would otherwise look caught up however far the primary moved on.
+var ErrReplicaNotStreaming = errors.New("replica is not streaming from the primary")
+
+type ReplicaPostgres struct {
+	db      *sqltrace.DB
+	primary *sqltrace.DB
+}
+
+// NewReplicaPostgres measures the lag of the replica db against primary.
+func NewReplicaPostgres(db, primary *sqlx.DB, timeout time.Duration) *ReplicaPostgres {
+	timeouts := sqltrace.Timeouts{Read: timeout, Write: timeout}
+
+	return &ReplicaPostgres{db: newTracedDB(db, timeouts), primary: newTracedDB(primary, timeouts)}
+}
+
+type replicaState struct {
+	Recovery bool    `db:"recovery"`
+	Status   string  `db:"status"`
+	Replayed bool    `db:"replayed"`
+	Behind   float64 `db:"behind"`
+}
+
+// Lag is how long ago the last transaction the replica replayed committed,
+// or zero once it replayed everything the primary had written when Lag was
+// called, however long the primary has been idle. A server that isn't a
+// replica is current; a replica whose WAL receiver isn't streaming fails
+// with ErrReplicaNotStreaming.
+func (r *ReplicaPostgres) Lag(ctx context.Context) (time.Duration, error) {
+	var primaryLSN string
+
+	if err := r.primary.GetContext(ctx, &primaryLSN, `SELECT pg_current_wal_lsn()::text`); err != nil {
+		return 0, fmt.Errorf("primary position: %w", err)
+	}
+
+	var state replicaState
+
+	query := `
+			SELECT
+			    pg_is_in_recovery() AS recovery,
+			    coalesce((SELECT status FROM pg_stat_wal_receiver), '') AS status,
+			    coalesce(pg_last_wal_replay_lsn() >= $1::pg_lsn, false) AS replayed,
+			    coalesce(extract(epoch FROM now() - pg_last_xact_replay_timestamp()), 0) AS behind`
+
+	if err := r.db.GetContext(ctx, &state, query, primaryLSN); err != nil {
+		return 0, err
+	}
+
+	switch {
+	case !state.Recovery:
+		return 0, nil
+	case state.Status != "streaming":
+		status := state.Status
+		if status == "" {
+			status = "not running"
+		}
+
+		return 0, fmt.Errorf("%w: WAL receiver %s", ErrReplicaNotStreaming, status)
+	case state.Replayed:
+		return 0, nil
+	default:
+		return time.Duration(state.Behind * float64(time.Second)), nil
+	}
+}
@@ -0,0 +1,84 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"main.go/internal/repository/postgres"
+)
+
+func TestReplicaPostgres_Lag(t *testing.T) {
+	columns := []string{"recovery", "status", "replayed", "behind"}
+
+	testTable := []struct {
+		name    string
+		state   []driver.Value
+		want    time.Duration
+		wantErr error
+	}{
+		{
+			name:    "Caught Up",
+			state:   []driver.Value{true, "streaming", true, 3600.0},
+			want:    0,
+			wantErr: nil,
+		},
+		{
+			name:    "Behind",
+			state:   []driver.Value{true, "streaming", false, 1.5},
+			want:    1500 * time.Millisecond,
+			wantErr: nil,
+		},
+		{
+			name:    "Not Streaming",
+			state:   []driver.Value{true, "", true, 0.0},
+			want:    0,
+			wantErr: postgres.ErrReplicaNotStreaming,
+		},
+		{
+			name:    "Reconnecting",
+			state:   []driver.Value{true, "waiting", true, 0.0},
+			want:    0,
+			wantErr: postgres.ErrReplicaNotStreaming,
+		},
+		{
+			name:    "Not A Replica",
+			state:   []driver.Value{false, "", false, 0.0},
+			want:    0,
+			wantErr: nil,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init deps
+			replicaDB, replicaMock, err := sqlmock.Newx()
+			require.NoError(t, err)
+
+			defer replicaDB.Close()
+
+			primaryDB, primaryMock, err := sqlmock.Newx()
+			require.NoError(t, err)
+
+			defer primaryDB.Close()
+
+			primaryMock.ExpectQuery(`SELECT pg_current_wal_lsn`).
+				WillReturnRows(sqlmock.NewRows([]string{"lsn"}).AddRow("0/3000060"))
+			replicaMock.ExpectQuery(`SELECT (.+) FROM pg_stat_wal_receiver`).
+				WithArgs("0/3000060").
+				WillReturnRows(sqlmock.NewRows(columns).AddRow(testCase.state...))
+
+			// Call
+			lag, err := postgres.NewReplicaPostgres(replicaDB, primaryDB, time.Second).Lag(context.Background())
+
+			// Assert
+			require.ErrorIs(t, err, testCase.wantErr)
+			require.Equal(t, testCase.want, lag)
+			require.NoError(t, primaryMock.ExpectationsWereMet())
+			require.NoError(t, replicaMock.ExpectationsWereMet())
+		})
+	}
+}
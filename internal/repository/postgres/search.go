@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"main.go/internal/entity"
+	"main.go/internal/repository/sqltrace"
+)
+
+// ts_headline marks the matches without escaping the text around them, so it
+// marks them with control characters and highlight turns those into <mark>
+// tags once the text is escaped.
+const (
+	markStart       = "\x02"
+	markStop        = "\x03"
+	headlineOptions = "StartSel=" + markStart + ", StopSel=" + markStop + ", MaxWords=20, MinWords=5, MaxFragments=2"
+)
+
+//nolint:gochecknoglobals // replacer of the marks ts_headline sets
+var highlighter = strings.NewReplacer(markStart, "<mark>", markStop, "</mark>")
+
+type Search interface {
+	Search(ctx context.Context, userID int, tsQuery string, limit int) ([]entity.SearchResult, error)
+}
+
+type SearchPostgres struct {
+	db *sqltrace.DB
+}
+
+func NewSearchPostgres(db *sqlx.DB, timeouts sqltrace.Timeouts) *SearchPostgres {
+	return &SearchPostgres{db: newTracedDB(db, timeouts)}
+}
+
+// Search matches tsQuery against the items and lists the user belongs to and
+// the client data of booking requests made with the user, and returns the
+// best ranked hits first.
+func (r *SearchPostgres) Search(
+	ctx context.Context,
+	userID int,
+	tsQuery string,
+	limit int,
+) ([]entity.SearchResult, error) {
+	var results []entity.SearchResult
+
+	query := fmt.Sprintf(
+		`
+			SELECT
+			    kind,
+			    id,
+			    title,
+			    snippet,
+			    rank
+			FROM (
+			    SELECT
+			        '%s' AS kind,
+			        ti.id,
+			        ti.title,
+			        ts_headline('simple', ti.title || ' ' || coalesce(ti.description, ''), q, $3) AS snippet,
+			        ts_rank(ti.search, q) AS rank
+			    FROM
+			        %s ti
+			        INNER JOIN %s li ON li.item_id = ti.id
+			        INNER JOIN %s ul ON ul.list_id = li.list_id,
+			        to_tsquery('simple', $1) q
+			    WHERE
+			        ul.user_id = $2
+			        AND ti.search @@ q
+			    UNION ALL
+			    SELECT
+			        '%s' AS kind,
+			        tl.id,
+			        tl.title,
+			        ts_headline('simple', tl.title || ' ' || coalesce(tl.description, ''), q, $3) AS snippet,
+			        ts_rank(tl.search, q) AS rank
+			    FROM
+			        %s tl
+			        INNER JOIN %s ul ON ul.list_id = tl.id,
+			        to_tsquery('simple', $1) q
+			    WHERE
+			        ul.user_id = $2
+			        AND tl.search @@ q
+			    UNION ALL
+			    SELECT
+			        '%s' AS kind,
+			        br.id,
+			        br.client_name AS title,
+			        ts_headline('simple', br.client_name || ' ' || br.client_email || ' ' || br.client_phone
+			            || ' ' || br.reference, q, $3) AS snippet,
+			        ts_rank(br.search, q) AS rank
+			    FROM
+			        %s br,
+			        to_tsquery('simple', $1) q
+			    WHERE
+			        br.artist_id = $2
+			        AND br.search @@ q
+			) results
+			ORDER BY
+			    rank DESC,
+			    kind,
+			    id
+			LIMIT $4`,
+		entity.SearchKindItem,
+		TimeslotsItemsTable,
+		ListsItemsTable,
+		UsersListsTable,
+		entity.SearchKindList,
+		TimeslotListsTable,
+		UsersListsTable,
+		entity.SearchKindBooking,
+		BookingRequestsTable,
+	)
+
+	if err := r.db.SelectContext(ctx, &results, query, tsQuery, userID, headlineOptions, limit); err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		results[i].Snippet = highlight(results[i].Snippet)
+	}
+
+	return results, nil
+}
+
+// highlight escapes a ts_headline snippet for HTML and marks its matches.
+// The client data of bookings comes from anyone, so the snippet must not
+// carry their markup.
+func highlight(snippet string) string {
+	return highlighter.Replace(html.EscapeString(snippet))
+}
@@ -0,0 +1,107 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"main.go/internal/entity"
+	"main.go/internal/repository"
+)
+
+func TestSearchPostgres_Search(t *testing.T) {
+	dataBase, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer dataBase.Close()
+
+	rep := repository.NewRepository(dataBase, repository.QueryTimeouts{})
+	query := `SELECT kind, id, title, snippet, rank FROM \((.+)UNION ALL(.+)\) results ORDER BY rank DESC`
+
+	type input struct {
+		userID  int
+		tsQuery string
+		limit   int
+	}
+
+	testTable := []struct {
+		name         string
+		mockBehavior func(input input)
+		input        input
+		want         []entity.SearchResult
+		wantErr      bool
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(input input) {
+				rows := sqlmock.NewRows([]string{"kind", "id", "title", "snippet", "rank"}).
+					AddRow(entity.SearchKindItem, 3, "Sleeve session", "\x02Sleeve\x03 session", 0.6).
+					AddRow(entity.SearchKindBooking, 1, "<b>Maria</b>", "\x02<b>Maria</b>\x03 & co", 0.3)
+
+				mock.ExpectQuery(query).
+					WithArgs(input.tsQuery, input.userID, sqlmock.AnyArg(), input.limit).
+					WillReturnRows(rows)
+			},
+			input: input{
+				userID:  1,
+				tsQuery: "sle:* & mar:*",
+				limit:   10,
+			},
+			want: []entity.SearchResult{
+				{
+					Kind:    entity.SearchKindItem,
+					ID:      3,
+					Title:   "Sleeve session",
+					Snippet: "<mark>Sleeve</mark> session",
+					Rank:    0.6,
+				},
+				{
+					Kind:    entity.SearchKindBooking,
+					ID:      1,
+					Title:   "<b>Maria</b>",
+					Snippet: "<mark>&lt;b&gt;Maria&lt;/b&gt;</mark> &amp; co",
+					Rank:    0.3,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "DB Error",
+			mockBehavior: func(input input) {
+				mock.ExpectQuery(query).
+					WithArgs(input.tsQuery, input.userID, sqlmock.AnyArg(), input.limit).
+					WillReturnError(errors.New("some error"))
+			},
+			input: input{
+				userID:  1,
+				tsQuery: "sle:*",
+				limit:   10,
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior(testCase.input)
+
+			got, err1 := rep.Search.Search(
+				context.Background(),
+				testCase.input.userID,
+				testCase.input.tsQuery,
+				testCase.input.limit,
+			)
+			if testCase.wantErr {
+				require.Error(t, err1)
+			} else {
+				require.NoError(t, err1)
+				require.Equal(t, testCase.want, got)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
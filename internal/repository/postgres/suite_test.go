@@ -0,0 +1,40 @@
+package postgres_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+	"main.go/internal/repository"
+	"main.go/internal/repository/postgres"
+	"main.go/internal/repository/repotest"
+	"main.go/schema"
+)
+
+// TestRepository runs the shared suite against a real database, which it
+// wipes, so it only runs when TEST_POSTGRES_DSN names one.
+func TestRepository(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	db, err := sqlx.Connect("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	migrator, err := postgres.NewMigrator(db, schema.Migrations)
+	require.NoError(t, err)
+
+	repotest.RunAll(t, func(t *testing.T) *repository.Repository {
+		t.Helper()
+
+		ctx := context.Background()
+		require.NoError(t, migrator.To(ctx, 0))
+		require.NoError(t, migrator.Up(ctx))
+
+		return repository.NewRepository(db, repository.QueryTimeouts{})
+	})
+}
@@ -1,34 +1,53 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"main.go/internal/entity"
+	"main.go/internal/repository/sqltrace"
 )
 
 type TimeslotItem interface {
-	Create(listID int, item entity.TimeslotItem) (int, error)
-	GetAll(userID, listID int) ([]entity.TimeslotItem, error)
-	GetByID(userID, itemID int) (entity.TimeslotItem, error)
-	Delete(userID, itemID int) error
-	Update(userID, itemID int, input entity.UpdateItemInput) error
+	Create(ctx context.Context, listID int, item entity.TimeslotItem) (int, error)
+	GetAll(ctx context.Context, userID, listID int, filter entity.ItemsFilter) ([]entity.TimeslotItem, error)
+	GetByID(ctx context.Context, userID, itemID int) (entity.TimeslotItem, error)
+	Delete(ctx context.Context, userID, itemID int) error
+	Update(ctx context.Context, userID, itemID int, input entity.UpdateItemInput, version int) error
+	GetByRange(ctx context.Context, input entity.ItemsByRange) ([]entity.TimeslotItem, error)
+	CountUpcoming(ctx context.Context, from, to time.Time) (int, error)
 }
 
 type TimeslotItemPostgres struct {
-	db *sqlx.DB
+	db *sqltrace.DB
+	// getAllDB and getByRangeDB run the item searches, which scan more rows
+	// than the other reads and get timeouts of their own.
+	getAllDB     *sqltrace.DB
+	getByRangeDB *sqltrace.DB
 }
 
-func NewTimeslotItemPostgres(db *sqlx.DB) *TimeslotItemPostgres {
-	return &TimeslotItemPostgres{db: db}
+func NewTimeslotItemPostgres(
+	db *sqlx.DB,
+	timeouts sqltrace.Timeouts,
+	getAllTimeout, getByRangeTimeout time.Duration,
+) *TimeslotItemPostgres {
+	traced := newTracedDB(db, timeouts)
+
+	return &TimeslotItemPostgres{
+		db:           traced,
+		getAllDB:     traced.WithReadTimeout(getAllTimeout),
+		getByRangeDB: traced.WithReadTimeout(getByRangeTimeout),
+	}
 }
 
-func (r *TimeslotItemPostgres) Create(listID int, item entity.TimeslotItem) (int, error) {
-	transaction, err := r.db.Begin()
+func (r *TimeslotItemPostgres) Create(ctx context.Context, listID int, item entity.TimeslotItem) (int, error) {
+	transaction, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return 0, err
+		return 0, translateError(err, "item")
 	}
 
 	var itemID int
@@ -40,14 +59,14 @@ func (r *TimeslotItemPostgres) Create(listID int, item entity.TimeslotItem) (int
 			    id`,
 		TimeslotsItemsTable,
 	)
-	row := transaction.QueryRow(createItemQuery, item.Title, item.Description, item.Start, item.End)
+	row := transaction.QueryRowContext(ctx, createItemQuery, item.Title, item.Description, item.Start, item.End)
 
 	if err = row.Scan(&itemID); err != nil {
 		if err1 := transaction.Rollback(); err1 != nil {
 			return 0, err1
 		}
 
-		return 0, err
+		return 0, translateError(err, "item")
 	}
 
 	createListsItemsQuery := fmt.Sprintf(
@@ -57,22 +76,25 @@ func (r *TimeslotItemPostgres) Create(listID int, item entity.TimeslotItem) (int
 		ListsItemsTable,
 	)
 
-	if _, err = transaction.Exec(createListsItemsQuery, listID, itemID); err != nil {
+	if _, err = transaction.ExecContext(ctx, createListsItemsQuery, listID, itemID); err != nil {
 		if err1 := transaction.Rollback(); err1 != nil {
 			return 0, err
 		}
 
-		return 0, err
+		return 0, translateError(err, "item")
 	}
 
 	return itemID, transaction.Commit()
 }
 
 func (r *TimeslotItemPostgres) GetAll(
+	ctx context.Context,
 	userID, listID int,
+	filter entity.ItemsFilter,
 ) ([]entity.TimeslotItem, error) {
 	var items []entity.TimeslotItem
 
+	conditions, filterArgs, argID := itemsFilterConditions(filter, 3)
 	query := fmt.Sprintf(
 		`
 			SELECT
@@ -82,6 +104,8 @@ func (r *TimeslotItemPostgres) GetAll(
 			    ti.beginning,
 			    ti.finish,
 			    ti.done,
+			    ti.cancelled,
+			    ti.version,
 				u.username
 			FROM
 			    %s ti
@@ -90,14 +114,24 @@ func (r *TimeslotItemPostgres) GetAll(
 				INNER JOIN %s u ON u.id = ul.user_id
 			WHERE
 			    li.list_id = $1
-			    AND ul.user_id = $2`,
+			    AND ul.user_id = $2
+			    %s
+			ORDER BY
+			    ti.beginning,
+			    ti.id
+			LIMIT $%d`,
 		TimeslotsItemsTable,
 		ListsItemsTable,
 		UsersListsTable,
 		UsersTable,
+		andConditions(conditions),
+		argID,
 	)
 
-	if err := r.db.Select(&items, query, listID, userID); err != nil {
+	args := append([]interface{}{listID, userID}, filterArgs...)
+	args = append(args, filter.Limit)
+
+	if err := r.getAllDB.SelectContext(ctx, &items, query, args...); err != nil {
 		return nil, err
 	}
 
@@ -105,6 +139,7 @@ func (r *TimeslotItemPostgres) GetAll(
 }
 
 func (r *TimeslotItemPostgres) GetByID(
+	ctx context.Context,
 	userID, itemID int,
 ) (entity.TimeslotItem, error) {
 	var item entity.TimeslotItem
@@ -118,8 +153,11 @@ func (r *TimeslotItemPostgres) GetByID(
 			    ti.beginning,
 			    ti.finish,
 			    ti.done,
+			    ti.cancelled,
+			    ti.version,
 				u.username,
-				u.color
+				u.color,
+			    li.list_id
 			FROM
 			    %s ti
 			    INNER JOIN %s li ON li.item_id = ti.id
@@ -133,14 +171,22 @@ func (r *TimeslotItemPostgres) GetByID(
 		UsersListsTable,
 		UsersTable,
 	)
-	if err := r.db.Get(&item, query, itemID, userID); err != nil {
-		return item, err
+	if err := r.db.GetContext(ctx, &item, query, itemID, userID); err != nil {
+		return item, translateError(err, "item")
 	}
 
 	return item, nil
 }
 
-func (r *TimeslotItemPostgres) Update(userID, itemID int, input entity.UpdateItemInput) error {
+// Update changes the item when its version still equals version, a zero
+// version skips the check. It returns a not found error wrapping sql.ErrNoRows
+// when no item was changed.
+func (r *TimeslotItemPostgres) Update(
+	ctx context.Context,
+	userID, itemID int,
+	input entity.UpdateItemInput,
+	version int,
+) error {
 	setValues := make([]string, 0)
 	args := make([]interface{}, 0)
 	argID := 1
@@ -174,23 +220,30 @@ func (r *TimeslotItemPostgres) Update(userID, itemID int, input entity.UpdateIte
 			    ti.id = li.item_id
 			    AND li.list_id = ul.list_id
 			    AND ul.user_id = $%d
-			    AND ti.id = $%d`,
+			    AND ti.id = $%d
+			    AND ($%d = 0
+			        OR ti.version = $%d)`,
 		TimeslotsItemsTable,
 		setQuery,
 		ListsItemsTable,
 		UsersListsTable,
 		argID,
 		argID+1,
+		argID+2,
+		argID+2,
 	)
 
-	args = append(args, userID, itemID)
+	args = append(args, userID, itemID, version)
 
-	_, err := r.db.Exec(query, args...)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return translateError(err, "item")
+	}
 
-	return err
+	return translateError(checkUpdated(result), "item")
 }
 
-func (r *TimeslotItemPostgres) Delete(userID, itemID int) error {
+func (r *TimeslotItemPostgres) Delete(ctx context.Context, userID, itemID int) error {
 	query := fmt.Sprintf(
 		`
 			DELETE FROM %s ti USING %s li, %s ul
@@ -203,16 +256,18 @@ func (r *TimeslotItemPostgres) Delete(userID, itemID int) error {
 		ListsItemsTable,
 		UsersListsTable,
 	)
-	_, err := r.db.Exec(query, userID, itemID)
+	_, err := r.db.ExecContext(ctx, query, userID, itemID)
 
 	return err
 }
 
 func (r *TimeslotItemPostgres) GetByRange(
+	ctx context.Context,
 	input entity.ItemsByRange,
 ) ([]entity.TimeslotItem, error) {
 	var items []entity.TimeslotItem
 
+	conditions, filterArgs, argID := itemsFilterConditions(input.ItemsFilter, 3)
 	query := fmt.Sprintf(
 		`
 			SELECT
@@ -222,6 +277,8 @@ func (r *TimeslotItemPostgres) GetByRange(
 			    ti.beginning,
 			    ti.finish,
 			    ti.done,
+			    ti.cancelled,
+			    ti.version,
 			    u.username,
 				u.color
 			FROM
@@ -231,20 +288,49 @@ func (r *TimeslotItemPostgres) GetByRange(
 				INNER JOIN %s u ON u.id = ul.user_id
 			WHERE
 			    ti.beginning >= $1
-			    AND ti.finish <= $2`,
+			    AND ti.finish <= $2
+			    %s
+			ORDER BY
+			    ti.beginning,
+			    ti.id
+			LIMIT $%d`,
 		TimeslotsItemsTable,
 		ListsItemsTable,
 		UsersListsTable,
 		UsersTable,
+		andConditions(conditions),
+		argID,
 	)
 
-	if err := r.db.Select(&items,
-		query,
-		input.Start,
-		input.End,
-	); err != nil {
+	args := append([]interface{}{input.Start, input.End}, filterArgs...)
+	args = append(args, input.Limit)
+
+	if err := r.getByRangeDB.SelectContext(ctx, &items, query, args...); err != nil {
 		return nil, err
 	}
 
 	return items, nil
 }
+
+// CountUpcoming counts the items that are neither done nor cancelled and
+// start within [from, to).
+func (r *TimeslotItemPostgres) CountUpcoming(ctx context.Context, from, to time.Time) (int, error) {
+	var count int
+
+	query := fmt.Sprintf(
+		`
+			SELECT
+			    count(*)
+			FROM
+			    %s
+			WHERE
+			    beginning >= $1
+			    AND beginning < $2
+			    AND NOT done
+			    AND NOT cancelled`,
+		TimeslotsItemsTable,
+	)
+	err := r.db.GetContext(ctx, &count, query, from, to)
+
+	return count, err
+}
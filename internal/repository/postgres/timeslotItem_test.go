@@ -1,6 +1,7 @@
 package postgres_test
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -21,7 +22,7 @@ func TestTimeslotItemPostgres_Create(t *testing.T) {
 	}
 	defer dataBase.Close()
 
-	rep := repository.NewRepository(dataBase)
+	rep := repository.NewRepository(dataBase, repository.QueryTimeouts{})
 	query1 := `
 		INSERT INTO timeslots_items`
 	query2 := `
@@ -129,7 +130,7 @@ func TestTimeslotItemPostgres_Create(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mockBehavior(testCase.input, testCase.want)
 
-			got, err1 := rep.TimeslotItem.Create(testCase.input.listID, testCase.input.item)
+			got, err1 := rep.TimeslotItem.Create(context.Background(), testCase.input.listID, testCase.input.item)
 			if testCase.wantErr {
 				require.Error(t, err1)
 			} else {
@@ -148,7 +149,7 @@ func TestTimeslotItemPostgres_GetAll(t *testing.T) {
 	}
 	defer dataBase.Close()
 
-	rep := repository.NewRepository(dataBase)
+	rep := repository.NewRepository(dataBase, repository.QueryTimeouts{})
 	query := fmt.Sprintf(
 		`
 			SELECT
@@ -165,6 +166,7 @@ func TestTimeslotItemPostgres_GetAll(t *testing.T) {
 	type input struct {
 		listID int
 		userID int
+		filter entity.ItemsFilter
 	}
 
 	timeNow := time.Now()
@@ -185,11 +187,17 @@ func TestTimeslotItemPostgres_GetAll(t *testing.T) {
 					AddRow(3, "title3", "description3", timeNow, timeNow, false)
 
 				mock.ExpectQuery(query).
-					WithArgs(1, 1).
+					WithArgs(1, 1, 10).
 					WillReturnRows(rows)
 			},
 			input: input{
 				listID: 1, userID: 1,
+				filter: entity.ItemsFilter{
+					PageParams: entity.PageParams{Cursor: "", Limit: 10, After: entity.Cursor{}},
+					Title:      "",
+					Status:     "",
+					Artist:     "",
+				},
 			},
 
 			want: []entity.TimeslotItem{
@@ -227,23 +235,71 @@ func TestTimeslotItemPostgres_GetAll(t *testing.T) {
 					[]string{"id", "title", "description", "beginning", "finish", "done"},
 				)
 				mock.ExpectQuery(query).
-					WithArgs(1, 1).
+					WithArgs(1, 1, 10).
 					WillReturnRows(rows)
 			},
 			input: input{
 				listID: 1,
 				userID: 1,
+				filter: entity.ItemsFilter{
+					PageParams: entity.PageParams{Cursor: "", Limit: 10, After: entity.Cursor{}},
+					Title:      "",
+					Status:     "",
+					Artist:     "",
+				},
 			},
 			want:    nil,
 			wantErr: false,
 		},
+		{
+			name: "Filtered Page",
+			mockBehavior: func() {
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "beginning", "finish", "done"}).
+					AddRow(5, "title5", "description5", timeNow, timeNow, true)
+
+				mock.ExpectQuery(query+`ti.title ILIKE (.+) AND ti.done AND u.username = (.+) `+
+					`AND \(ti.beginning, ti.id\) > (.+) ORDER BY ti.beginning, ti.id LIMIT (.+)`).
+					WithArgs(1, 1, `50\%`, "artist", timeNow, 4, 10).
+					WillReturnRows(rows)
+			},
+			input: input{
+				listID: 1,
+				userID: 1,
+				filter: entity.ItemsFilter{
+					PageParams: entity.PageParams{
+						Cursor: "",
+						Limit:  10,
+						After:  entity.Cursor{Start: timeNow, ID: 4},
+					},
+					Title:  "50%",
+					Status: entity.ItemStatusDone,
+					Artist: "artist",
+				},
+			},
+			want: []entity.TimeslotItem{
+				{
+					ID:          5,
+					Title:       "title5",
+					Description: "description5",
+					Start:       timeNow,
+					End:         timeNow,
+					Done:        true,
+				},
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, testCase := range testTable {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mockBehavior()
 
-			got, err1 := rep.TimeslotItem.GetAll(testCase.input.userID, testCase.input.listID)
+			got, err1 := rep.TimeslotItem.GetAll(
+				context.Background(),
+				testCase.input.userID,
+				testCase.input.listID,
+				testCase.input.filter,
+			)
 			if testCase.wantErr {
 				require.Error(t, err1)
 			} else {
@@ -262,7 +318,7 @@ func TestTimeslotItemPostgres_GetById(t *testing.T) {
 	}
 	defer dataBase.Close()
 
-	rep := repository.NewRepository(dataBase)
+	rep := repository.NewRepository(dataBase, repository.QueryTimeouts{})
 	query := fmt.Sprintf(
 		`
 			SELECT
@@ -341,7 +397,7 @@ func TestTimeslotItemPostgres_GetById(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mockBehavior()
 
-			got, err1 := rep.TimeslotItem.GetByID(testCase.input.userID, testCase.input.itemID)
+			got, err1 := rep.TimeslotItem.GetByID(context.Background(), testCase.input.userID, testCase.input.itemID)
 			if testCase.wantErr {
 				require.Error(t, err1)
 			} else {
@@ -360,7 +416,7 @@ func TestTimeslotItemPostgres_Update(t *testing.T) {
 	}
 	defer dataBase.Close()
 
-	rep := repository.NewRepository(dataBase)
+	rep := repository.NewRepository(dataBase, repository.QueryTimeouts{})
 	query := fmt.Sprintf(
 		`
 			UPDATE
@@ -377,9 +433,10 @@ func TestTimeslotItemPostgres_Update(t *testing.T) {
 	)
 
 	type input struct {
-		itemID int
-		userID int
-		update entity.UpdateItemInput
+		itemID  int
+		userID  int
+		version int
+		update  entity.UpdateItemInput
 	}
 
 	timeNow := time.Now()
@@ -399,12 +456,13 @@ func TestTimeslotItemPostgres_Update(t *testing.T) {
 			name: "OK",
 			mockBehavior: func() {
 				mock.ExpectExec(query).
-					WithArgs(newTitle, newDescription, timeNow, timeNow, newDone, 1, 1).
+					WithArgs(newTitle, newDescription, timeNow, timeNow, newDone, 1, 1, 0).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 			input: input{
-				itemID: 1,
-				userID: 1,
+				itemID:  1,
+				userID:  1,
+				version: 0,
 				update: entity.UpdateItemInput{
 					Title:       &newTitle,
 					Description: &newDescription,
@@ -420,12 +478,13 @@ func TestTimeslotItemPostgres_Update(t *testing.T) {
 			name: "OK without done",
 			mockBehavior: func() {
 				mock.ExpectExec(query).
-					WithArgs(newTitle, newDescription, timeNow, timeNow, 1, 1).
+					WithArgs(newTitle, newDescription, timeNow, timeNow, 1, 1, 0).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 			input: input{
-				itemID: 1,
-				userID: 1,
+				itemID:  1,
+				userID:  1,
+				version: 0,
 				update: entity.UpdateItemInput{
 					Title:       &newTitle,
 					Description: &newDescription,
@@ -441,12 +500,13 @@ func TestTimeslotItemPostgres_Update(t *testing.T) {
 			name: "OK without done and time",
 			mockBehavior: func() {
 				mock.ExpectExec(query).
-					WithArgs(newTitle, newDescription, 1, 1).
+					WithArgs(newTitle, newDescription, 1, 1, 0).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 			input: input{
-				itemID: 1,
-				userID: 1,
+				itemID:  1,
+				userID:  1,
+				version: 0,
 				update: entity.UpdateItemInput{
 					Title:       &newTitle,
 					Description: &newDescription,
@@ -474,12 +534,13 @@ func TestTimeslotItemPostgres_Update(t *testing.T) {
 					postgres.ListsItemsTable,
 					postgres.UsersListsTable,
 				)).
-					WithArgs(1, 1).
+					WithArgs(1, 1, 0).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 			input: input{
-				itemID: 1,
-				userID: 1,
+				itemID:  1,
+				userID:  1,
+				version: 0,
 				update: entity.UpdateItemInput{
 					Title:       nil,
 					Description: nil,
@@ -491,6 +552,28 @@ func TestTimeslotItemPostgres_Update(t *testing.T) {
 
 			wantErr: false,
 		},
+		{
+			name: "Stale Version",
+			mockBehavior: func() {
+				mock.ExpectExec(query).
+					WithArgs(newTitle, 1, 1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			input: input{
+				itemID:  1,
+				userID:  1,
+				version: 2,
+				update: entity.UpdateItemInput{
+					Title:       &newTitle,
+					Description: nil,
+					Start:       nil,
+					End:         nil,
+					Done:        nil,
+				},
+			},
+
+			wantErr: true,
+		},
 	}
 
 	for _, testCase := range testTable {
@@ -498,9 +581,11 @@ func TestTimeslotItemPostgres_Update(t *testing.T) {
 			testCase.mockBehavior()
 
 			err1 := rep.TimeslotItem.Update(
+				context.Background(),
 				testCase.input.userID,
 				testCase.input.itemID,
 				testCase.input.update,
+				testCase.input.version,
 			)
 			if testCase.wantErr {
 				require.Error(t, err1)
@@ -519,7 +604,7 @@ func TestTimeslotItemPostgres_Delete(t *testing.T) {
 	}
 	defer dataBase.Close()
 
-	rep := repository.NewRepository(dataBase)
+	rep := repository.NewRepository(dataBase, repository.QueryTimeouts{})
 	query := fmt.Sprintf(
 		`
 			DELETE FROM %s ti USING %s li, %s ul
@@ -574,7 +659,7 @@ func TestTimeslotItemPostgres_Delete(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mockBehavior()
 
-			err1 := rep.TimeslotItem.Delete(testCase.input.userID, testCase.input.itemID)
+			err1 := rep.TimeslotItem.Delete(context.Background(), testCase.input.userID, testCase.input.itemID)
 			if testCase.wantErr {
 				require.Error(t, err1)
 			} else {
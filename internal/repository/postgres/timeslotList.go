@@ -1,34 +1,36 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	"github.com/jmoiron/sqlx"
-	"github.com/sirupsen/logrus"
 	"main.go/internal/entity"
+	"main.go/internal/logging"
+	"main.go/internal/repository/sqltrace"
 )
 
 type TimeslotList interface {
-	Create(userID int, list entity.TimeslotsList) (int, error)
-	GetAll(userID int) ([]entity.TimeslotsList, error)
-	GetByID(userID, listID int) (entity.TimeslotsList, error)
-	Delete(userID, listID int) error
-	Update(userID, listID int, input entity.UpdateListInput) error
+	Create(ctx context.Context, userID int, list entity.TimeslotsList) (int, error)
+	GetAll(ctx context.Context, userID int, filter entity.ListsFilter) ([]entity.TimeslotsList, error)
+	GetByID(ctx context.Context, userID, listID int) (entity.TimeslotsList, error)
+	Delete(ctx context.Context, userID, listID int) error
+	Update(ctx context.Context, userID, listID int, input entity.UpdateListInput, version int) error
 }
 
 type TimeslotListPostgres struct {
-	db *sqlx.DB
+	db *sqltrace.DB
 }
 
-func NewTimeslotListPostgres(db *sqlx.DB) *TimeslotListPostgres {
-	return &TimeslotListPostgres{db: db}
+func NewTimeslotListPostgres(db *sqlx.DB, timeouts sqltrace.Timeouts) *TimeslotListPostgres {
+	return &TimeslotListPostgres{db: newTracedDB(db, timeouts)}
 }
 
-func (r *TimeslotListPostgres) Create(userID int, list entity.TimeslotsList) (int, error) {
-	transaction, err := r.db.Begin()
+func (r *TimeslotListPostgres) Create(ctx context.Context, userID int, list entity.TimeslotsList) (int, error) {
+	transaction, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return 0, err
+		return 0, translateError(err, "list")
 	}
 
 	var listID int
@@ -41,14 +43,14 @@ func (r *TimeslotListPostgres) Create(userID int, list entity.TimeslotsList) (in
 			    id`,
 		TimeslotListsTable,
 	)
-	row := transaction.QueryRow(createListQuery, list.Title, list.Description)
+	row := transaction.QueryRowContext(ctx, createListQuery, list.Title, list.Description)
 
 	if err = row.Scan(&listID); err != nil {
 		if err1 := transaction.Rollback(); err1 != nil {
 			return 0, err
 		}
 
-		return 0, err
+		return 0, translateError(err, "list")
 	}
 
 	createUsersListQuery := fmt.Sprintf(
@@ -58,40 +60,55 @@ func (r *TimeslotListPostgres) Create(userID int, list entity.TimeslotsList) (in
 		UsersListsTable,
 	)
 
-	if _, err = transaction.Exec(createUsersListQuery, userID, listID); err != nil {
+	if _, err = transaction.ExecContext(ctx, createUsersListQuery, userID, listID); err != nil {
 		if err1 := transaction.Rollback(); err1 != nil {
 			return 0, err1
 		}
 
-		return 0, err
+		return 0, translateError(err, "list")
 	}
 
 	return listID, transaction.Commit()
 }
 
-func (r *TimeslotListPostgres) GetAll(userID int) ([]entity.TimeslotsList, error) {
+func (r *TimeslotListPostgres) GetAll(
+	ctx context.Context,
+	userID int,
+	filter entity.ListsFilter,
+) ([]entity.TimeslotsList, error) {
 	var lists []entity.TimeslotsList
 
+	conditions, filterArgs, argID := listsFilterConditions(filter, 2)
 	query := fmt.Sprintf(
 		`
 			SELECT
 			    tl.id,
 			    tl.title,
-			    tl.description
+			    tl.description,
+			    tl.version
 			FROM
 			    %s tl
 			    INNER JOIN %s ul ON tl.id = ul.list_id
 			WHERE
-			    ul.user_id = $1`,
+			    ul.user_id = $1
+			    %s
+			ORDER BY
+			    tl.id
+			LIMIT $%d`,
 		TimeslotListsTable,
 		UsersListsTable,
+		andConditions(conditions),
+		argID,
 	)
-	err := r.db.Select(&lists, query, userID)
+
+	args := append([]interface{}{userID}, filterArgs...)
+	args = append(args, filter.Limit)
+	err := r.db.SelectContext(ctx, &lists, query, args...)
 
 	return lists, err
 }
 
-func (r *TimeslotListPostgres) GetByID(userID, listID int) (entity.TimeslotsList, error) {
+func (r *TimeslotListPostgres) GetByID(ctx context.Context, userID, listID int) (entity.TimeslotsList, error) {
 	var list entity.TimeslotsList
 
 	query := fmt.Sprintf(
@@ -99,7 +116,8 @@ func (r *TimeslotListPostgres) GetByID(userID, listID int) (entity.TimeslotsList
 			SELECT
 			    tl.id,
 			    tl.title,
-			    tl.description
+			    tl.description,
+			    tl.version
 			FROM
 			    %s tl
 			    INNER JOIN %s ul ON tl.id = ul.list_id
@@ -109,12 +127,20 @@ func (r *TimeslotListPostgres) GetByID(userID, listID int) (entity.TimeslotsList
 		TimeslotListsTable,
 		UsersListsTable,
 	)
-	err := r.db.Get(&list, query, userID, listID)
+	err := r.db.GetContext(ctx, &list, query, userID, listID)
 
-	return list, err
+	return list, translateError(err, "list")
 }
 
-func (r *TimeslotListPostgres) Update(userID, listID int, input entity.UpdateListInput) error {
+// Update changes the list when its version still equals version, a zero
+// version skips the check. It returns a not found error wrapping sql.ErrNoRows
+// when no list was changed.
+func (r *TimeslotListPostgres) Update(
+	ctx context.Context,
+	userID, listID int,
+	input entity.UpdateListInput,
+	version int,
+) error {
 	setValues := make([]string, 0)
 	args := make([]interface{}, 0)
 	argID := 1
@@ -142,26 +168,33 @@ func (r *TimeslotListPostgres) Update(userID, listID int, input entity.UpdateLis
 			    %s ul
 			WHERE
 			    tl.id = ul.list_id
-			    AND ul.list_id = $ %d
-			    AND ul.user_id = $ %d`,
+			    AND ul.list_id = $%d
+			    AND ul.user_id = $%d
+			    AND ($%d = 0
+			        OR tl.version = $%d)`,
 		TimeslotListsTable,
 		setQuery,
 		UsersListsTable,
 		argID,
 		argID+1,
+		argID+2,
+		argID+2,
 	)
 
-	args = append(args, listID, userID)
+	args = append(args, listID, userID, version)
 
-	logrus.Debugf("updateQuery: %s", query)
-	logrus.Debugf("args: %s", args...)
+	logging.FromContext(ctx).Debugf("updateQuery: %s", query)
+	logging.FromContext(ctx).Debugf("args: %s", args...)
 
-	_, err := r.db.Exec(query, args...)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return translateError(err, "list")
+	}
 
-	return err
+	return translateError(checkUpdated(result), "list")
 }
 
-func (r *TimeslotListPostgres) Delete(userID, listID int) error {
+func (r *TimeslotListPostgres) Delete(ctx context.Context, userID, listID int) error {
 	query := fmt.Sprintf(
 		`
 			DELETE FROM %s tl USING %s ul
@@ -171,7 +204,7 @@ func (r *TimeslotListPostgres) Delete(userID, listID int) error {
 		TimeslotListsTable,
 		UsersListsTable,
 	)
-	_, err := r.db.Exec(query, userID, listID)
+	_, err := r.db.ExecContext(ctx, query, userID, listID)
 
 	return err
 }
@@ -1,6 +1,7 @@
 package postgres_test
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -21,7 +22,7 @@ func TestTimeslotsListPostgres_Create(t *testing.T) {
 
 	defer dataBase.Close()
 
-	rep := repository.NewRepository(dataBase)
+	rep := repository.NewRepository(dataBase, repository.QueryTimeouts{})
 	query1 := fmt.Sprintf( //nolint:perfsprint // general style for queries
 		`
 			INSERT INTO %s`,
@@ -126,7 +127,7 @@ func TestTimeslotsListPostgres_Create(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mockBehavior(testCase.input, testCase.want)
 
-			got, err1 := rep.TimeslotList.Create(testCase.input.userID, testCase.input.list)
+			got, err1 := rep.TimeslotList.Create(context.Background(), testCase.input.userID, testCase.input.list)
 			if testCase.wantErr {
 				require.Error(t, err1)
 			} else {
@@ -145,7 +146,7 @@ func TestTimeslotsListPostgres_GetAll(t *testing.T) {
 	}
 	defer dataBase.Close()
 
-	rep := repository.NewRepository(dataBase)
+	rep := repository.NewRepository(dataBase, repository.QueryTimeouts{})
 	query := fmt.Sprintf(
 		`
 			SELECT
@@ -160,6 +161,7 @@ func TestTimeslotsListPostgres_GetAll(t *testing.T) {
 	type input struct {
 		listID int
 		userID int
+		filter entity.ListsFilter
 	}
 
 	testTable := []struct {
@@ -178,11 +180,15 @@ func TestTimeslotsListPostgres_GetAll(t *testing.T) {
 					AddRow(3, "title3", "description3")
 
 				mock.ExpectQuery(query).
-					WithArgs(1).
+					WithArgs(1, 10).
 					WillReturnRows(rows)
 			},
 			input: input{
 				listID: 1, userID: 1,
+				filter: entity.ListsFilter{
+					PageParams: entity.PageParams{Cursor: "", Limit: 10, After: entity.Cursor{}},
+					Title:      "",
+				},
 			},
 
 			want: []entity.TimeslotsList{
@@ -211,22 +217,52 @@ func TestTimeslotsListPostgres_GetAll(t *testing.T) {
 					[]string{"id", "title", "description"},
 				)
 				mock.ExpectQuery(query).
-					WithArgs(1).
+					WithArgs(1, 10).
 					WillReturnRows(rows)
 			},
 			input: input{
 				listID: 1, userID: 1,
+				filter: entity.ListsFilter{
+					PageParams: entity.PageParams{Cursor: "", Limit: 10, After: entity.Cursor{}},
+					Title:      "",
+				},
 			},
 			want:    nil,
 			wantErr: false,
 		},
+		{
+			name: "Filtered Page",
+			mockBehavior: func() {
+				rows := sqlmock.NewRows([]string{"id", "title", "description"}).
+					AddRow(4, "title4", "description4")
+
+				mock.ExpectQuery(query+`tl.title ILIKE (.+) AND tl.id > (.+) ORDER BY tl.id LIMIT (.+)`).
+					WithArgs(1, "title", 3, 10).
+					WillReturnRows(rows)
+			},
+			input: input{
+				listID: 1, userID: 1,
+				filter: entity.ListsFilter{
+					PageParams: entity.PageParams{Cursor: "", Limit: 10, After: entity.Cursor{ID: 3}},
+					Title:      "title",
+				},
+			},
+			want: []entity.TimeslotsList{
+				{
+					ID:          4,
+					Title:       "title4",
+					Description: "description4",
+				},
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, testCase := range testTable {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mockBehavior()
 
-			got, err1 := rep.TimeslotList.GetAll(testCase.input.userID)
+			got, err1 := rep.TimeslotList.GetAll(context.Background(), testCase.input.userID, testCase.input.filter)
 			if testCase.wantErr {
 				require.Error(t, err1)
 			} else {
@@ -245,7 +281,7 @@ func TestTimeslotsListPostgres_GetById(t *testing.T) {
 	}
 	defer dataBase.Close()
 
-	rep := repository.NewRepository(dataBase)
+	rep := repository.NewRepository(dataBase, repository.QueryTimeouts{})
 	query := fmt.Sprintf(
 		`
 			SELECT
@@ -314,7 +350,7 @@ func TestTimeslotsListPostgres_GetById(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mockBehavior()
 
-			got, err1 := rep.TimeslotList.GetByID(testCase.input.userID, testCase.input.listID)
+			got, err1 := rep.TimeslotList.GetByID(context.Background(), testCase.input.userID, testCase.input.listID)
 			if testCase.wantErr {
 				require.Error(t, err1)
 			} else {
@@ -333,7 +369,7 @@ func TestTimeslotsListPostgres_Update(t *testing.T) {
 	}
 	defer dataBase.Close()
 
-	rep := repository.NewRepository(dataBase)
+	rep := repository.NewRepository(dataBase, repository.QueryTimeouts{})
 	query := fmt.Sprintf(
 		`
 			UPDATE
@@ -348,9 +384,10 @@ func TestTimeslotsListPostgres_Update(t *testing.T) {
 	)
 
 	type input struct {
-		listID int
-		userID int
-		update entity.UpdateListInput
+		listID  int
+		userID  int
+		version int
+		update  entity.UpdateListInput
 	}
 
 	newTitle := "New title"
@@ -368,12 +405,13 @@ func TestTimeslotsListPostgres_Update(t *testing.T) {
 			name: "OK",
 			mockBehavior: func() {
 				mock.ExpectExec(query).
-					WithArgs(newTitle, newDescription, 1, 1).
+					WithArgs(newTitle, newDescription, 1, 1, 0).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 			input: input{
-				listID: 1,
-				userID: 1,
+				listID:  1,
+				userID:  1,
+				version: 0,
 				update: entity.UpdateListInput{
 					Title:       &newTitle,
 					Description: &newDescription,
@@ -386,12 +424,13 @@ func TestTimeslotsListPostgres_Update(t *testing.T) {
 			name: "OK without done",
 			mockBehavior: func() {
 				mock.ExpectExec(query).
-					WithArgs(newTitle, newDescription, 1, 1).
+					WithArgs(newTitle, newDescription, 1, 1, 0).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 			input: input{
-				listID: 1,
-				userID: 1,
+				listID:  1,
+				userID:  1,
+				version: 0,
 				update: entity.UpdateListInput{
 					Title:       &newTitle,
 					Description: &newDescription,
@@ -404,12 +443,13 @@ func TestTimeslotsListPostgres_Update(t *testing.T) {
 			name: "OK without done and time",
 			mockBehavior: func() {
 				mock.ExpectExec(query).
-					WithArgs(newTitle, newDescription, 1, 1).
+					WithArgs(newTitle, newDescription, 1, 1, 0).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 			input: input{
-				listID: 1,
-				userID: 1,
+				listID:  1,
+				userID:  1,
+				version: 0,
 				update: entity.UpdateListInput{
 					Title:       &newTitle,
 					Description: &newDescription,
@@ -432,12 +472,13 @@ func TestTimeslotsListPostgres_Update(t *testing.T) {
 					postgres.TimeslotListsTable,
 					postgres.UsersListsTable,
 				)).
-					WithArgs(1, 1).
+					WithArgs(1, 1, 0).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 			input: input{
-				listID: 1,
-				userID: 1,
+				listID:  1,
+				userID:  1,
+				version: 0,
 				update: entity.UpdateListInput{
 					Title:       nil,
 					Description: nil,
@@ -446,6 +487,25 @@ func TestTimeslotsListPostgres_Update(t *testing.T) {
 
 			wantErr: false,
 		},
+		{
+			name: "Stale Version",
+			mockBehavior: func() {
+				mock.ExpectExec(query).
+					WithArgs(newTitle, 1, 1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			input: input{
+				listID:  1,
+				userID:  1,
+				version: 2,
+				update: entity.UpdateListInput{
+					Title:       &newTitle,
+					Description: nil,
+				},
+			},
+
+			wantErr: true,
+		},
 	}
 
 	for _, testCase := range testTable {
@@ -453,9 +513,11 @@ func TestTimeslotsListPostgres_Update(t *testing.T) {
 			testCase.mockBehavior()
 
 			err1 := rep.TimeslotList.Update(
+				context.Background(),
 				testCase.input.userID,
 				testCase.input.listID,
 				testCase.input.update,
+				testCase.input.version,
 			)
 			if testCase.wantErr {
 				require.Error(t, err1)
@@ -474,7 +536,7 @@ func TestTimeslotsListPostgres_Delete(t *testing.T) {
 	}
 	defer dataBase.Close()
 
-	rep := repository.NewRepository(dataBase)
+	rep := repository.NewRepository(dataBase, repository.QueryTimeouts{})
 	query := fmt.Sprintf(
 		`
 			DELETE FROM %s tl USING %s ul
@@ -528,7 +590,7 @@ func TestTimeslotsListPostgres_Delete(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mockBehavior()
 
-			err1 := rep.TimeslotList.Delete(testCase.input.userID, testCase.input.listID)
+			err1 := rep.TimeslotList.Delete(context.Background(), testCase.input.userID, testCase.input.listID)
 			if testCase.wantErr {
 				require.Error(t, err1)
 			} else {
@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"main.go/internal/entity"
+	"main.go/internal/repository/sqltrace"
+)
+
+type Users interface {
+	GetUsers(ctx context.Context) ([]entity.UserInfo, error)
+	SetDisabled(ctx context.Context, userID int, disabled bool) error
+	DeleteUser(ctx context.Context, userID int) error
+	SetPassword(ctx context.Context, userID int, passwordHash string) error
+	SetColor(ctx context.Context, userID int, color string) error
+	TransferList(ctx context.Context, listID, userID int) error
+	GetStats(ctx context.Context, now time.Time) (entity.StudioStats, error)
+}
+
+// UsersPostgres backs the admin tools, which act on any user rather than on
+// behalf of one.
+type UsersPostgres struct {
+	db *sqltrace.DB
+}
+
+func NewUsersPostgres(db *sqlx.DB, timeouts sqltrace.Timeouts) *UsersPostgres {
+	return &UsersPostgres{db: newTracedDB(db, timeouts)}
+}
+
+func (r *UsersPostgres) GetUsers(ctx context.Context) ([]entity.UserInfo, error) {
+	var users []entity.UserInfo
+
+	query := fmt.Sprintf(
+		`
+			SELECT
+			    u.id,
+			    u.name,
+			    u.color,
+			    u.username,
+			    u.disabled,
+			    count(ul.id) AS lists
+			FROM
+			    %s u
+			    LEFT JOIN %s ul ON ul.user_id = u.id
+			GROUP BY
+			    u.id
+			ORDER BY
+			    u.id`,
+		UsersTable,
+		UsersListsTable,
+	)
+
+	if err := r.db.SelectContext(ctx, &users, query); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *UsersPostgres) SetDisabled(ctx context.Context, userID int, disabled bool) error {
+	return r.setColumn(ctx, userID, "disabled", disabled)
+}
+
+func (r *UsersPostgres) SetPassword(ctx context.Context, userID int, passwordHash string) error {
+	return r.setColumn(ctx, userID, "password_hash", passwordHash)
+}
+
+func (r *UsersPostgres) SetColor(ctx context.Context, userID int, color string) error {
+	return r.setColumn(ctx, userID, "color", color)
+}
+
+func (r *UsersPostgres) setColumn(ctx context.Context, userID int, column string, value any) error {
+	query := fmt.Sprintf(`UPDATE %s SET %s = $1 WHERE id = $2`, UsersTable, column)
+
+	result, err := r.db.ExecContext(ctx, query, value, userID)
+	if err != nil {
+		return translateError(err, "user")
+	}
+
+	return translateError(checkUpdated(result), "user")
+}
+
+// DeleteUser removes the user together with their lists and the items in
+// them, which would otherwise be left behind unreachable.
+func (r *UsersPostgres) DeleteUser(ctx context.Context, userID int) error {
+	transaction, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	deleteItemsQuery := fmt.Sprintf(
+		`
+			DELETE FROM %s ti USING %s li, %s ul
+			WHERE ti.id = li.item_id
+			    AND li.list_id = ul.list_id
+			    AND ul.user_id = $1`,
+		TimeslotsItemsTable,
+		ListsItemsTable,
+		UsersListsTable,
+	)
+	deleteListsQuery := fmt.Sprintf(
+		`
+			DELETE FROM %s tl USING %s ul
+			WHERE tl.id = ul.list_id
+			    AND ul.user_id = $1`,
+		TimeslotListsTable,
+		UsersListsTable,
+	)
+
+	for _, query := range []string{deleteItemsQuery, deleteListsQuery} {
+		if _, err = transaction.ExecContext(ctx, query, userID); err != nil {
+			if err1 := transaction.Rollback(); err1 != nil {
+				return err1
+			}
+
+			return err
+		}
+	}
+
+	deleteUserQuery := fmt.Sprintf(`DELETE FROM %s WHERE id = $1`, UsersTable)
+
+	result, err := transaction.ExecContext(ctx, deleteUserQuery, userID)
+	if err == nil {
+		err = checkUpdated(result)
+	}
+
+	if err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return err1
+		}
+
+		return translateError(err, "user")
+	}
+
+	return transaction.Commit()
+}
+
+// TransferList hands the list over to another user.
+func (r *UsersPostgres) TransferList(ctx context.Context, listID, userID int) error {
+	query := fmt.Sprintf(`UPDATE %s SET user_id = $1 WHERE list_id = $2`, UsersListsTable)
+
+	result, err := r.db.ExecContext(ctx, query, userID, listID)
+	if err != nil {
+		return translateError(err, "user")
+	}
+
+	return translateError(checkUpdated(result), "list")
+}
+
+func (r *UsersPostgres) GetStats(ctx context.Context, now time.Time) (entity.StudioStats, error) {
+	var stats entity.StudioStats
+
+	query := fmt.Sprintf(
+		`
+			SELECT
+			    (SELECT count(*) FROM %[1]s) AS users,
+			    (SELECT count(*) FROM %[1]s WHERE disabled) AS disabled_users,
+			    (SELECT count(*) FROM %[2]s) AS lists,
+			    (SELECT count(*) FROM %[3]s) AS items,
+			    (SELECT count(*) FROM %[3]s WHERE beginning >= $1 AND NOT done AND NOT cancelled) AS upcoming_items,
+			    (SELECT count(*) FROM %[4]s WHERE status = $2) AS pending_bookings,
+			    (SELECT count(*) FROM %[5]s WHERE status = $3) AS waiting_clients,
+			    (SELECT count(*) FROM %[6]s WHERE status = $4) AS pending_offers`,
+		UsersTable,
+		TimeslotListsTable,
+		TimeslotsItemsTable,
+		BookingRequestsTable,
+		WaitlistEntriesTable,
+		WaitlistOffersTable,
+	)
+	err := r.db.GetContext(
+		ctx, &stats, query,
+		now, entity.BookingStatusRequested, entity.WaitlistStatusWaiting, entity.OfferStatusPending,
+	)
+
+	return stats, err
+}
@@ -0,0 +1,142 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	apperrors "main.go/internal/errors"
+	"main.go/internal/repository"
+	"main.go/internal/repository/postgres"
+)
+
+func TestUsersPostgres_SetDisabled(t *testing.T) {
+	dataBase, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer dataBase.Close()
+
+	rep := repository.NewRepository(dataBase, repository.QueryTimeouts{})
+	query := fmt.Sprintf(`UPDATE %s SET disabled = (.+) WHERE id = (.+)`, postgres.UsersTable)
+
+	testTable := []struct {
+		name         string
+		mockBehavior func()
+		wantErr      error
+	}{
+		{
+			name: "OK",
+			mockBehavior: func() {
+				mock.ExpectExec(query).WithArgs(true, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func() {
+				mock.ExpectExec(query).WithArgs(true, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: apperrors.ErrNotFound,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior()
+
+			err1 := rep.Users.SetDisabled(context.Background(), 1, true)
+			if testCase.wantErr != nil {
+				require.ErrorIs(t, err1, testCase.wantErr)
+			} else {
+				require.NoError(t, err1)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUsersPostgres_DeleteUser(t *testing.T) {
+	dataBase, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer dataBase.Close()
+
+	rep := repository.NewRepository(dataBase, repository.QueryTimeouts{})
+	deleteItems := fmt.Sprintf(`DELETE FROM %s ti USING`, postgres.TimeslotsItemsTable)
+	deleteLists := fmt.Sprintf(`DELETE FROM %s tl USING`, postgres.TimeslotListsTable)
+	deleteUser := fmt.Sprintf(`DELETE FROM %s WHERE id = (.+)`, postgres.UsersTable)
+
+	testTable := []struct {
+		name         string
+		mockBehavior func()
+		wantErr      bool
+	}{
+		{
+			name: "OK",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(deleteItems).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec(deleteLists).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(deleteUser).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantErr: false,
+		},
+		{
+			name: "Failed Delete Lists",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(deleteItems).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec(deleteLists).WithArgs(1).WillReturnError(errors.New("some error"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "Unknown User",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(deleteItems).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(deleteLists).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(deleteUser).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior()
+
+			err1 := rep.Users.DeleteUser(context.Background(), 1)
+			if testCase.wantErr {
+				require.Error(t, err1)
+			} else {
+				require.NoError(t, err1)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUsersPostgres_TransferList(t *testing.T) {
+	dataBase, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer dataBase.Close()
+
+	rep := repository.NewRepository(dataBase, repository.QueryTimeouts{})
+	query := fmt.Sprintf(`UPDATE %s SET user_id = (.+) WHERE list_id = (.+)`, postgres.UsersListsTable)
+
+	mock.ExpectExec(query).WithArgs(2, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, rep.Users.TransferList(context.Background(), 3, 2))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,436 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"main.go/internal/entity"
+	"main.go/internal/repository/sqltrace"
+)
+
+const (
+	WaitlistEntriesTable = "waitlist_entries"
+	WaitlistOffersTable  = "waitlist_offers"
+)
+
+const offerColumns = `id, entry_id, list_id, slot_start, slot_end, beginning, finish, status, expires_at`
+
+type Waitlist interface {
+	CreateEntry(ctx context.Context, entry entity.WaitlistEntry) (int, error)
+	GetEntries(ctx context.Context, artistID int) ([]entity.WaitlistEntry, error)
+	DeleteEntry(ctx context.Context, entryID int) error
+	CreateOffer(ctx context.Context, slot entity.FreedSlot, hold time.Duration) (entity.WaitlistOffer, error)
+	GetOffers(ctx context.Context, artistID int) ([]entity.WaitlistOffer, error)
+	AcceptOffer(ctx context.Context, offerID int) (int, error)
+	DeclineOffer(ctx context.Context, offerID int) (entity.WaitlistOffer, error)
+	ExpireOffers(ctx context.Context) ([]entity.WaitlistOffer, error)
+}
+
+type WaitlistPostgres struct {
+	db *sqltrace.DB
+}
+
+func NewWaitlistPostgres(db *sqlx.DB, timeouts sqltrace.Timeouts) *WaitlistPostgres {
+	return &WaitlistPostgres{db: newTracedDB(db, timeouts)}
+}
+
+func (r *WaitlistPostgres) CreateEntry(ctx context.Context, entry entity.WaitlistEntry) (int, error) {
+	var entryID int
+
+	query := fmt.Sprintf(
+		`
+			INSERT INTO %s (client_name, client_contact, artist_id, window_start, window_end, duration_minutes)
+			    VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING
+			    id`,
+		WaitlistEntriesTable,
+	)
+	row := r.db.QueryRowContext(ctx,
+		query,
+		entry.ClientName,
+		entry.ClientContact,
+		entry.ArtistID,
+		entry.WindowStart,
+		entry.WindowEnd,
+		entry.DurationMinutes,
+	)
+
+	if err := row.Scan(&entryID); err != nil {
+		return 0, translateError(err, "waitlist_entry")
+	}
+
+	return entryID, nil
+}
+
+// GetEntries returns the entries of one artist, or of everyone when artistID
+// is zero, in the order they will be offered slots.
+func (r *WaitlistPostgres) GetEntries(ctx context.Context, artistID int) ([]entity.WaitlistEntry, error) {
+	var entries []entity.WaitlistEntry
+
+	query := fmt.Sprintf(
+		`
+			SELECT
+			    id,
+			    client_name,
+			    client_contact,
+			    artist_id,
+			    window_start,
+			    window_end,
+			    duration_minutes,
+			    status,
+			    created_at
+			FROM
+			    %s
+			WHERE
+			    $1 = 0
+			    OR artist_id = $1
+			ORDER BY
+			    created_at,
+			    id`,
+		WaitlistEntriesTable,
+	)
+
+	if err := r.db.SelectContext(ctx, &entries, query, artistID); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *WaitlistPostgres) DeleteEntry(ctx context.Context, entryID int) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE id = $1`, WaitlistEntriesTable)
+	_, err := r.db.ExecContext(ctx, query, entryID)
+
+	return err
+}
+
+// CreateOffer picks the oldest waiting entry of an artist owning the slot's
+// list whose window and duration fit into the slot from its opening on and
+// has not been offered this slot before, marks it offered and holds the slot
+// for it. It returns sql.ErrNoRows when nobody on the waitlist fits.
+func (r *WaitlistPostgres) CreateOffer(
+	ctx context.Context,
+	slot entity.FreedSlot,
+	hold time.Duration,
+) (entity.WaitlistOffer, error) {
+	var offer entity.WaitlistOffer
+
+	query := fmt.Sprintf(
+		`
+			WITH candidate AS (
+			    SELECT
+			        we.id,
+			        greatest($5::timestamp, we.window_start) AS beginning,
+			        we.duration_minutes
+			    FROM
+			        %[1]s we
+			    WHERE
+			        we.status = '%[4]s'
+			        AND we.artist_id IN (
+			            SELECT
+			                user_id
+			            FROM
+			                %[3]s
+			            WHERE
+			                list_id = $1)
+			        AND greatest($5::timestamp, we.window_start)
+			            + make_interval(mins => we.duration_minutes) <= least($3::timestamp, we.window_end)
+			        AND NOT EXISTS (
+			            SELECT
+			                1
+			            FROM
+			                %[2]s wo
+			            WHERE
+			                wo.entry_id = we.id
+			                AND wo.list_id = $1
+			                AND wo.slot_start = $2
+			                AND wo.slot_end = $3)
+			    ORDER BY
+			        we.created_at,
+			        we.id
+			    LIMIT 1
+			    FOR UPDATE
+			        SKIP LOCKED
+			),
+			offered AS (
+			    UPDATE
+			        %[1]s we
+			    SET
+			        status = '%[5]s'
+			    FROM
+			        candidate c
+			    WHERE
+			        we.id = c.id
+			    RETURNING
+			        we.id
+			)
+			INSERT INTO %[2]s (entry_id, list_id, slot_start, slot_end, beginning, finish, expires_at)
+			SELECT
+			    c.id,
+			    $1,
+			    $2,
+			    $3,
+			    c.beginning,
+			    c.beginning + make_interval(mins => c.duration_minutes),
+			    now() + make_interval(secs => $4)
+			FROM
+			    candidate c
+			    INNER JOIN offered o ON o.id = c.id
+			RETURNING
+			    %[6]s`,
+		WaitlistEntriesTable,
+		WaitlistOffersTable,
+		UsersListsTable,
+		entity.WaitlistStatusWaiting,
+		entity.WaitlistStatusOffered,
+		offerColumns,
+	)
+
+	err := r.db.GetContext(ctx, &offer, query, slot.ListID, slot.Start, slot.End, hold.Seconds(), slot.Opening())
+
+	return offer, err
+}
+
+// GetOffers returns the pending offers of one artist, or of everyone when
+// artistID is zero.
+func (r *WaitlistPostgres) GetOffers(ctx context.Context, artistID int) ([]entity.WaitlistOffer, error) {
+	var offers []entity.WaitlistOffer
+
+	query := fmt.Sprintf(
+		`
+			SELECT
+			    wo.id,
+			    wo.entry_id,
+			    wo.list_id,
+			    we.client_name,
+			    wo.slot_start,
+			    wo.slot_end,
+			    wo.beginning,
+			    wo.finish,
+			    wo.status,
+			    wo.expires_at
+			FROM
+			    %s wo
+			    INNER JOIN %s we ON we.id = wo.entry_id
+			WHERE
+			    wo.status = '%s'
+			    AND ($1 = 0
+			        OR we.artist_id = $1)
+			ORDER BY
+			    wo.expires_at,
+			    wo.id`,
+		WaitlistOffersTable,
+		WaitlistEntriesTable,
+		entity.OfferStatusPending,
+	)
+
+	if err := r.db.SelectContext(ctx, &offers, query, artistID); err != nil {
+		return nil, err
+	}
+
+	return offers, nil
+}
+
+// AcceptOffer books a pending, unexpired offer as a new item in the offer's
+// list and returns the item id. It returns sql.ErrNoRows when the offer is
+// gone, already answered or expired.
+func (r *WaitlistPostgres) AcceptOffer(ctx context.Context, offerID int) (int, error) {
+	transaction, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var accepted struct {
+		EntryID       int       `db:"entry_id"`
+		ListID        int       `db:"list_id"`
+		Start         time.Time `db:"beginning"`
+		End           time.Time `db:"finish"`
+		ClientName    string    `db:"client_name"`
+		ClientContact string    `db:"client_contact"`
+	}
+
+	acceptQuery := fmt.Sprintf(
+		`
+			UPDATE
+			    %s wo
+			SET
+			    status = '%s'
+			FROM
+			    %s we
+			WHERE
+			    we.id = wo.entry_id
+			    AND wo.id = $1
+			    AND wo.status = '%s'
+			    AND wo.expires_at > now()
+			RETURNING
+			    wo.entry_id,
+			    wo.list_id,
+			    wo.beginning,
+			    wo.finish,
+			    we.client_name,
+			    we.client_contact`,
+		WaitlistOffersTable,
+		entity.OfferStatusAccepted,
+		WaitlistEntriesTable,
+		entity.OfferStatusPending,
+	)
+
+	if err = transaction.GetContext(ctx, &accepted, acceptQuery, offerID); err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return 0, err1
+		}
+
+		return 0, err
+	}
+
+	var itemID int
+
+	createItemQuery := fmt.Sprintf(
+		`
+			INSERT INTO %s (title, description, beginning, finish)
+			    VALUES ($1, $2, $3, $4)
+			RETURNING
+			    id`,
+		TimeslotsItemsTable,
+	)
+	row := transaction.QueryRowContext(ctx,
+		createItemQuery,
+		accepted.ClientName,
+		accepted.ClientContact,
+		accepted.Start,
+		accepted.End,
+	)
+
+	if err = row.Scan(&itemID); err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return 0, err1
+		}
+
+		return 0, err
+	}
+
+	createListsItemsQuery := fmt.Sprintf(
+		`
+			INSERT INTO %s (list_id, item_id)
+			    VALUES ($1, $2)`,
+		ListsItemsTable,
+	)
+
+	if _, err = transaction.ExecContext(ctx, createListsItemsQuery, accepted.ListID, itemID); err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return 0, err1
+		}
+
+		return 0, err
+	}
+
+	bookEntryQuery := fmt.Sprintf(
+		`UPDATE %s SET status = '%s' WHERE id = $1`,
+		WaitlistEntriesTable,
+		entity.WaitlistStatusBooked,
+	)
+
+	if _, err = transaction.ExecContext(ctx, bookEntryQuery, accepted.EntryID); err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return 0, err1
+		}
+
+		return 0, err
+	}
+
+	return itemID, transaction.Commit()
+}
+
+// DeclineOffer closes a pending offer and puts its entry back on the
+// waitlist. It returns sql.ErrNoRows when the offer is not pending.
+func (r *WaitlistPostgres) DeclineOffer(ctx context.Context, offerID int) (entity.WaitlistOffer, error) {
+	var offer entity.WaitlistOffer
+
+	query := fmt.Sprintf(
+		`
+			WITH declined AS (
+			    UPDATE
+			        %[1]s
+			    SET
+			        status = '%[3]s'
+			    WHERE
+			        id = $1
+			        AND status = '%[4]s'
+			    RETURNING
+			        %[6]s
+			),
+			reset AS (
+			    UPDATE
+			        %[2]s we
+			    SET
+			        status = '%[5]s'
+			    FROM
+			        declined d
+			    WHERE
+			        we.id = d.entry_id
+			)
+			SELECT
+			    %[6]s
+			FROM
+			    declined`,
+		WaitlistOffersTable,
+		WaitlistEntriesTable,
+		entity.OfferStatusDeclined,
+		entity.OfferStatusPending,
+		entity.WaitlistStatusWaiting,
+		offerColumns,
+	)
+
+	err := r.db.GetContext(ctx, &offer, query, offerID)
+
+	return offer, err
+}
+
+// ExpireOffers closes every pending offer whose hold ran out, puts the
+// entries back on the waitlist and returns the expired offers.
+func (r *WaitlistPostgres) ExpireOffers(ctx context.Context) ([]entity.WaitlistOffer, error) {
+	var offers []entity.WaitlistOffer
+
+	query := fmt.Sprintf(
+		`
+			WITH expired AS (
+			    UPDATE
+			        %[1]s
+			    SET
+			        status = '%[3]s'
+			    WHERE
+			        status = '%[4]s'
+			        AND expires_at <= now()
+			    RETURNING
+			        %[6]s
+			),
+			reset AS (
+			    UPDATE
+			        %[2]s we
+			    SET
+			        status = '%[5]s'
+			    FROM
+			        expired e
+			    WHERE
+			        we.id = e.entry_id
+			)
+			SELECT
+			    %[6]s
+			FROM
+			    expired`,
+		WaitlistOffersTable,
+		WaitlistEntriesTable,
+		entity.OfferStatusExpired,
+		entity.OfferStatusPending,
+		entity.WaitlistStatusWaiting,
+		offerColumns,
+	)
+
+	if err := r.db.SelectContext(ctx, &offers, query); err != nil {
+		return nil, err
+	}
+
+	return offers, nil
+}
@@ -0,0 +1,239 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"main.go/internal/entity"
+	"main.go/internal/repository"
+	"main.go/internal/repository/postgres"
+)
+
+func TestWaitlistPostgres_CreateEntry(t *testing.T) {
+	dataBase, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer dataBase.Close()
+
+	rep := repository.NewRepository(dataBase, repository.QueryTimeouts{})
+	query := fmt.Sprintf(`INSERT INTO %s`, postgres.WaitlistEntriesTable) //nolint:perfsprint // general style for queries
+
+	timeNow := time.Now()
+	entry := entity.WaitlistEntry{
+		ID:              0,
+		ClientName:      "Maria",
+		ClientContact:   "+100000000",
+		ArtistID:        1,
+		WindowStart:     timeNow,
+		WindowEnd:       timeNow.Add(24 * time.Hour),
+		DurationMinutes: 120,
+		Status:          "",
+		CreatedAt:       time.Time{},
+	}
+
+	testTable := []struct {
+		name         string
+		mockBehavior func()
+		want         int
+		wantErr      bool
+	}{
+		{
+			name: "OK",
+			mockBehavior: func() {
+				mock.ExpectQuery(query).
+					WithArgs(entry.ClientName, entry.ClientContact, entry.ArtistID,
+						entry.WindowStart, entry.WindowEnd, entry.DurationMinutes).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			},
+			want:    1,
+			wantErr: false,
+		},
+		{
+			name: "Unknown Artist",
+			mockBehavior: func() {
+				mock.ExpectQuery(query).
+					WithArgs(entry.ClientName, entry.ClientContact, entry.ArtistID,
+						entry.WindowStart, entry.WindowEnd, entry.DurationMinutes).
+					WillReturnError(errors.New("foreign key violation"))
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior()
+
+			got, err1 := rep.Waitlist.CreateEntry(context.Background(), entry)
+			if testCase.wantErr {
+				require.Error(t, err1)
+			} else {
+				require.NoError(t, err1)
+				require.Equal(t, testCase.want, got)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWaitlistPostgres_CreateOffer(t *testing.T) {
+	dataBase, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer dataBase.Close()
+
+	rep := repository.NewRepository(dataBase, repository.QueryTimeouts{})
+	query := fmt.Sprintf( //nolint:perfsprint // general style for queries
+		`WITH candidate AS (.+) INSERT INTO %s`,
+		postgres.WaitlistOffersTable,
+	)
+
+	timeNow := time.Now()
+	slot := entity.FreedSlot{
+		ListID: 1, Start: timeNow.Add(-time.Hour), End: timeNow.Add(3 * time.Hour), NotBefore: timeNow,
+	}
+	columns := []string{
+		"id", "entry_id", "list_id", "slot_start", "slot_end",
+		"beginning", "finish", "status", "expires_at",
+	}
+
+	testTable := []struct {
+		name         string
+		mockBehavior func()
+		want         entity.WaitlistOffer
+		wantErr      error
+	}{
+		{
+			name: "OK",
+			mockBehavior: func() {
+				mock.ExpectQuery(query).
+					WithArgs(slot.ListID, slot.Start, slot.End, float64(7200), timeNow).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(
+						1, 2, slot.ListID, slot.Start, slot.End, timeNow,
+						timeNow.Add(time.Hour), entity.OfferStatusPending, timeNow.Add(2*time.Hour),
+					))
+			},
+			want: entity.WaitlistOffer{
+				ID:         1,
+				EntryID:    2,
+				ListID:     slot.ListID,
+				ClientName: "",
+				SlotStart:  slot.Start,
+				SlotEnd:    slot.End,
+				Start:      timeNow,
+				End:        timeNow.Add(time.Hour),
+				Status:     entity.OfferStatusPending,
+				ExpiresAt:  timeNow.Add(2 * time.Hour),
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Nobody Fits",
+			mockBehavior: func() {
+				mock.ExpectQuery(query).
+					WithArgs(slot.ListID, slot.Start, slot.End, float64(7200), timeNow).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			want:    entity.WaitlistOffer{},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior()
+
+			got, err1 := rep.Waitlist.CreateOffer(context.Background(), slot, 2*time.Hour)
+			if testCase.wantErr != nil {
+				require.ErrorIs(t, err1, testCase.wantErr)
+			} else {
+				require.NoError(t, err1)
+				require.Equal(t, testCase.want, got)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWaitlistPostgres_AcceptOffer(t *testing.T) {
+	dataBase, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer dataBase.Close()
+
+	rep := repository.NewRepository(dataBase, repository.QueryTimeouts{})
+	acceptQuery := fmt.Sprintf(`UPDATE %s wo SET status`, postgres.WaitlistOffersTable) //nolint:perfsprint // general style for queries
+	itemQuery := fmt.Sprintf(`INSERT INTO %s`, postgres.TimeslotsItemsTable)            //nolint:perfsprint // general style for queries
+	listsItemsQuery := fmt.Sprintf(`INSERT INTO %s`, postgres.ListsItemsTable)          //nolint:perfsprint // general style for queries
+	entryQuery := fmt.Sprintf(`UPDATE %s SET status`, postgres.WaitlistEntriesTable)    //nolint:perfsprint // general style for queries
+
+	timeNow := time.Now()
+	columns := []string{"entry_id", "list_id", "beginning", "finish", "client_name", "client_contact"}
+
+	testTable := []struct {
+		name         string
+		mockBehavior func()
+		want         int
+		wantErr      bool
+	}{
+		{
+			name: "OK",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(acceptQuery).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(2, 3, timeNow, timeNow.Add(time.Hour), "Maria", "+100000000"))
+				mock.ExpectQuery(itemQuery).
+					WithArgs("Maria", "+100000000", timeNow, timeNow.Add(time.Hour)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+				mock.ExpectExec(listsItemsQuery).
+					WithArgs(3, 4).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(entryQuery).
+					WithArgs(2).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			want:    4,
+			wantErr: false,
+		},
+		{
+			name: "Offer Expired",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(acceptQuery).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(columns))
+				mock.ExpectRollback()
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior()
+
+			got, err1 := rep.Waitlist.AcceptOffer(context.Background(), 1)
+			if testCase.wantErr {
+				require.Error(t, err1)
+			} else {
+				require.NoError(t, err1)
+				require.Equal(t, testCase.want, got)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package repository
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"main.go/internal/entity"
+	"main.go/internal/health"
+)
+
+// ReplicaLag tells how far a replica is behind its primary.
+type ReplicaLag interface {
+	Lag(ctx context.Context) (time.Duration, error)
+}
+
+// Replica serves the reads Route sends it while it keeps within maxLag of
+// the primary. It starts out unused until the first check.
+type Replica struct {
+	repo   *Repository
+	lag    ReplicaLag
+	maxLag time.Duration
+	usable atomic.Bool
+}
+
+func NewReplica(repo *Repository, lag ReplicaLag, maxLag time.Duration) *Replica {
+	return &Replica{repo: repo, lag: lag, maxLag: maxLag, usable: atomic.Bool{}}
+}
+
+// Check measures the lag and takes the replica in or out of use.
+func (r *Replica) Check(ctx context.Context) error {
+	lag, err := r.lag.Lag(ctx)
+	usable := err == nil && lag <= r.maxLag
+
+	if r.usable.Swap(usable) != usable {
+		switch {
+		case usable:
+			logrus.Infof("reading from the replica, %s behind", lag)
+		case err != nil:
+			logrus.Warnf("reading from the primary, replica failed: %s", err.Error())
+		default:
+			logrus.Warnf("reading from the primary, replica is %s behind", lag)
+		}
+	}
+
+	return err
+}
+
+// Run checks the replica every interval until ctx is cancelled.
+func (r *Replica) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_ = r.Check(ctx)
+
+		health.Beat(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type sessionKey struct{}
+
+// WithSession starts a unit of work, such as a request, whose reads may go
+// to the replica until it writes. Without one every read goes to the
+// primary, so callers that read back what they wrote need nothing special.
+func WithSession(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sessionKey{}, new(atomic.Bool))
+}
+
+// wrote keeps the rest of the session's reads on the primary, which has the
+// write the replica may not have yet.
+func wrote(ctx context.Context) {
+	if written, ok := ctx.Value(sessionKey{}).(*atomic.Bool); ok {
+		written.Store(true)
+	}
+}
+
+// onPrimary sends the reads made with ctx to the primary, whatever the
+// session allows.
+func onPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sessionKey{}, nil)
+}
+
+func (r *Replica) serves(ctx context.Context) bool {
+	written, ok := ctx.Value(sessionKey{}).(*atomic.Bool)
+
+	return ok && !written.Load() && r.usable.Load()
+}
+
+// Route sends the calendar reads of repo, which carry most of the load, to
+// replica and falls back to repo when the replica fails. The writes that
+// change what those reads return keep the rest of their session on repo.
+// Other calls go to repo as before.
+func Route(repo *Repository, replica *Replica) *Repository {
+	routed := *repo
+	routed.Authorization = &routedAuthorization{Authorization: repo.Authorization}
+	routed.TimeslotList = &routedTimeslotList{
+		TimeslotList: repo.TimeslotList,
+		replica:      replica.repo.TimeslotList,
+		router:       replica,
+	}
+	routed.TimeslotItem = &routedTimeslotItem{
+		TimeslotItem: repo.TimeslotItem,
+		replica:      replica.repo.TimeslotItem,
+		router:       replica,
+	}
+	routed.Search = &routedSearch{primary: repo.Search, replica: replica.repo.Search, router: replica}
+	routed.Waitlist = &routedWaitlist{Waitlist: repo.Waitlist}
+	routed.Booking = &routedBooking{Booking: repo.Booking, replica: replica.repo.Booking, router: replica}
+	routed.Users = &routedUsers{Users: repo.Users}
+
+	return &routed
+}
+
+// read runs call on the replica when it serves ctx, and on the primary when
+// it doesn't or fails.
+func read[R, T any](ctx context.Context, router *Replica, primary, replica R, call func(repo R) (T, error)) (T, error) {
+	if router.serves(ctx) {
+		result, err := call(replica)
+		if err == nil || ctx.Err() != nil {
+			return result, err
+		}
+	}
+
+	return call(primary)
+}
+
+type routedAuthorization struct {
+	Authorization
+}
+
+func (r *routedAuthorization) CreateUser(ctx context.Context, user entity.User) (int, error) {
+	wrote(ctx)
+
+	return r.Authorization.CreateUser(ctx, user)
+}
+
+type routedTimeslotList struct {
+	TimeslotList
+	replica TimeslotList
+	router  *Replica
+}
+
+func (r *routedTimeslotList) Create(ctx context.Context, userID int, list entity.TimeslotsList) (int, error) {
+	wrote(ctx)
+
+	return r.TimeslotList.Create(ctx, userID, list)
+}
+
+func (r *routedTimeslotList) GetAll(
+	ctx context.Context,
+	userID int,
+	filter entity.ListsFilter,
+) ([]entity.TimeslotsList, error) {
+	return read(ctx, r.router, r.TimeslotList, r.replica, func(repo TimeslotList) ([]entity.TimeslotsList, error) {
+		return repo.GetAll(ctx, userID, filter)
+	})
+}
+
+func (r *routedTimeslotList) GetByID(ctx context.Context, userID, listID int) (entity.TimeslotsList, error) {
+	return read(ctx, r.router, r.TimeslotList, r.replica, func(repo TimeslotList) (entity.TimeslotsList, error) {
+		return repo.GetByID(ctx, userID, listID)
+	})
+}
+
+func (r *routedTimeslotList) Delete(ctx context.Context, userID, listID int) error {
+	wrote(ctx)
+
+	return r.TimeslotList.Delete(ctx, userID, listID)
+}
+
+func (r *routedTimeslotList) Update(
+	ctx context.Context,
+	userID, listID int,
+	input entity.UpdateListInput,
+	version int,
+) error {
+	wrote(ctx)
+
+	return r.TimeslotList.Update(ctx, userID, listID, input, version)
+}
+
+type routedTimeslotItem struct {
+	TimeslotItem
+	replica TimeslotItem
+	router  *Replica
+}
+
+func (r *routedTimeslotItem) Create(ctx context.Context, listID int, item entity.TimeslotItem) (int, error) {
+	wrote(ctx)
+
+	return r.TimeslotItem.Create(ctx, listID, item)
+}
+
+func (r *routedTimeslotItem) GetAll(
+	ctx context.Context,
+	userID, listID int,
+	filter entity.ItemsFilter,
+) ([]entity.TimeslotItem, error) {
+	return read(ctx, r.router, r.TimeslotItem, r.replica, func(repo TimeslotItem) ([]entity.TimeslotItem, error) {
+		return repo.GetAll(ctx, userID, listID, filter)
+	})
+}
+
+func (r *routedTimeslotItem) GetByID(ctx context.Context, userID, itemID int) (entity.TimeslotItem, error) {
+	return read(ctx, r.router, r.TimeslotItem, r.replica, func(repo TimeslotItem) (entity.TimeslotItem, error) {
+		return repo.GetByID(ctx, userID, itemID)
+	})
+}
+
+func (r *routedTimeslotItem) Delete(ctx context.Context, userID, itemID int) error {
+	wrote(ctx)
+
+	return r.TimeslotItem.Delete(ctx, userID, itemID)
+}
+
+func (r *routedTimeslotItem) Update(
+	ctx context.Context,
+	userID, itemID int,
+	input entity.UpdateItemInput,
+	version int,
+) error {
+	wrote(ctx)
+
+	return r.TimeslotItem.Update(ctx, userID, itemID, input, version)
+}
+
+func (r *routedTimeslotItem) GetByRange(ctx context.Context, input entity.ItemsByRange) ([]entity.TimeslotItem, error) {
+	return read(ctx, r.router, r.TimeslotItem, r.replica, func(repo TimeslotItem) ([]entity.TimeslotItem, error) {
+		return repo.GetByRange(ctx, input)
+	})
+}
+
+func (r *routedTimeslotItem) CountUpcoming(ctx context.Context, from, to time.Time) (int, error) {
+	return read(ctx, r.router, r.TimeslotItem, r.replica, func(repo TimeslotItem) (int, error) {
+		return repo.CountUpcoming(ctx, from, to)
+	})
+}
+
+type routedSearch struct {
+	primary Search
+	replica Search
+	router  *Replica
+}
+
+func (r *routedSearch) Search(
+	ctx context.Context,
+	userID int,
+	tsQuery string,
+	limit int,
+) ([]entity.SearchResult, error) {
+	return read(ctx, r.router, r.primary, r.replica, func(repo Search) ([]entity.SearchResult, error) {
+		return repo.Search(ctx, userID, tsQuery, limit)
+	})
+}
+
+// routedWaitlist only marks accepted offers, which book an item.
+type routedWaitlist struct {
+	Waitlist
+}
+
+func (r *routedWaitlist) AcceptOffer(ctx context.Context, offerID int) (int, error) {
+	wrote(ctx)
+
+	return r.Waitlist.AcceptOffer(ctx, offerID)
+}
+
+// routedBooking routes what the public booking pages show. The counts that
+// limit abuse stay on the primary, where a lagging replica can't let
+// requests slip through.
+type routedBooking struct {
+	Booking
+	replica Booking
+	router  *Replica
+}
+
+func (r *routedBooking) GetArtists(ctx context.Context) ([]entity.Artist, error) {
+	return read(ctx, r.router, r.Booking, r.replica, func(repo Booking) ([]entity.Artist, error) {
+		return repo.GetArtists(ctx)
+	})
+}
+
+func (r *routedBooking) GetBusy(ctx context.Context, artistID int, start, end time.Time) ([]entity.TimeRange, error) {
+	return read(ctx, r.router, r.Booking, r.replica, func(repo Booking) ([]entity.TimeRange, error) {
+		return repo.GetBusy(ctx, artistID, start, end)
+	})
+}
+
+func (r *routedBooking) ApproveRequest(ctx context.Context, requestID, listID int) (int, error) {
+	wrote(ctx)
+
+	return r.Booking.ApproveRequest(ctx, requestID, listID)
+}
+
+// routedUsers marks the changes to the artists, their colors and their
+// lists, which the routed reads show.
+type routedUsers struct {
+	Users
+}
+
+func (r *routedUsers) SetDisabled(ctx context.Context, userID int, disabled bool) error {
+	wrote(ctx)
+
+	return r.Users.SetDisabled(ctx, userID, disabled)
+}
+
+func (r *routedUsers) DeleteUser(ctx context.Context, userID int) error {
+	wrote(ctx)
+
+	return r.Users.DeleteUser(ctx, userID)
+}
+
+func (r *routedUsers) SetColor(ctx context.Context, userID int, color string) error {
+	wrote(ctx)
+
+	return r.Users.SetColor(ctx, userID, color)
+}
+
+func (r *routedUsers) TransferList(ctx context.Context, listID, userID int) error {
+	wrote(ctx)
+
+	return r.Users.TransferList(ctx, listID, userID)
+}
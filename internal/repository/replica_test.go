@@ -0,0 +1,173 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"main.go/internal/entity"
+	"main.go/internal/repository"
+)
+
+type lagFunc func(ctx context.Context) (time.Duration, error)
+
+func (f lagFunc) Lag(ctx context.Context) (time.Duration, error) {
+	return f(ctx)
+}
+
+type failingItems struct {
+	repository.TimeslotItem
+}
+
+func (failingItems) GetByRange(context.Context, entity.ItemsByRange) ([]entity.TimeslotItem, error) {
+	return nil, errors.New("connection refused")
+}
+
+// newStore returns a memory repository holding one user with a list titled
+// title, so the tests can tell where a read went.
+func newStore(t *testing.T, title string) (*repository.Repository, int) {
+	t.Helper()
+
+	ctx := context.Background()
+	repo := repository.NewMemoryRepository()
+
+	userID, err := repo.Authorization.CreateUser(ctx, entity.User{
+		ID: 0, Name: "alice", Color: "ff0000", Username: "alice", Password: "hash",
+	})
+	require.NoError(t, err)
+
+	listID, err := repo.TimeslotList.Create(ctx, userID, entity.TimeslotsList{
+		ID: 0, Title: title, Description: "", Version: 0,
+	})
+	require.NoError(t, err)
+
+	_, err = repo.TimeslotItem.Create(ctx, listID, entity.TimeslotItem{
+		ID:          0,
+		Title:       title,
+		Description: "",
+		Start:       time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		End:         time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+		Done:        false,
+		Cancelled:   false,
+		Username:    "",
+		Color:       "",
+		ListID:      0,
+		Version:     0,
+	})
+	require.NoError(t, err)
+
+	return repo, listID
+}
+
+func TestRoute(t *testing.T) {
+	testTable := []struct {
+		name      string
+		lag       time.Duration
+		lagErr    error
+		session   bool
+		write     bool
+		wantTitle string
+	}{
+		{
+			name:      "Replica",
+			lag:       time.Second,
+			lagErr:    nil,
+			session:   true,
+			write:     false,
+			wantTitle: "Replica",
+		},
+		{
+			name:      "No Session",
+			lag:       0,
+			lagErr:    nil,
+			session:   false,
+			write:     false,
+			wantTitle: "Primary",
+		},
+		{
+			name:      "After A Write",
+			lag:       0,
+			lagErr:    nil,
+			session:   true,
+			write:     true,
+			wantTitle: "Primary",
+		},
+		{
+			name:      "Lagging",
+			lag:       time.Minute,
+			lagErr:    nil,
+			session:   true,
+			write:     false,
+			wantTitle: "Primary",
+		},
+		{
+			name:      "Unreachable",
+			lag:       0,
+			lagErr:    errors.New("connection refused"),
+			session:   true,
+			write:     false,
+			wantTitle: "Primary",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init deps
+			ctx := context.Background()
+			primary, listID := newStore(t, "Primary")
+			replicaRepo, _ := newStore(t, "Replica")
+
+			replica := repository.NewReplica(replicaRepo, lagFunc(func(context.Context) (time.Duration, error) {
+				return testCase.lag, testCase.lagErr
+			}), 5*time.Second)
+			require.ErrorIs(t, replica.Check(ctx), testCase.lagErr)
+
+			repo := repository.Route(primary, replica)
+
+			if testCase.session {
+				ctx = repository.WithSession(ctx)
+			}
+
+			if testCase.write {
+				title := "Primary"
+				input := entity.UpdateListInput{Title: &title, Description: nil}
+				require.NoError(t, repo.TimeslotList.Update(ctx, 1, listID, input, 1))
+			}
+
+			// Call
+			list, err := repo.TimeslotList.GetByID(ctx, 1, listID)
+
+			// Assert
+			require.NoError(t, err)
+			require.Equal(t, testCase.wantTitle, list.Title)
+		})
+	}
+}
+
+func TestRoute_FallsBack(t *testing.T) {
+	ctx := repository.WithSession(context.Background())
+	primary, _ := newStore(t, "Primary")
+	replicaRepo, _ := newStore(t, "Replica")
+	replicaRepo.TimeslotItem = failingItems{TimeslotItem: replicaRepo.TimeslotItem}
+
+	replica := repository.NewReplica(replicaRepo, lagFunc(func(context.Context) (time.Duration, error) {
+		return 0, nil
+	}), time.Second)
+	require.NoError(t, replica.Check(ctx))
+
+	items, err := repository.Route(primary, replica).TimeslotItem.GetByRange(ctx, entity.ItemsByRange{
+		ItemsFilter: entity.ItemsFilter{
+			PageParams: entity.PageParams{Cursor: "", Limit: entity.DefaultPageLimit, After: entity.Cursor{}},
+			Title:      "",
+			Status:     "",
+			Artist:     "",
+		},
+		Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, "Primary", items[0].Title)
+}
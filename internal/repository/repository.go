@@ -1,43 +1,192 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"github.com/jmoiron/sqlx"
 	"main.go/internal/entity"
+	"main.go/internal/repository/memory"
 	"main.go/internal/repository/postgres"
+	"main.go/internal/repository/sqlite"
+	"main.go/internal/repository/sqltrace"
 )
 
 type Authorization interface {
-	CreateUser(user entity.User) (int, error)
-	GetUser(username, password string) (entity.User, error)
+	CreateUser(ctx context.Context, user entity.User) (int, error)
+	GetUser(ctx context.Context, username, password string) (entity.User, error)
+	IsActive(ctx context.Context, userID int) (bool, error)
 }
 
 type TimeslotList interface {
-	Create(userID int, list entity.TimeslotsList) (int, error)
-	GetAll(userID int) ([]entity.TimeslotsList, error)
-	GetByID(userID, listID int) (entity.TimeslotsList, error)
-	Delete(userID, listID int) error
-	Update(userID, listID int, input entity.UpdateListInput) error
+	Create(ctx context.Context, userID int, list entity.TimeslotsList) (int, error)
+	GetAll(ctx context.Context, userID int, filter entity.ListsFilter) ([]entity.TimeslotsList, error)
+	GetByID(ctx context.Context, userID, listID int) (entity.TimeslotsList, error)
+	Delete(ctx context.Context, userID, listID int) error
+	Update(ctx context.Context, userID, listID int, input entity.UpdateListInput, version int) error
 }
 
 type TimeslotItem interface {
-	Create(listID int, item entity.TimeslotItem) (int, error)
-	GetAll(userID, listID int) ([]entity.TimeslotItem, error)
-	GetByID(userID, itemID int) (entity.TimeslotItem, error)
-	Delete(userID, itemID int) error
-	Update(userID, itemID int, input entity.UpdateItemInput) error
-	GetByRange(input entity.ItemsByRange) ([]entity.TimeslotItem, error)
+	Create(ctx context.Context, listID int, item entity.TimeslotItem) (int, error)
+	GetAll(ctx context.Context, userID, listID int, filter entity.ItemsFilter) ([]entity.TimeslotItem, error)
+	GetByID(ctx context.Context, userID, itemID int) (entity.TimeslotItem, error)
+	Delete(ctx context.Context, userID, itemID int) error
+	Update(ctx context.Context, userID, itemID int, input entity.UpdateItemInput, version int) error
+	GetByRange(ctx context.Context, input entity.ItemsByRange) ([]entity.TimeslotItem, error)
+	CountUpcoming(ctx context.Context, from, to time.Time) (int, error)
+}
+
+type Search interface {
+	Search(ctx context.Context, userID int, tsQuery string, limit int) ([]entity.SearchResult, error)
+}
+
+type Waitlist interface {
+	CreateEntry(ctx context.Context, entry entity.WaitlistEntry) (int, error)
+	GetEntries(ctx context.Context, artistID int) ([]entity.WaitlistEntry, error)
+	DeleteEntry(ctx context.Context, entryID int) error
+	CreateOffer(ctx context.Context, slot entity.FreedSlot, hold time.Duration) (entity.WaitlistOffer, error)
+	GetOffers(ctx context.Context, artistID int) ([]entity.WaitlistOffer, error)
+	AcceptOffer(ctx context.Context, offerID int) (int, error)
+	DeclineOffer(ctx context.Context, offerID int) (entity.WaitlistOffer, error)
+	ExpireOffers(ctx context.Context) ([]entity.WaitlistOffer, error)
+}
+
+type Booking interface {
+	GetArtists(ctx context.Context) ([]entity.Artist, error)
+	GetBusy(ctx context.Context, artistID int, start, end time.Time) ([]entity.TimeRange, error)
+	CreateRequest(ctx context.Context, request entity.BookingRequest) (int, error)
+	CountRecentByIP(ctx context.Context, clientIP string, since time.Time) (int, error)
+	CountPendingByEmail(ctx context.Context, clientEmail string) (int, error)
+	GetRequests(ctx context.Context, filter entity.BookingsFilter) ([]entity.BookingRequest, error)
+	ApproveRequest(ctx context.Context, requestID, listID int) (int, error)
+	RejectRequest(ctx context.Context, requestID int) error
+}
+
+type RateLimit interface {
+	Take(ctx context.Context, key string, limit entity.RateLimit, now time.Time) (entity.RateDecision, error)
+	DeleteStale(ctx context.Context, before time.Time) error
+}
+
+type Idempotency interface {
+	Create(ctx context.Context, record entity.IdempotencyKey) (bool, error)
+	Reclaim(ctx context.Context, record entity.IdempotencyKey, claimedBefore time.Time) (bool, error)
+	Get(ctx context.Context, userID int, key string) (entity.IdempotencyKey, error)
+	SaveResponse(ctx context.Context, record entity.IdempotencyKey) error
+	Delete(ctx context.Context, userID int, key string) error
+	DeleteStale(ctx context.Context, before time.Time) error
+}
+
+type Users interface {
+	GetUsers(ctx context.Context) ([]entity.UserInfo, error)
+	SetDisabled(ctx context.Context, userID int, disabled bool) error
+	DeleteUser(ctx context.Context, userID int) error
+	SetPassword(ctx context.Context, userID int, passwordHash string) error
+	SetColor(ctx context.Context, userID int, color string) error
+	TransferList(ctx context.Context, listID, userID int) error
+	GetStats(ctx context.Context, now time.Time) (entity.StudioStats, error)
 }
 
 type Repository struct {
 	Authorization
 	TimeslotList
 	TimeslotItem
+	Search
+	Waitlist
+	Booking
+	RateLimit
+	Idempotency
+	Users
+}
+
+// QueryTimeouts bounds how long a single statement may run, so a hung query
+// fails its request with a timeout error instead of holding it until the
+// write timeout. They are keyed by operation rather than by repository:
+// Default bounds the reads no other field covers, Writes the inserts,
+// updates, deletes and transactions, ItemSearch the filtered item listing,
+// GetByRange the reads of every item in a time range, and Search the full
+// text search. Zero falls back to Default, and a zero Default means no
+// limit.
+type QueryTimeouts struct {
+	Default    time.Duration `mapstructure:"default"`
+	Writes     time.Duration `mapstructure:"writes"`
+	ItemSearch time.Duration `mapstructure:"itemSearch"`
+	GetByRange time.Duration `mapstructure:"getByRange"`
+	Search     time.Duration `mapstructure:"search"`
+}
+
+// or is timeout, or Default when it is not set.
+func (t QueryTimeouts) or(timeout time.Duration) time.Duration {
+	if timeout == 0 {
+		return t.Default
+	}
+
+	return timeout
 }
 
-func NewRepository(db *sqlx.DB) *Repository {
+// statements are the timeouts of the operations without a key of their own.
+func (t QueryTimeouts) statements() sqltrace.Timeouts {
+	return sqltrace.Timeouts{Read: t.Default, Write: t.or(t.Writes)}
+}
+
+// reads are the statement timeouts with reads bounded by timeout.
+func (t QueryTimeouts) reads(timeout time.Duration) sqltrace.Timeouts {
+	return sqltrace.Timeouts{Read: t.or(timeout), Write: t.or(t.Writes)}
+}
+
+func NewRepository(db *sqlx.DB, timeouts QueryTimeouts) *Repository {
+	statements := timeouts.statements()
+
+	return &Repository{
+		Authorization: postgres.NewAuthorizationPostgres(db, statements),
+		TimeslotList:  postgres.NewTimeslotListPostgres(db, statements),
+		TimeslotItem: postgres.NewTimeslotItemPostgres(
+			db, statements, timeouts.or(timeouts.ItemSearch), timeouts.or(timeouts.GetByRange),
+		),
+		Search:      postgres.NewSearchPostgres(db, timeouts.reads(timeouts.Search)),
+		Waitlist:    postgres.NewWaitlistPostgres(db, statements),
+		Booking:     postgres.NewBookingPostgres(db, statements),
+		RateLimit:   postgres.NewRateLimitPostgres(db, statements),
+		Idempotency: postgres.NewIdempotencyPostgres(db, statements),
+		Users:       postgres.NewUsersPostgres(db, statements),
+	}
+}
+
+// NewSQLiteRepository runs on a single SQLite file. Search falls back to LIKE
+// matching, ranked and highlighted in Go.
+func NewSQLiteRepository(db *sqlx.DB, timeouts QueryTimeouts) *Repository {
+	statements := timeouts.statements()
+
+	return &Repository{
+		Authorization: sqlite.NewAuthorizationSQLite(db, statements),
+		TimeslotList:  sqlite.NewTimeslotListSQLite(db, statements),
+		TimeslotItem: sqlite.NewTimeslotItemSQLite(
+			db, statements, timeouts.or(timeouts.ItemSearch), timeouts.or(timeouts.GetByRange),
+		),
+		Search:      sqlite.NewSearchSQLite(db, timeouts.reads(timeouts.Search)),
+		Waitlist:    sqlite.NewWaitlistSQLite(db, statements),
+		Booking:     sqlite.NewBookingSQLite(db, statements),
+		RateLimit:   sqlite.NewRateLimitSQLite(db, statements),
+		Idempotency: sqlite.NewIdempotencySQLite(db, statements),
+		Users:       sqlite.NewUsersSQLite(db, statements),
+	}
+}
+
+// NewMemoryRepository keeps users, lists, items, rate limits and idempotency
+// keys in process memory, for tests and for running the app as a demo
+// without Postgres. The other repositories answer with unsupported errors.
+func NewMemoryRepository() *Repository {
+	store := memory.NewStore()
+	unsupported := memory.Unsupported{}
+
 	return &Repository{
-		Authorization: postgres.NewAuthorizationPostgres(db),
-		TimeslotList:  postgres.NewTimeslotListPostgres(db),
-		TimeslotItem:  postgres.NewTimeslotItemPostgres(db),
+		Authorization: memory.NewAuthorizationMemory(store),
+		TimeslotList:  memory.NewTimeslotListMemory(store),
+		TimeslotItem:  memory.NewTimeslotItemMemory(store),
+		Search:        unsupported,
+		Waitlist:      unsupported,
+		Booking:       unsupported,
+		RateLimit:     memory.NewRateLimitMemory(),
+		Idempotency:   memory.NewIdempotencyMemory(),
+		Users:         unsupported,
 	}
 }
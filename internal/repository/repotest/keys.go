@@ -0,0 +1,110 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"main.go/internal/entity"
+	"main.go/internal/repository"
+)
+
+func testRateLimit(t *testing.T, repo *repository.Repository) {
+	ctx := context.Background()
+	limit := entity.RateLimit{Requests: 1, Per: time.Minute, Burst: 2}
+	now := day.Add(10 * time.Hour)
+
+	for _, allowed := range []bool{true, true, false} {
+		decision, err := repo.RateLimit.Take(ctx, "login:10.0.0.1", limit, now)
+		require.NoError(t, err)
+		require.Equal(t, allowed, decision.Allowed)
+	}
+
+	decision, err := repo.RateLimit.Take(ctx, "login:10.0.0.1", limit, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.True(t, decision.Allowed)
+	require.Equal(t, 0, decision.Remaining)
+
+	decision, err = repo.RateLimit.Take(ctx, "login:10.0.0.2", limit, now)
+	require.NoError(t, err)
+	require.True(t, decision.Allowed)
+	require.Equal(t, 1, decision.Remaining)
+
+	require.NoError(t, repo.RateLimit.DeleteStale(ctx, now.Add(time.Second)))
+
+	decision, err = repo.RateLimit.Take(ctx, "login:10.0.0.2", limit, now)
+	require.NoError(t, err)
+	require.Equal(t, 1, decision.Remaining, "the stale bucket starts over")
+}
+
+func testIdempotency(t *testing.T, repo *repository.Repository) {
+	ctx := context.Background()
+
+	userID := createUser(t, repo, "alice", "ff0000")
+	record := entity.IdempotencyKey{
+		UserID:      userID,
+		Key:         "key",
+		RequestHash: "hash",
+		StatusCode:  0,
+		Response:    nil,
+		CreatedAt:   time.Time{},
+	}
+
+	created, err := repo.Idempotency.Create(ctx, record)
+	require.NoError(t, err)
+	require.True(t, created)
+
+	created, err = repo.Idempotency.Create(ctx, record)
+	require.NoError(t, err)
+	require.False(t, created)
+
+	stored, err := repo.Idempotency.Get(ctx, userID, "key")
+	require.NoError(t, err)
+	require.False(t, stored.Completed())
+
+	reclaimed, err := repo.Idempotency.Reclaim(ctx, record, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.False(t, reclaimed)
+
+	other := record
+	other.RequestHash = "other"
+
+	reclaimed, err = repo.Idempotency.Reclaim(ctx, other, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.False(t, reclaimed)
+
+	reclaimed, err = repo.Idempotency.Reclaim(ctx, record, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.True(t, reclaimed)
+
+	record.StatusCode = 201
+	record.Response = []byte(`{"id":1}`)
+	require.NoError(t, repo.Idempotency.SaveResponse(ctx, record))
+
+	stored, err = repo.Idempotency.Get(ctx, userID, "key")
+	require.NoError(t, err)
+	require.Equal(t, 201, stored.StatusCode)
+	require.Equal(t, []byte(`{"id":1}`), stored.Response)
+
+	reclaimed, err = repo.Idempotency.Reclaim(ctx, record, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.False(t, reclaimed)
+
+	require.NoError(t, repo.Idempotency.DeleteStale(ctx, time.Now().Add(-time.Hour)))
+
+	_, err = repo.Idempotency.Get(ctx, userID, "key")
+	require.NoError(t, err)
+
+	require.NoError(t, repo.Idempotency.DeleteStale(ctx, time.Now().Add(time.Hour)))
+
+	created, err = repo.Idempotency.Create(ctx, record)
+	require.NoError(t, err)
+	require.True(t, created)
+
+	require.NoError(t, repo.Idempotency.Delete(ctx, userID, "key"))
+
+	created, err = repo.Idempotency.Create(ctx, record)
+	require.NoError(t, err)
+	require.True(t, created)
+}
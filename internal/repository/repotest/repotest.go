@@ -0,0 +1,108 @@
+// Package repotest holds the behavioural tests every store has to pass, so
+// that the service layer can't tell Postgres, SQLite and memory apart.
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"main.go/internal/entity"
+	"main.go/internal/repository"
+)
+
+// NewRepository returns a repository on an empty store.
+type NewRepository func(t *testing.T) *repository.Repository
+
+// day is the date the tests schedule on, in UTC as stored by Postgres.
+var day = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) //nolint:gochecknoglobals // test fixture
+
+// RunCore tests users, lists and items, rate limits and idempotency keys,
+// which every store implements.
+func RunCore(t *testing.T, newRepository NewRepository) {
+	t.Helper()
+
+	t.Run("Authorization", func(t *testing.T) { testAuthorization(t, newRepository(t)) })
+	t.Run("TimeslotList Ownership", func(t *testing.T) { testListOwnership(t, newRepository(t)) })
+	t.Run("TimeslotList GetAll", func(t *testing.T) { testListGetAll(t, newRepository(t)) })
+	t.Run("TimeslotItem", func(t *testing.T) { testItems(t, newRepository(t)) })
+	t.Run("Concurrent Access", func(t *testing.T) { testConcurrentAccess(t, newRepository(t)) })
+	t.Run("RateLimit", func(t *testing.T) { testRateLimit(t, newRepository(t)) })
+	t.Run("Idempotency", func(t *testing.T) { testIdempotency(t, newRepository(t)) })
+}
+
+// RunAll adds the tests of the repositories only databases implement.
+func RunAll(t *testing.T, newRepository NewRepository) {
+	t.Helper()
+
+	RunCore(t, newRepository)
+
+	t.Run("Search", func(t *testing.T) { testSearch(t, newRepository(t)) })
+	t.Run("Booking", func(t *testing.T) { testBooking(t, newRepository(t)) })
+	t.Run("Booking Approval", func(t *testing.T) { testBookingApproval(t, newRepository(t)) })
+	t.Run("Waitlist", func(t *testing.T) { testWaitlist(t, newRepository(t)) })
+	t.Run("Users", func(t *testing.T) { testUsers(t, newRepository(t)) })
+}
+
+func createUser(t *testing.T, repo *repository.Repository, username, color string) int {
+	t.Helper()
+
+	id, err := repo.Authorization.CreateUser(context.Background(), entity.User{
+		ID:       0,
+		Name:     username,
+		Color:    color,
+		Username: username,
+		Password: "hash",
+	})
+	require.NoError(t, err)
+
+	return id
+}
+
+func createList(t *testing.T, repo *repository.Repository, userID int, title string) int {
+	t.Helper()
+
+	id, err := repo.TimeslotList.Create(context.Background(), userID, entity.TimeslotsList{
+		ID:          0,
+		Title:       title,
+		Description: "",
+		Version:     0,
+	})
+	require.NoError(t, err)
+
+	return id
+}
+
+func createItem(t *testing.T, repo *repository.Repository, listID int, title string, start time.Time) int {
+	t.Helper()
+
+	id, err := repo.TimeslotItem.Create(context.Background(), listID, newItem(title, start))
+	require.NoError(t, err)
+
+	return id
+}
+
+func newItem(title string, start time.Time) entity.TimeslotItem {
+	return entity.TimeslotItem{
+		ID:          0,
+		Title:       title,
+		Description: "",
+		Start:       start,
+		End:         start.Add(time.Hour),
+		Done:        false,
+		Cancelled:   false,
+		Username:    "",
+		Color:       "",
+		ListID:      0,
+		Version:     0,
+	}
+}
+
+func page(limit int) entity.PageParams {
+	return entity.PageParams{Cursor: "", Limit: limit, After: entity.Cursor{Start: time.Time{}, ID: 0}}
+}
+
+func itemsFilter(status string, limit int) entity.ItemsFilter {
+	return entity.ItemsFilter{PageParams: page(limit), Title: "", Status: status, Artist: ""}
+}
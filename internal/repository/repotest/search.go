@@ -0,0 +1,79 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"main.go/internal/entity"
+	"main.go/internal/repository"
+)
+
+type searchHit struct {
+	Kind string
+	ID   int
+}
+
+func searchHits(results []entity.SearchResult) []searchHit {
+	hits := make([]searchHit, 0, len(results))
+	for _, result := range results {
+		hits = append(hits, searchHit{Kind: result.Kind, ID: result.ID})
+	}
+
+	return hits
+}
+
+func testSearch(t *testing.T, repo *repository.Repository) {
+	ctx := context.Background()
+
+	alice := createUser(t, repo, "alice", "ff0000")
+	bob := createUser(t, repo, "bob", "00ff00")
+	listID := createList(t, repo, alice, "Sleeve sessions")
+	titleItem := createItem(t, repo, listID, "Sleeve outline", day.Add(10*time.Hour))
+
+	consultation := newItem("Consultation", day.Add(14*time.Hour))
+	consultation.Description = "Planning the sleeve with Maria"
+	textItem, err := repo.TimeslotItem.Create(ctx, listID, consultation)
+	require.NoError(t, err)
+
+	createItem(t, repo, createList(t, repo, bob, "Sleeve"), "Sleeve", day)
+
+	booking := newBookingRequest(alice, "client@example.com", day)
+	booking.ClientName = "<b>Client</b>"
+	bookingID, err := repo.Booking.CreateRequest(ctx, booking)
+	require.NoError(t, err)
+
+	results, err := repo.Search.Search(ctx, alice, "slee:*", 10)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []searchHit{
+		{Kind: entity.SearchKindItem, ID: titleItem},
+		{Kind: entity.SearchKindList, ID: listID},
+		{Kind: entity.SearchKindItem, ID: textItem},
+	}, searchHits(results))
+	require.Equal(t, searchHit{Kind: entity.SearchKindItem, ID: textItem}, searchHits(results)[2])
+	require.Greater(t, results[1].Rank, results[2].Rank)
+
+	for _, result := range results {
+		require.Contains(t, result.Snippet, "<mark>")
+	}
+
+	results, err = repo.Search.Search(ctx, alice, "slee:* & mar:*", 10)
+	require.NoError(t, err)
+	require.Equal(t, []searchHit{{Kind: entity.SearchKindItem, ID: textItem}}, searchHits(results))
+	require.Equal(t, "Consultation", results[0].Title)
+
+	results, err = repo.Search.Search(ctx, alice, "drag:*", 10)
+	require.NoError(t, err)
+	require.Equal(t, []searchHit{{Kind: entity.SearchKindBooking, ID: bookingID}}, searchHits(results))
+	require.Contains(t, results[0].Snippet, "&lt;b&gt;Client&lt;/b&gt;", "client data is escaped")
+	require.NotContains(t, results[0].Snippet, "<b>")
+
+	results, err = repo.Search.Search(ctx, alice, "leeve:*", 10)
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	results, err = repo.Search.Search(ctx, alice, "slee:*", 1)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+}
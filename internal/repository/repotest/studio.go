@@ -0,0 +1,294 @@
+package repotest
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"main.go/internal/entity"
+	apperrors "main.go/internal/errors"
+	"main.go/internal/repository"
+)
+
+func newBookingRequest(artistID int, email string, start time.Time) entity.BookingRequest {
+	return entity.BookingRequest{
+		ID:          0,
+		ArtistID:    artistID,
+		ClientName:  "Client",
+		ClientEmail: email,
+		ClientPhone: "",
+		Reference:   "dragon",
+		Start:       start,
+		End:         start.Add(2 * time.Hour),
+		Status:      "",
+		ItemID:      nil,
+		ClientIP:    "10.0.0.1",
+		Website:     "",
+		CreatedAt:   time.Time{},
+	}
+}
+
+func testBooking(t *testing.T, repo *repository.Repository) {
+	ctx := context.Background()
+
+	alice := createUser(t, repo, "alice", "ff0000")
+	bob := createUser(t, repo, "bob", "00ff00")
+	listID := createList(t, repo, alice, "Bookings")
+
+	require.NoError(t, repo.Users.SetDisabled(ctx, bob, true))
+
+	artists, err := repo.Booking.GetArtists(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []entity.Artist{{ID: alice, Name: "alice", Color: "ff0000"}}, artists)
+
+	first, err := repo.Booking.CreateRequest(ctx, newBookingRequest(alice, "Client@Example.com", day.Add(10*time.Hour)))
+	require.NoError(t, err)
+
+	second, err := repo.Booking.CreateRequest(ctx, newBookingRequest(alice, "client@example.com", day.Add(14*time.Hour)))
+	require.NoError(t, err)
+
+	_, err = repo.Booking.CreateRequest(ctx, newBookingRequest(alice+bob, "client@example.com", day))
+	require.ErrorIs(t, err, apperrors.ErrValidation)
+
+	count, err := repo.Booking.CountRecentByIP(ctx, "10.0.0.1", time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	count, err = repo.Booking.CountPendingByEmail(ctx, "CLIENT@example.com")
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	itemID, err := repo.Booking.ApproveRequest(ctx, first, listID)
+	require.NoError(t, err)
+
+	_, err = repo.Booking.ApproveRequest(ctx, first, listID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.NoError(t, repo.Booking.RejectRequest(ctx, second))
+	require.ErrorIs(t, repo.Booking.RejectRequest(ctx, first), sql.ErrNoRows)
+
+	requests, err := repo.Booking.GetRequests(ctx, entity.BookingsFilter{ArtistID: alice, Status: ""})
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	require.Equal(t, first, requests[0].ID)
+	require.Equal(t, entity.BookingStatusApproved, requests[0].Status)
+	require.Equal(t, &itemID, requests[0].ItemID)
+	require.Equal(t, entity.BookingStatusRejected, requests[1].Status)
+	require.Nil(t, requests[1].ItemID)
+
+	pending := entity.BookingsFilter{ArtistID: 0, Status: entity.BookingStatusRequested}
+
+	requests, err = repo.Booking.GetRequests(ctx, pending)
+	require.NoError(t, err)
+	require.Empty(t, requests)
+
+	item, err := repo.TimeslotItem.GetByID(ctx, alice, itemID)
+	require.NoError(t, err)
+	require.Equal(t, "Client", item.Title)
+	require.Equal(t, "dragon", item.Description)
+
+	busy, err := repo.Booking.GetBusy(ctx, alice, day, day.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, busy, 1)
+	require.True(t, busy[0].Start.Equal(day.Add(10*time.Hour)))
+	require.True(t, busy[0].End.Equal(day.Add(12*time.Hour)))
+}
+
+func testBookingApproval(t *testing.T, repo *repository.Repository) {
+	ctx := context.Background()
+
+	alice := createUser(t, repo, "alice", "ff0000")
+	bob := createUser(t, repo, "bob", "00ff00")
+	aliceList := createList(t, repo, alice, "Bookings")
+	bobList := createList(t, repo, bob, "Bookings")
+
+	first, err := repo.Booking.CreateRequest(ctx, newBookingRequest(alice, "one@example.com", day.Add(10*time.Hour)))
+	require.NoError(t, err)
+
+	overlapping, err := repo.Booking.CreateRequest(ctx, newBookingRequest(alice, "two@example.com", day.Add(11*time.Hour)))
+	require.NoError(t, err)
+
+	_, err = repo.Booking.ApproveRequest(ctx, first, bobList)
+	require.ErrorIs(t, err, entity.ErrBookingListForeign)
+
+	_, err = repo.Booking.ApproveRequest(ctx, first, aliceList)
+	require.NoError(t, err)
+
+	_, err = repo.Booking.ApproveRequest(ctx, overlapping, aliceList)
+	require.ErrorIs(t, err, entity.ErrBookingSlotTaken)
+
+	pending := entity.BookingsFilter{ArtistID: alice, Status: entity.BookingStatusRequested}
+
+	requests, err := repo.Booking.GetRequests(ctx, pending)
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	require.Equal(t, overlapping, requests[0].ID)
+}
+
+func testWaitlist(t *testing.T, repo *repository.Repository) {
+	ctx := context.Background()
+
+	alice := createUser(t, repo, "alice", "ff0000")
+	listID := createList(t, repo, alice, "Week")
+
+	entryID, err := repo.Waitlist.CreateEntry(ctx, entity.WaitlistEntry{
+		ID:              0,
+		ClientName:      "Client",
+		ClientContact:   "client@example.com",
+		ArtistID:        alice,
+		WindowStart:     day.Add(11 * time.Hour),
+		WindowEnd:       day.Add(18 * time.Hour),
+		DurationMinutes: 90,
+		Status:          "",
+		CreatedAt:       time.Time{},
+	})
+	require.NoError(t, err)
+
+	entries, err := repo.Waitlist.GetEntries(ctx, alice)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, entity.WaitlistStatusWaiting, entries[0].Status)
+
+	tooShort := entity.FreedSlot{
+		ListID: listID, Start: day.Add(10 * time.Hour), End: day.Add(12 * time.Hour), NotBefore: time.Time{},
+	}
+	_, err = repo.Waitlist.CreateOffer(ctx, tooShort, time.Hour)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	slot := entity.FreedSlot{
+		ListID: listID, Start: day.Add(10 * time.Hour), End: day.Add(13 * time.Hour), NotBefore: time.Time{},
+	}
+
+	offer, err := repo.Waitlist.CreateOffer(ctx, slot, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, entryID, offer.EntryID)
+	require.True(t, offer.Start.Equal(day.Add(11*time.Hour)))
+	require.True(t, offer.End.Equal(day.Add(12*time.Hour+30*time.Minute)))
+	require.Equal(t, entity.OfferStatusPending, offer.Status)
+
+	offers, err := repo.Waitlist.GetOffers(ctx, alice)
+	require.NoError(t, err)
+	require.Len(t, offers, 1)
+	require.Equal(t, "Client", offers[0].ClientName)
+
+	declined, err := repo.Waitlist.DeclineOffer(ctx, offer.ID)
+	require.NoError(t, err)
+	require.Equal(t, entity.OfferStatusDeclined, declined.Status)
+
+	_, err = repo.Waitlist.DeclineOffer(ctx, offer.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = repo.Waitlist.CreateOffer(ctx, slot, time.Hour)
+	require.ErrorIs(t, err, sql.ErrNoRows, "the same slot is not offered twice")
+
+	underway := slot
+	underway.NotBefore = day.Add(11*time.Hour + 30*time.Minute)
+	_, err = repo.Waitlist.CreateOffer(ctx, underway, time.Hour)
+	require.ErrorIs(t, err, sql.ErrNoRows, "a slot under way is still the slot offered before")
+
+	later := entity.FreedSlot{
+		ListID: listID, Start: day.Add(15 * time.Hour), End: day.Add(17 * time.Hour),
+		NotBefore: day.Add(15*time.Hour + 15*time.Minute),
+	}
+
+	expiring, err := repo.Waitlist.CreateOffer(ctx, later, -time.Minute)
+	require.NoError(t, err)
+	require.True(t, expiring.Start.Equal(later.NotBefore))
+	require.True(t, expiring.SlotStart.Equal(later.Start))
+
+	expired, err := repo.Waitlist.ExpireOffers(ctx)
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	require.Equal(t, expiring.ID, expired[0].ID)
+
+	_, err = repo.Waitlist.AcceptOffer(ctx, expiring.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	evening := entity.FreedSlot{
+		ListID: listID, Start: day.Add(16 * time.Hour), End: day.Add(20 * time.Hour), NotBefore: time.Time{},
+	}
+
+	offer, err = repo.Waitlist.CreateOffer(ctx, evening, time.Hour)
+	require.NoError(t, err)
+
+	itemID, err := repo.Waitlist.AcceptOffer(ctx, offer.ID)
+	require.NoError(t, err)
+
+	item, err := repo.TimeslotItem.GetByID(ctx, alice, itemID)
+	require.NoError(t, err)
+	require.Equal(t, "Client", item.Title)
+	require.Equal(t, "client@example.com", item.Description)
+	require.True(t, item.Start.Equal(day.Add(16*time.Hour)))
+
+	entries, err = repo.Waitlist.GetEntries(ctx, 0)
+	require.NoError(t, err)
+	require.Equal(t, entity.WaitlistStatusBooked, entries[0].Status)
+
+	require.NoError(t, repo.Waitlist.DeleteEntry(ctx, entryID))
+
+	entries, err = repo.Waitlist.GetEntries(ctx, alice)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func testUsers(t *testing.T, repo *repository.Repository) {
+	ctx := context.Background()
+
+	alice := createUser(t, repo, "alice", "ff0000")
+	bob := createUser(t, repo, "bob", "00ff00")
+	listID := createList(t, repo, alice, "Week")
+	createItem(t, repo, listID, "Session", day.Add(10*time.Hour))
+
+	users, err := repo.Users.GetUsers(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []entity.UserInfo{
+		{ID: alice, Name: "alice", Color: "ff0000", Username: "alice", Disabled: false, Lists: 1},
+		{ID: bob, Name: "bob", Color: "00ff00", Username: "bob", Disabled: false, Lists: 0},
+	}, users)
+
+	require.ErrorIs(t, repo.Users.SetColor(ctx, bob, "ff0000"), apperrors.Conflict("color_taken", "", nil))
+	require.ErrorIs(t, repo.Users.SetColor(ctx, bob+alice, "0000ff"), apperrors.ErrNotFound)
+	require.NoError(t, repo.Users.SetPassword(ctx, bob, "new"))
+
+	_, err = repo.Authorization.GetUser(ctx, "bob", "new")
+	require.NoError(t, err)
+
+	require.NoError(t, repo.Users.SetDisabled(ctx, bob, true))
+
+	_, err = repo.Authorization.GetUser(ctx, "bob", "new")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	active, err := repo.Authorization.IsActive(ctx, bob)
+	require.NoError(t, err)
+	require.False(t, active)
+
+	stats, err := repo.Users.GetStats(ctx, day)
+	require.NoError(t, err)
+	require.Equal(t, entity.StudioStats{
+		Users:           2,
+		DisabledUsers:   1,
+		Lists:           1,
+		Items:           1,
+		UpcomingItems:   1,
+		PendingBookings: 0,
+		WaitingClients:  0,
+		PendingOffers:   0,
+	}, stats)
+
+	require.NoError(t, repo.Users.TransferList(ctx, listID, bob))
+	require.ErrorIs(t, repo.Users.TransferList(ctx, listID+1, bob), apperrors.ErrNotFound)
+
+	_, err = repo.TimeslotList.GetByID(ctx, bob, listID)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.Users.DeleteUser(ctx, bob))
+	require.ErrorIs(t, repo.Users.DeleteUser(ctx, bob), apperrors.ErrNotFound)
+
+	stats, err = repo.Users.GetStats(ctx, day)
+	require.NoError(t, err)
+	require.Equal(t, 1, stats.Users)
+	require.Equal(t, 0, stats.Lists)
+	require.Equal(t, 0, stats.Items)
+}
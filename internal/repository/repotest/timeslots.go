@@ -0,0 +1,248 @@
+package repotest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"main.go/internal/entity"
+	apperrors "main.go/internal/errors"
+	"main.go/internal/repository"
+)
+
+func testAuthorization(t *testing.T, repo *repository.Repository) {
+	ctx := context.Background()
+
+	id := createUser(t, repo, "alice", "ff0000")
+
+	user, err := repo.Authorization.GetUser(ctx, "alice", "hash")
+	require.NoError(t, err)
+	require.Equal(t, id, user.ID)
+
+	_, err = repo.Authorization.GetUser(ctx, "alice", "wrong")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	active, err := repo.Authorization.IsActive(ctx, id)
+	require.NoError(t, err)
+	require.True(t, active)
+
+	active, err = repo.Authorization.IsActive(ctx, id+1)
+	require.NoError(t, err)
+	require.False(t, active)
+
+	_, err = repo.Authorization.CreateUser(ctx, entity.User{
+		ID:       0,
+		Name:     "Other",
+		Color:    "00ff00",
+		Username: "alice",
+		Password: "hash",
+	})
+	require.ErrorIs(t, err, apperrors.Conflict("username_taken", "", nil))
+
+	_, err = repo.Authorization.CreateUser(ctx, entity.User{
+		ID:       0,
+		Name:     "Other",
+		Color:    "ff0000",
+		Username: "other",
+		Password: "hash",
+	})
+	require.ErrorIs(t, err, apperrors.Conflict("color_taken", "", nil))
+}
+
+func testListOwnership(t *testing.T, repo *repository.Repository) {
+	ctx := context.Background()
+
+	alice := createUser(t, repo, "alice", "ff0000")
+	bob := createUser(t, repo, "bob", "00ff00")
+	listID := createList(t, repo, alice, "Week")
+
+	_, err := repo.TimeslotList.GetByID(ctx, bob, listID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	title := "Renamed"
+	input := entity.UpdateListInput{Title: &title, Description: nil}
+
+	require.ErrorIs(t, repo.TimeslotList.Update(ctx, bob, listID, input, 0), sql.ErrNoRows)
+	require.NoError(t, repo.TimeslotList.Delete(ctx, bob, listID))
+
+	list, err := repo.TimeslotList.GetByID(ctx, alice, listID)
+	require.NoError(t, err)
+	require.Equal(t, "Week", list.Title)
+	require.Equal(t, 1, list.Version)
+
+	require.NoError(t, repo.TimeslotList.Update(ctx, alice, listID, input, 1))
+	require.ErrorIs(t, repo.TimeslotList.Update(ctx, alice, listID, input, 1), sql.ErrNoRows)
+
+	list, err = repo.TimeslotList.GetByID(ctx, alice, listID)
+	require.NoError(t, err)
+	require.Equal(t, "Renamed", list.Title)
+	require.Equal(t, 2, list.Version)
+
+	itemID := createItem(t, repo, listID, "Session", day.Add(10*time.Hour))
+
+	require.NoError(t, repo.TimeslotList.Delete(ctx, alice, listID))
+
+	_, err = repo.TimeslotList.GetByID(ctx, alice, listID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = repo.TimeslotItem.GetByID(ctx, alice, itemID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func testListGetAll(t *testing.T, repo *repository.Repository) {
+	ctx := context.Background()
+
+	alice := createUser(t, repo, "alice", "ff0000")
+	bob := createUser(t, repo, "bob", "00ff00")
+
+	for _, title := range []string{"Monday", "Tuesday", "Monday evening"} {
+		createList(t, repo, alice, title)
+	}
+
+	createList(t, repo, bob, "Monday")
+
+	filter := entity.ListsFilter{PageParams: page(1), Title: "monday"}
+
+	lists, err := repo.TimeslotList.GetAll(ctx, alice, filter)
+	require.NoError(t, err)
+	require.Len(t, lists, 1)
+	require.Equal(t, "Monday", lists[0].Title)
+
+	filter.After.ID = lists[0].ID
+
+	lists, err = repo.TimeslotList.GetAll(ctx, alice, filter)
+	require.NoError(t, err)
+	require.Len(t, lists, 1)
+	require.Equal(t, "Monday evening", lists[0].Title)
+
+	lists, err = repo.TimeslotList.GetAll(ctx, alice, entity.ListsFilter{PageParams: page(10), Title: "50%"})
+	require.NoError(t, err)
+	require.Empty(t, lists)
+}
+
+func testItems(t *testing.T, repo *repository.Repository) {
+	ctx := context.Background()
+
+	alice := createUser(t, repo, "alice", "ff0000")
+	bob := createUser(t, repo, "bob", "00ff00")
+	aliceList := createList(t, repo, alice, "Alice")
+	bobList := createList(t, repo, bob, "Bob")
+
+	late := createItem(t, repo, aliceList, "Late", day.Add(15*time.Hour))
+	early := createItem(t, repo, aliceList, "Early", day.Add(9*time.Hour))
+	createItem(t, repo, bobList, "Bob's", day.Add(12*time.Hour))
+
+	_, err := repo.TimeslotItem.Create(ctx, bobList+100, newItem("Nowhere", day))
+	require.ErrorIs(t, err, apperrors.ErrValidation)
+
+	item, err := repo.TimeslotItem.GetByID(ctx, alice, early)
+	require.NoError(t, err)
+	require.Equal(t, "alice", item.Username)
+	require.Equal(t, "ff0000", item.Color)
+	require.Equal(t, aliceList, item.ListID)
+	require.True(t, item.Start.Equal(day.Add(9*time.Hour)))
+	require.Equal(t, 1, item.Version)
+
+	_, err = repo.TimeslotItem.GetByID(ctx, bob, early)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	items, err := repo.TimeslotItem.GetAll(ctx, bob, aliceList, itemsFilter("", entity.DefaultPageLimit))
+	require.NoError(t, err)
+	require.Empty(t, items)
+
+	done := true
+	input := entity.UpdateItemInput{
+		Title: nil, Description: nil, Start: nil, End: nil, Done: &done, Cancelled: nil,
+	}
+
+	require.ErrorIs(t, repo.TimeslotItem.Update(ctx, bob, late, input, 0), sql.ErrNoRows)
+	require.NoError(t, repo.TimeslotItem.Update(ctx, alice, late, input, 1))
+	require.ErrorIs(t, repo.TimeslotItem.Update(ctx, alice, late, input, 1), sql.ErrNoRows)
+
+	items, err = repo.TimeslotItem.GetAll(ctx, alice, aliceList, itemsFilter(entity.ItemStatusOpen, 10))
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, early, items[0].ID)
+	require.Equal(t, "alice", items[0].Username)
+	require.Empty(t, items[0].Color)
+
+	filter := itemsFilter("", 1)
+	filter.After = entity.Cursor{Start: items[0].Start, ID: items[0].ID}
+
+	items, err = repo.TimeslotItem.GetAll(ctx, alice, aliceList, filter)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, late, items[0].ID)
+	require.True(t, items[0].Done)
+	require.Equal(t, 2, items[0].Version)
+
+	byRange, err := repo.TimeslotItem.GetByRange(ctx, entity.ItemsByRange{
+		ItemsFilter: itemsFilter("", 2),
+		Start:       day,
+		End:         day.Add(24 * time.Hour),
+	})
+	require.NoError(t, err)
+	require.Len(t, byRange, 2)
+	require.Equal(t, "Early", byRange[0].Title)
+	require.Equal(t, "Bob's", byRange[1].Title)
+	require.Equal(t, "00ff00", byRange[1].Color)
+
+	count, err := repo.TimeslotItem.CountUpcoming(ctx, day, day.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	require.NoError(t, repo.TimeslotItem.Delete(ctx, bob, early))
+	require.NoError(t, repo.TimeslotItem.Delete(ctx, alice, early))
+
+	_, err = repo.TimeslotItem.GetByID(ctx, alice, early)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func testConcurrentAccess(t *testing.T, repo *repository.Repository) {
+	ctx := context.Background()
+
+	userID := createUser(t, repo, "alice", "ff0000")
+	listID := createList(t, repo, userID, "Week")
+
+	const workers = 8
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, workers)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			item := newItem(fmt.Sprintf("Session %d", i), day.Add(time.Duration(i)*time.Hour))
+
+			itemID, err := repo.TimeslotItem.Create(ctx, listID, item)
+			if err == nil {
+				_, err = repo.TimeslotItem.GetByID(ctx, userID, itemID)
+			}
+
+			if err == nil {
+				_, err = repo.TimeslotList.GetAll(ctx, userID, entity.ListsFilter{PageParams: page(1), Title: ""})
+			}
+
+			errs <- err
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	items, err := repo.TimeslotItem.GetAll(ctx, userID, listID, itemsFilter("", entity.DefaultPageLimit))
+	require.NoError(t, err)
+	require.Len(t, items, workers)
+}
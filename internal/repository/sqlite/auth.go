@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"main.go/internal/entity"
+	"main.go/internal/repository/sqltrace"
+)
+
+type AuthorizationSQLite struct {
+	db *sqltrace.DB
+}
+
+func NewAuthorizationSQLite(db *sqlx.DB, timeouts sqltrace.Timeouts) *AuthorizationSQLite {
+	return &AuthorizationSQLite{db: newTracedDB(db, timeouts)}
+}
+
+func (r *AuthorizationSQLite) CreateUser(ctx context.Context, user entity.User) (int, error) {
+	var userID int
+
+	query := fmt.Sprintf(
+		`
+			INSERT INTO %s (name, color, username, password_hash)
+			    VALUES (?1, ?2, ?3, ?4)
+			RETURNING
+			    id`,
+		UsersTable,
+	)
+	row := r.db.QueryRowContext(ctx, query, user.Name, user.Color, user.Username, user.Password)
+
+	if err := row.Scan(&userID); err != nil {
+		return 0, translateError(err, "user")
+	}
+
+	return userID, nil
+}
+
+func (r *AuthorizationSQLite) GetUser(ctx context.Context, username, password string) (entity.User, error) {
+	var user entity.User
+
+	query := fmt.Sprintf(`
+		SELECT
+		    id
+		FROM
+		    %s
+		WHERE
+		    username = ?1
+		    AND password_hash = ?2
+		    AND NOT disabled`, UsersTable)
+	err := r.db.GetContext(ctx, &user, query, username, password)
+
+	return user, translateError(err, "user")
+}
+
+// IsActive reports whether the user still exists and is not disabled.
+func (r *AuthorizationSQLite) IsActive(ctx context.Context, userID int) (bool, error) {
+	var active bool
+
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE id = ?1 AND NOT disabled)`, UsersTable)
+	err := r.db.GetContext(ctx, &active, query, userID)
+
+	return active, err
+}
@@ -0,0 +1,360 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"main.go/internal/entity"
+	"main.go/internal/repository/sqltrace"
+)
+
+type BookingSQLite struct {
+	db *sqltrace.DB
+}
+
+func NewBookingSQLite(db *sqlx.DB, timeouts sqltrace.Timeouts) *BookingSQLite {
+	return &BookingSQLite{db: newTracedDB(db, timeouts)}
+}
+
+func (r *BookingSQLite) GetArtists(ctx context.Context) ([]entity.Artist, error) {
+	var artists []entity.Artist
+
+	query := fmt.Sprintf(
+		`
+			SELECT
+			    id,
+			    name,
+			    color
+			FROM
+			    %s
+			WHERE
+			    NOT disabled
+			ORDER BY
+			    name,
+			    id`,
+		UsersTable,
+	)
+
+	if err := r.db.SelectContext(ctx, &artists, query); err != nil {
+		return nil, err
+	}
+
+	return artists, nil
+}
+
+// GetBusy returns the items of the artist's lists that overlap the range and
+// are not cancelled, ordered by start.
+func (r *BookingSQLite) GetBusy(
+	ctx context.Context,
+	artistID int,
+	start, end time.Time,
+) ([]entity.TimeRange, error) {
+	var busy []entity.TimeRange
+
+	query := fmt.Sprintf(
+		`
+			SELECT
+			    ti.beginning,
+			    ti.finish
+			FROM
+			    %s ti
+			    INNER JOIN %s li ON li.item_id = ti.id
+			    INNER JOIN %s ul ON ul.list_id = li.list_id
+			WHERE
+			    ul.user_id = ?1
+			    AND ti.beginning < ?3
+			    AND ti.finish > ?2
+			    AND NOT ti.cancelled
+			ORDER BY
+			    ti.beginning`,
+		TimeslotsItemsTable,
+		ListsItemsTable,
+		UsersListsTable,
+	)
+
+	if err := r.db.SelectContext(ctx, &busy, query, artistID, utc(start), utc(end)); err != nil {
+		return nil, err
+	}
+
+	return busy, nil
+}
+
+func (r *BookingSQLite) CreateRequest(ctx context.Context, request entity.BookingRequest) (int, error) {
+	var requestID int
+
+	query := fmt.Sprintf(
+		`
+			INSERT INTO %s (artist_id, client_name, client_email, client_phone, reference, beginning, finish, client_ip,
+			    created_at)
+			    VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9)
+			RETURNING
+			    id`,
+		BookingRequestsTable,
+	)
+	row := r.db.QueryRowContext(ctx,
+		query,
+		request.ArtistID,
+		request.ClientName,
+		request.ClientEmail,
+		request.ClientPhone,
+		request.Reference,
+		utc(request.Start),
+		utc(request.End),
+		request.ClientIP,
+		utc(time.Now()),
+	)
+
+	if err := row.Scan(&requestID); err != nil {
+		return 0, translateError(err, "booking_request")
+	}
+
+	return requestID, nil
+}
+
+func (r *BookingSQLite) CountRecentByIP(ctx context.Context, clientIP string, since time.Time) (int, error) {
+	var count int
+
+	query := fmt.Sprintf(
+		`SELECT count(*) FROM %s WHERE client_ip = ?1 AND created_at >= ?2`,
+		BookingRequestsTable,
+	)
+	err := r.db.GetContext(ctx, &count, query, clientIP, utc(since))
+
+	return count, err
+}
+
+func (r *BookingSQLite) CountPendingByEmail(ctx context.Context, clientEmail string) (int, error) {
+	var count int
+
+	query := fmt.Sprintf(
+		`SELECT count(*) FROM %s WHERE lower(client_email) = lower(?1) AND status = '%s'`,
+		BookingRequestsTable,
+		entity.BookingStatusRequested,
+	)
+	err := r.db.GetContext(ctx, &count, query, clientEmail)
+
+	return count, err
+}
+
+// GetRequests returns booking requests oldest first. Zero values in the
+// filter match everything.
+func (r *BookingSQLite) GetRequests(
+	ctx context.Context,
+	filter entity.BookingsFilter,
+) ([]entity.BookingRequest, error) {
+	var requests []entity.BookingRequest
+
+	query := fmt.Sprintf(
+		`
+			SELECT
+			    id,
+			    artist_id,
+			    client_name,
+			    client_email,
+			    client_phone,
+			    reference,
+			    beginning,
+			    finish,
+			    status,
+			    item_id,
+			    client_ip,
+			    created_at
+			FROM
+			    %s
+			WHERE (?1 = 0
+			    OR artist_id = ?1)
+			AND (?2 = ''
+			    OR status = ?2)
+			ORDER BY
+			    created_at,
+			    id`,
+		BookingRequestsTable,
+	)
+
+	if err := r.db.SelectContext(ctx, &requests, query, filter.ArtistID, filter.Status); err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+// ApproveRequest turns a requested booking into an item of the list and
+// returns the item id. It returns sql.ErrNoRows when the request is not
+// waiting for approval, entity.ErrBookingListForeign when the list is not
+// one of the artist's and entity.ErrBookingSlotTaken when the slot was
+// booked since the request came in. The transaction holds the write lock
+// from the start, which does what FOR UPDATE does in Postgres.
+func (r *BookingSQLite) ApproveRequest(ctx context.Context, requestID, listID int) (int, error) {
+	transaction, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var request entity.BookingRequest
+
+	getRequestQuery := fmt.Sprintf(
+		`
+			SELECT
+			    id,
+			    artist_id,
+			    client_name,
+			    reference,
+			    beginning,
+			    finish
+			FROM
+			    %s
+			WHERE
+			    id = ?1
+			    AND status = '%s'`,
+		BookingRequestsTable,
+		entity.BookingStatusRequested,
+	)
+
+	if err = transaction.GetContext(ctx, &request, getRequestQuery, requestID); err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return 0, err1
+		}
+
+		return 0, err
+	}
+
+	if err = r.checkApproval(ctx, transaction, request, listID); err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return 0, err1
+		}
+
+		return 0, err
+	}
+
+	var itemID int
+
+	createItemQuery := fmt.Sprintf(
+		`
+			INSERT INTO %s (title, description, beginning, finish)
+			    VALUES (?1, ?2, ?3, ?4)
+			RETURNING
+			    id`,
+		TimeslotsItemsTable,
+	)
+	row := transaction.QueryRowContext(ctx,
+		createItemQuery,
+		request.ClientName,
+		request.Reference,
+		utc(request.Start),
+		utc(request.End),
+	)
+
+	if err = row.Scan(&itemID); err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return 0, err1
+		}
+
+		return 0, err
+	}
+
+	createListsItemsQuery := fmt.Sprintf(
+		`
+			INSERT INTO %s (list_id, item_id)
+			    VALUES (?1, ?2)`,
+		ListsItemsTable,
+	)
+
+	if _, err = transaction.ExecContext(ctx, createListsItemsQuery, listID, itemID); err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return 0, err1
+		}
+
+		return 0, err
+	}
+
+	approveQuery := fmt.Sprintf(
+		`UPDATE %s SET status = '%s', item_id = ?2 WHERE id = ?1`,
+		BookingRequestsTable,
+		entity.BookingStatusApproved,
+	)
+
+	if _, err = transaction.ExecContext(ctx, approveQuery, requestID, itemID); err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return 0, err1
+		}
+
+		return 0, err
+	}
+
+	return itemID, transaction.Commit()
+}
+
+// checkApproval makes sure the request can still be booked into the list:
+// the list has to be the artist's and the slot must not overlap any of the
+// artist's items.
+func (r *BookingSQLite) checkApproval(
+	ctx context.Context,
+	transaction *sqltrace.Tx,
+	request entity.BookingRequest,
+	listID int,
+) error {
+	var owned bool
+
+	ownedQuery := fmt.Sprintf(
+		`SELECT EXISTS (SELECT 1 FROM %s WHERE user_id = ?1 AND list_id = ?2)`,
+		UsersListsTable,
+	)
+
+	if err := transaction.GetContext(ctx, &owned, ownedQuery, request.ArtistID, listID); err != nil {
+		return err
+	}
+
+	if !owned {
+		return entity.ErrBookingListForeign
+	}
+
+	var taken bool
+
+	takenQuery := fmt.Sprintf(
+		`
+			SELECT EXISTS (
+			    SELECT
+			        1
+			    FROM
+			        %s ti
+			        INNER JOIN %s li ON li.item_id = ti.id
+			        INNER JOIN %s ul ON ul.list_id = li.list_id
+			    WHERE
+			        ul.user_id = ?1
+			        AND ti.beginning < ?3
+			        AND ti.finish > ?2
+			        AND NOT ti.cancelled
+			)`,
+		TimeslotsItemsTable,
+		ListsItemsTable,
+		UsersListsTable,
+	)
+
+	err := transaction.GetContext(ctx, &taken, takenQuery, request.ArtistID, utc(request.Start), utc(request.End))
+	if err != nil {
+		return err
+	}
+
+	if taken {
+		return entity.ErrBookingSlotTaken
+	}
+
+	return nil
+}
+
+// RejectRequest returns sql.ErrNoRows when the request is not waiting for
+// approval.
+func (r *BookingSQLite) RejectRequest(ctx context.Context, requestID int) error {
+	var rejectedID int
+
+	query := fmt.Sprintf(
+		`UPDATE %s SET status = '%s' WHERE id = ?1 AND status = '%s' RETURNING id`,
+		BookingRequestsTable,
+		entity.BookingStatusRejected,
+		entity.BookingStatusRequested,
+	)
+
+	return r.db.GetContext(ctx, &rejectedID, query, requestID)
+}
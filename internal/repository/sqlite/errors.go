@@ -0,0 +1,64 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+	apperrors "main.go/internal/errors"
+)
+
+// fieldConflicts maps the table.column SQLite names in unique violations to
+// the errors the postgres package returns for the same constraints.
+//
+//nolint:gochecknoglobals // lookup table
+var fieldConflicts = map[string]apperrors.FieldError{
+	"users.username": {Field: "username", Code: "username_taken", Message: "username is already taken"},
+	"users.color":    {Field: "color", Code: "color_taken", Message: "color is already taken"},
+}
+
+// translateError turns driver errors about the resource into the typed
+// errors the postgres package returns for the same violations, keeping the
+// driver's message in the wrapped error only.
+func translateError(err error, resource string) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperrors.NotFound(resource+"_not_found", resource+" not found", err)
+	}
+
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return err
+	}
+
+	//nolint:exhaustive // other codes pass through
+	switch sqliteErr.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		if field, ok := fieldConflicts[violatedColumns(sqliteErr)]; ok {
+			conflict := apperrors.Conflict(field.Code, field.Message, err)
+			conflict.Details = []apperrors.FieldError{field}
+
+			return conflict
+		}
+
+		return apperrors.Conflict(resource+"_already_exists", resource+" already exists", err)
+	case sqlite3.ErrConstraintForeignKey:
+		return apperrors.Validation(resource+"_invalid_reference", resource+" refers to a missing record", err)
+	case sqlite3.ErrConstraintCheck, sqlite3.ErrConstraintNotNull:
+		return apperrors.Validation(resource+"_invalid", "invalid "+resource, err)
+	default:
+		return err
+	}
+}
+
+// violatedColumns returns the columns of a constraint violation, as in
+// "UNIQUE constraint failed: users.color".
+func violatedColumns(err sqlite3.Error) string {
+	_, columns, _ := strings.Cut(err.Error(), "constraint failed: ")
+
+	return columns
+}
@@ -0,0 +1,85 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+
+	"main.go/internal/entity"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`) //nolint:gochecknoglobals // stateless replacer
+
+// itemsFilterConditions renders the optional item filters and the keyset
+// condition as WHERE fragments numbered from argID on. LIKE ignores ASCII
+// case in SQLite, standing in for ILIKE.
+func itemsFilterConditions(
+	filter entity.ItemsFilter,
+	argID int,
+) ([]string, []interface{}, int) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+
+	if filter.Title != "" {
+		conditions = append(conditions, fmt.Sprintf(`ti.title LIKE '%%' || ?%d || '%%' ESCAPE '\'`, argID))
+		args = append(args, likeEscaper.Replace(filter.Title))
+		argID++
+	}
+
+	switch filter.Status {
+	case entity.ItemStatusDone:
+		conditions = append(conditions, "ti.done")
+	case entity.ItemStatusOpen:
+		conditions = append(conditions, "NOT ti.done AND NOT ti.cancelled")
+	case entity.ItemStatusCancelled:
+		conditions = append(conditions, "ti.cancelled")
+	}
+
+	if filter.Artist != "" {
+		conditions = append(conditions, fmt.Sprintf("u.username = ?%d", argID))
+		args = append(args, filter.Artist)
+		argID++
+	}
+
+	if !filter.After.IsZero() {
+		conditions = append(
+			conditions,
+			fmt.Sprintf("(ti.beginning, ti.id) > (?%d, ?%d)", argID, argID+1),
+		)
+		args = append(args, utc(filter.After.Start), filter.After.ID)
+		argID += 2
+	}
+
+	return conditions, args, argID
+}
+
+// listsFilterConditions is the TimeslotsList counterpart of
+// itemsFilterConditions; lists are paged by id alone.
+func listsFilterConditions(
+	filter entity.ListsFilter,
+	argID int,
+) ([]string, []interface{}, int) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+
+	if filter.Title != "" {
+		conditions = append(conditions, fmt.Sprintf(`tl.title LIKE '%%' || ?%d || '%%' ESCAPE '\'`, argID))
+		args = append(args, likeEscaper.Replace(filter.Title))
+		argID++
+	}
+
+	if !filter.After.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("tl.id > ?%d", argID))
+		args = append(args, filter.After.ID)
+		argID++
+	}
+
+	return conditions, args, argID
+}
+
+func andConditions(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+
+	return "AND " + strings.Join(conditions, "\n			    AND ")
+}
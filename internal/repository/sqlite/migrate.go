@@ -0,0 +1,22 @@
+package sqlite
+
+import (
+	"io/fs"
+
+	"github.com/jmoiron/sqlx"
+	"main.go/internal/repository/migrate"
+)
+
+// dialect takes no lock: SQLite serializes the steps' transactions and a
+// database file isn't shared by replicas.
+//
+//nolint:gochecknoglobals // constant queries
+var dialect = migrate.Dialect{
+	Lock:        "",
+	Unlock:      "",
+	TableExists: "SELECT count(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = ?1",
+}
+
+func NewMigrator(db *sqlx.DB, fsys fs.FS) (*migrate.Migrator, error) {
+	return migrate.New(db, fsys, dialect)
+}
@@ -0,0 +1,96 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"main.go/internal/entity"
+	"main.go/internal/repository/sqltrace"
+)
+
+type RateLimitSQLite struct {
+	db *sqltrace.DB
+}
+
+func NewRateLimitSQLite(db *sqlx.DB, timeouts sqltrace.Timeouts) *RateLimitSQLite {
+	return &RateLimitSQLite{db: newTracedDB(db, timeouts)}
+}
+
+// Take refills the key's bucket for the time since its last take and takes
+// one token if there is one. The refill is worked out here instead of in SQL
+// as in Postgres; the transaction holding the write lock keeps two takes from
+// handing out the same token.
+func (r *RateLimitSQLite) Take(
+	ctx context.Context,
+	key string,
+	limit entity.RateLimit,
+	now time.Time,
+) (entity.RateDecision, error) {
+	transaction, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return entity.RateDecision{}, err
+	}
+
+	var bucket struct {
+		Tokens    float64   `db:"tokens"`
+		UpdatedAt time.Time `db:"updated_at"`
+	}
+
+	getQuery := fmt.Sprintf(`SELECT tokens, updated_at FROM %s WHERE key = ?1`, RateLimitBucketsTable)
+
+	err = transaction.GetContext(ctx, &bucket, getQuery, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		bucket.Tokens, bucket.UpdatedAt, err = float64(limit.Burst), now, nil
+	}
+
+	if err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return entity.RateDecision{}, err1
+		}
+
+		return entity.RateDecision{}, err
+	}
+
+	elapsed := math.Max(now.Sub(bucket.UpdatedAt).Seconds(), 0)
+	tokens := math.Min(float64(limit.Burst), bucket.Tokens+elapsed*limit.RefillRate())
+
+	allowed := tokens >= 1
+	if allowed {
+		tokens--
+	}
+
+	saveQuery := fmt.Sprintf(
+		`
+			INSERT INTO %s (key, tokens, updated_at)
+			    VALUES (?1, ?2, ?3)
+			ON CONFLICT (key)
+			    DO UPDATE SET
+			        tokens = excluded.tokens,
+			        updated_at = excluded.updated_at`,
+		RateLimitBucketsTable,
+	)
+
+	if _, err = transaction.ExecContext(ctx, saveQuery, key, tokens, utc(now)); err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return entity.RateDecision{}, err1
+		}
+
+		return entity.RateDecision{}, err
+	}
+
+	return entity.NewRateDecision(allowed, tokens, limit), transaction.Commit()
+}
+
+// DeleteStale drops buckets untouched since before. Callers pick a time by
+// which every bucket would have refilled, so dropping them changes nothing.
+func (r *RateLimitSQLite) DeleteStale(ctx context.Context, before time.Time) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE updated_at < ?1`, RateLimitBucketsTable)
+	_, err := r.db.ExecContext(ctx, query, utc(before))
+
+	return err
+}
@@ -0,0 +1,258 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/jmoiron/sqlx"
+	"main.go/internal/entity"
+	"main.go/internal/repository/sqltrace"
+)
+
+const (
+	snippetWords   = 20
+	snippetLeading = 5
+
+	titleMatchRank = 1.0
+	textMatchRank  = 0.4
+)
+
+// SearchSQLite stands in for Postgres full text search, which the SQLite
+// driver is built without: LIKE narrows the candidates down to those holding
+// every word, which are then matched by word prefix, ranked and highlighted
+// here.
+type SearchSQLite struct {
+	db *sqltrace.DB
+}
+
+type searchDocument struct {
+	Kind  string `db:"kind"`
+	ID    int    `db:"id"`
+	Title string `db:"title"`
+	Text  string `db:"document"`
+}
+
+func NewSearchSQLite(db *sqlx.DB, timeouts sqltrace.Timeouts) *SearchSQLite {
+	return &SearchSQLite{db: newTracedDB(db, timeouts)}
+}
+
+// Search takes the prefix tsQuery the service builds ("word:* & word:*") and
+// matches it against the same documents as the Postgres search. A word found
+// in the title weighs more than one found in the rest of the text.
+func (r *SearchSQLite) Search(
+	ctx context.Context,
+	userID int,
+	tsQuery string,
+	limit int,
+) ([]entity.SearchResult, error) {
+	terms := tsQueryTerms(tsQuery)
+	if len(terms) == 0 {
+		return []entity.SearchResult{}, nil
+	}
+
+	conditions := make([]string, 0, len(terms))
+	args := []interface{}{userID}
+
+	for i, term := range terms {
+		conditions = append(conditions, fmt.Sprintf(`document LIKE '%%' || ?%d || '%%' ESCAPE '\'`, i+2))
+		args = append(args, likeEscaper.Replace(term))
+	}
+
+	query := fmt.Sprintf(
+		`
+			SELECT
+			    kind,
+			    id,
+			    title,
+			    document
+			FROM (
+			    SELECT
+			        '%s' AS kind,
+			        ti.id,
+			        ti.title,
+			        ti.title || ' ' || coalesce(ti.description, '') AS document
+			    FROM
+			        %s ti
+			        INNER JOIN %s li ON li.item_id = ti.id
+			        INNER JOIN %s ul ON ul.list_id = li.list_id
+			    WHERE
+			        ul.user_id = ?1
+			    UNION ALL
+			    SELECT
+			        '%s' AS kind,
+			        tl.id,
+			        tl.title,
+			        tl.title || ' ' || coalesce(tl.description, '') AS document
+			    FROM
+			        %s tl
+			        INNER JOIN %s ul ON ul.list_id = tl.id
+			    WHERE
+			        ul.user_id = ?1
+			    UNION ALL
+			    SELECT
+			        '%s' AS kind,
+			        br.id,
+			        br.client_name AS title,
+			        br.client_name || ' ' || br.client_email || ' ' || br.client_phone
+			            || ' ' || br.reference AS document
+			    FROM
+			        %s br
+			    WHERE
+			        br.artist_id = ?1
+			) documents
+			WHERE
+			    %s`,
+		entity.SearchKindItem,
+		TimeslotsItemsTable,
+		ListsItemsTable,
+		UsersListsTable,
+		entity.SearchKindList,
+		TimeslotListsTable,
+		UsersListsTable,
+		entity.SearchKindBooking,
+		BookingRequestsTable,
+		strings.Join(conditions, " AND "),
+	)
+
+	var documents []searchDocument
+	if err := r.db.SelectContext(ctx, &documents, query, args...); err != nil {
+		return nil, err
+	}
+
+	results := make([]entity.SearchResult, 0, len(documents))
+
+	for _, document := range documents {
+		rank, ok := searchRank(document, terms)
+		if !ok {
+			continue
+		}
+
+		results = append(results, entity.SearchResult{
+			Kind:    document.Kind,
+			ID:      document.ID,
+			Title:   document.Title,
+			Snippet: searchSnippet(document.Text, terms),
+			Rank:    rank,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+
+		if results[i].Kind != results[j].Kind {
+			return results[i].Kind < results[j].Kind
+		}
+
+		return results[i].ID < results[j].ID
+	})
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// tsQueryTerms undoes the service's prefix query into its words.
+func tsQueryTerms(tsQuery string) []string {
+	terms := make([]string, 0)
+
+	for _, term := range strings.Split(tsQuery, "&") {
+		term = strings.TrimSuffix(strings.TrimSpace(term), ":*")
+		if term != "" {
+			terms = append(terms, strings.ToLower(term))
+		}
+	}
+
+	return terms
+}
+
+// searchWords splits text the way the service splits the query.
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func hasPrefixWord(words []string, term string) bool {
+	for _, word := range words {
+		if strings.HasPrefix(word, term) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// searchRank averages the weight of every term over the document, and reports
+// false when a term only occurs inside a word rather than at its start.
+func searchRank(document searchDocument, terms []string) (float64, bool) {
+	titleWords := searchWords(document.Title)
+	textWords := searchWords(document.Text)
+
+	var rank float64
+
+	for _, term := range terms {
+		switch {
+		case hasPrefixWord(titleWords, term):
+			rank += titleMatchRank
+		case hasPrefixWord(textWords, term):
+			rank += textMatchRank
+		default:
+			return 0, false
+		}
+	}
+
+	return rank / float64(len(terms)), true
+}
+
+// searchSnippet cuts snippetWords words out of text starting just before the
+// first match, escaped for HTML, marking the matching words like the
+// Postgres store does.
+func searchSnippet(text string, terms []string) string {
+	fields := strings.Fields(text)
+	marked := make([]bool, len(fields))
+	first := -1
+
+	for i, field := range fields {
+		words := searchWords(field)
+
+		for _, term := range terms {
+			if hasPrefixWord(words, term) {
+				marked[i] = true
+			}
+		}
+
+		if marked[i] && first < 0 {
+			first = i
+		}
+	}
+
+	start := 0
+	if first > snippetLeading {
+		start = first - snippetLeading
+	}
+
+	end := start + snippetWords
+	if end > len(fields) {
+		end = len(fields)
+	}
+
+	snippet := make([]string, 0, end-start)
+
+	for i := start; i < end; i++ {
+		if marked[i] {
+			snippet = append(snippet, "<mark>"+html.EscapeString(fields[i])+"</mark>")
+		} else {
+			snippet = append(snippet, html.EscapeString(fields[i]))
+		}
+	}
+
+	return strings.Join(snippet, " ")
+}
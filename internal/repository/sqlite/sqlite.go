@@ -0,0 +1,75 @@
+// Package sqlite stores everything in a single SQLite file, for studios that
+// don't want to run Postgres. The queries mirror the postgres package; the
+// differences are commented where they happen.
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"main.go/internal/repository/sqltrace"
+)
+
+const (
+	UsersTable            = "users"
+	TimeslotListsTable    = "timeslots_lists"
+	UsersListsTable       = "users_lists"
+	TimeslotsItemsTable   = "timeslots_items"
+	ListsItemsTable       = "lists_items"
+	BookingRequestsTable  = "booking_requests"
+	WaitlistEntriesTable  = "waitlist_entries"
+	WaitlistOffersTable   = "waitlist_offers"
+	RateLimitBucketsTable = "rate_limit_buckets"
+	IdempotencyKeysTable  = "idempotency_keys"
+)
+
+type Config struct {
+	Path string `mapstructure:"path"`
+}
+
+// NewSQLiteDB opens the database file, creating it when missing. Foreign
+// keys are off in SQLite unless asked for, and transactions take the write
+// lock up front so that two of them can't deadlock upgrading to it.
+func NewSQLiteDB(cfg Config) (*sqlx.DB, error) {
+	dataBase, err := sqlx.Open(
+		"sqlite3",
+		cfg.Path+"?_foreign_keys=1&_busy_timeout=5000&_journal_mode=WAL&_txlock=immediate&_loc=UTC",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = dataBase.Ping(); err != nil {
+		return nil, err
+	}
+
+	return dataBase, nil
+}
+
+// newTracedDB is what the repositories run their statements through, each
+// bounded by timeouts.
+func newTracedDB(db *sqlx.DB, timeouts sqltrace.Timeouts) *sqltrace.DB {
+	return sqltrace.Wrap(db, semconv.DBSystemSqlite, timeouts)
+}
+
+// utc is applied to every time written or compared. The driver stores times
+// as text with their offset, which only sorts right when they share one.
+func utc(t time.Time) time.Time {
+	return t.UTC()
+}
+
+// checkUpdated turns an update that matched no rows into sql.ErrNoRows.
+func checkUpdated(result sql.Result) error {
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
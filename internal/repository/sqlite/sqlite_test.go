@@ -0,0 +1,61 @@
+package sqlite_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+	"main.go/internal/repository"
+	"main.go/internal/repository/repotest"
+	"main.go/internal/repository/sqlite"
+	"main.go/schema"
+)
+
+func openDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	db, err := sqlite.NewSQLiteDB(sqlite.Config{Path: filepath.Join(t.TempDir(), "test.db")})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db
+}
+
+func TestRepository(t *testing.T) {
+	repotest.RunAll(t, func(t *testing.T) *repository.Repository {
+		t.Helper()
+
+		db := openDB(t)
+
+		migrator, err := sqlite.NewMigrator(db, schema.SQLiteMigrations)
+		require.NoError(t, err)
+		require.NoError(t, migrator.Up(context.Background()))
+
+		return repository.NewSQLiteRepository(db, repository.QueryTimeouts{})
+	})
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+
+	migrator, err := sqlite.NewMigrator(db, schema.SQLiteMigrations)
+	require.NoError(t, err)
+
+	require.NoError(t, migrator.Up(ctx))
+
+	status, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	require.Equal(t, migrator.Latest(), status.Version)
+	require.False(t, status.Dirty)
+
+	require.NoError(t, migrator.To(ctx, 0))
+
+	var tables int
+	require.NoError(t, db.Get(&tables, "SELECT count(*) FROM sqlite_master WHERE name = 'users'"))
+	require.Zero(t, tables)
+
+	require.NoError(t, migrator.Up(ctx))
+}
@@ -0,0 +1,336 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"main.go/internal/entity"
+	"main.go/internal/repository/sqltrace"
+)
+
+type TimeslotItemSQLite struct {
+	db *sqltrace.DB
+	// getAllDB and getByRangeDB run the item searches, which scan more rows
+	// than the other reads and get timeouts of their own.
+	getAllDB     *sqltrace.DB
+	getByRangeDB *sqltrace.DB
+}
+
+func NewTimeslotItemSQLite(
+	db *sqlx.DB,
+	timeouts sqltrace.Timeouts,
+	getAllTimeout, getByRangeTimeout time.Duration,
+) *TimeslotItemSQLite {
+	traced := newTracedDB(db, timeouts)
+
+	return &TimeslotItemSQLite{
+		db:           traced,
+		getAllDB:     traced.WithReadTimeout(getAllTimeout),
+		getByRangeDB: traced.WithReadTimeout(getByRangeTimeout),
+	}
+}
+
+func (r *TimeslotItemSQLite) Create(ctx context.Context, listID int, item entity.TimeslotItem) (int, error) {
+	transaction, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, translateError(err, "item")
+	}
+
+	var itemID int
+	createItemQuery := fmt.Sprintf(
+		`
+			INSERT INTO %s (title, description, beginning, finish)
+			    VALUES (?1, ?2, ?3, ?4)
+			RETURNING
+			    id`,
+		TimeslotsItemsTable,
+	)
+	row := transaction.QueryRowContext(ctx, createItemQuery, item.Title, item.Description, utc(item.Start), utc(item.End))
+
+	if err = row.Scan(&itemID); err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return 0, err1
+		}
+
+		return 0, translateError(err, "item")
+	}
+
+	createListsItemsQuery := fmt.Sprintf(
+		`
+			INSERT INTO %s (list_id, item_id)
+			    VALUES (?1, ?2)`,
+		ListsItemsTable,
+	)
+
+	if _, err = transaction.ExecContext(ctx, createListsItemsQuery, listID, itemID); err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return 0, err1
+		}
+
+		return 0, translateError(err, "item")
+	}
+
+	return itemID, transaction.Commit()
+}
+
+func (r *TimeslotItemSQLite) GetAll(
+	ctx context.Context,
+	userID, listID int,
+	filter entity.ItemsFilter,
+) ([]entity.TimeslotItem, error) {
+	var items []entity.TimeslotItem
+
+	conditions, filterArgs, argID := itemsFilterConditions(filter, 3)
+	query := fmt.Sprintf(
+		`
+			SELECT
+			    ti.id,
+			    ti.title,
+			    ti.description,
+			    ti.beginning,
+			    ti.finish,
+			    ti.done,
+			    ti.cancelled,
+			    ti.version,
+			    u.username
+			FROM
+			    %s ti
+			    INNER JOIN %s li ON li.item_id = ti.id
+			    INNER JOIN %s ul ON ul.list_id = li.list_id
+			    INNER JOIN %s u ON u.id = ul.user_id
+			WHERE
+			    li.list_id = ?1
+			    AND ul.user_id = ?2
+			    %s
+			ORDER BY
+			    ti.beginning,
+			    ti.id
+			LIMIT ?%d`,
+		TimeslotsItemsTable,
+		ListsItemsTable,
+		UsersListsTable,
+		UsersTable,
+		andConditions(conditions),
+		argID,
+	)
+
+	args := append([]interface{}{listID, userID}, filterArgs...)
+	args = append(args, filter.Limit)
+
+	if err := r.getAllDB.SelectContext(ctx, &items, query, args...); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *TimeslotItemSQLite) GetByID(
+	ctx context.Context,
+	userID, itemID int,
+) (entity.TimeslotItem, error) {
+	var item entity.TimeslotItem
+
+	query := fmt.Sprintf(
+		`
+			SELECT
+			    ti.id,
+			    ti.title,
+			    ti.description,
+			    ti.beginning,
+			    ti.finish,
+			    ti.done,
+			    ti.cancelled,
+			    ti.version,
+			    u.username,
+			    u.color,
+			    li.list_id
+			FROM
+			    %s ti
+			    INNER JOIN %s li ON li.item_id = ti.id
+			    INNER JOIN %s ul ON ul.list_id = li.list_id
+			    INNER JOIN %s u ON u.id = ul.user_id
+			WHERE
+			    ti.id = ?1
+			    AND ul.user_id = ?2`,
+		TimeslotsItemsTable,
+		ListsItemsTable,
+		UsersListsTable,
+		UsersTable,
+	)
+	if err := r.db.GetContext(ctx, &item, query, itemID, userID); err != nil {
+		return item, translateError(err, "item")
+	}
+
+	return item, nil
+}
+
+// Update changes the item when its version still equals version, a zero
+// version skips the check. It returns a not found error wrapping sql.ErrNoRows
+// when no item was changed.
+func (r *TimeslotItemSQLite) Update(
+	ctx context.Context,
+	userID, itemID int,
+	input entity.UpdateItemInput,
+	version int,
+) error {
+	setValues := make([]string, 0)
+	args := make([]interface{}, 0)
+	argID := 1
+
+	refVal := reflect.ValueOf(&input).Elem()
+	refType := reflect.TypeOf(input)
+
+	for i := 0; i < refVal.NumField(); i++ {
+		field := refVal.Field(i)
+		if !field.IsNil() {
+			setValues = append(
+				setValues,
+				fmt.Sprintf("%s=?%d", refType.Field(i).Tag.Get("db"), argID),
+			)
+
+			value := field.Elem().Interface()
+			if t, ok := value.(time.Time); ok {
+				value = utc(t)
+			}
+
+			args = append(args, value)
+			argID++
+		}
+	}
+
+	setQuery := strings.Join(setValues, ", ")
+	query := fmt.Sprintf(
+		`
+			UPDATE
+			    %s AS ti
+			SET
+			    %s
+			FROM
+			    %s AS li,
+			    %s AS ul
+			WHERE
+			    ti.id = li.item_id
+			    AND li.list_id = ul.list_id
+			    AND ul.user_id = ?%d
+			    AND ti.id = ?%d
+			    AND (?%d = 0
+			        OR ti.version = ?%d)`,
+		TimeslotsItemsTable,
+		setQuery,
+		ListsItemsTable,
+		UsersListsTable,
+		argID,
+		argID+1,
+		argID+2,
+		argID+2,
+	)
+
+	args = append(args, userID, itemID, version)
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return translateError(err, "item")
+	}
+
+	return translateError(checkUpdated(result), "item")
+}
+
+func (r *TimeslotItemSQLite) Delete(ctx context.Context, userID, itemID int) error {
+	query := fmt.Sprintf(
+		`
+			DELETE FROM %s
+			WHERE id IN (
+			    SELECT
+			        li.item_id
+			    FROM
+			        %s li
+			        INNER JOIN %s ul ON ul.list_id = li.list_id
+			    WHERE
+			        ul.user_id = ?1
+			        AND li.item_id = ?2)`,
+		TimeslotsItemsTable,
+		ListsItemsTable,
+		UsersListsTable,
+	)
+	_, err := r.db.ExecContext(ctx, query, userID, itemID)
+
+	return err
+}
+
+func (r *TimeslotItemSQLite) GetByRange(
+	ctx context.Context,
+	input entity.ItemsByRange,
+) ([]entity.TimeslotItem, error) {
+	var items []entity.TimeslotItem
+
+	conditions, filterArgs, argID := itemsFilterConditions(input.ItemsFilter, 3)
+	query := fmt.Sprintf(
+		`
+			SELECT
+			    ti.id,
+			    ti.title,
+			    ti.description,
+			    ti.beginning,
+			    ti.finish,
+			    ti.done,
+			    ti.cancelled,
+			    ti.version,
+			    u.username,
+			    u.color
+			FROM
+			    %s ti
+			    INNER JOIN %s li ON li.item_id = ti.id
+			    INNER JOIN %s ul ON ul.list_id = li.list_id
+			    INNER JOIN %s u ON u.id = ul.user_id
+			WHERE
+			    ti.beginning >= ?1
+			    AND ti.finish <= ?2
+			    %s
+			ORDER BY
+			    ti.beginning,
+			    ti.id
+			LIMIT ?%d`,
+		TimeslotsItemsTable,
+		ListsItemsTable,
+		UsersListsTable,
+		UsersTable,
+		andConditions(conditions),
+		argID,
+	)
+
+	args := append([]interface{}{utc(input.Start), utc(input.End)}, filterArgs...)
+	args = append(args, input.Limit)
+
+	if err := r.getByRangeDB.SelectContext(ctx, &items, query, args...); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// CountUpcoming counts the items that are neither done nor cancelled and
+// start within [from, to).
+func (r *TimeslotItemSQLite) CountUpcoming(ctx context.Context, from, to time.Time) (int, error) {
+	var count int
+
+	query := fmt.Sprintf(
+		`
+			SELECT
+			    count(*)
+			FROM
+			    %s
+			WHERE
+			    beginning >= ?1
+			    AND beginning < ?2
+			    AND NOT done
+			    AND NOT cancelled`,
+		TimeslotsItemsTable,
+	)
+	err := r.db.GetContext(ctx, &count, query, utc(from), utc(to))
+
+	return count, err
+}
@@ -0,0 +1,206 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"main.go/internal/entity"
+	"main.go/internal/repository/sqltrace"
+)
+
+type TimeslotListSQLite struct {
+	db *sqltrace.DB
+}
+
+func NewTimeslotListSQLite(db *sqlx.DB, timeouts sqltrace.Timeouts) *TimeslotListSQLite {
+	return &TimeslotListSQLite{db: newTracedDB(db, timeouts)}
+}
+
+func (r *TimeslotListSQLite) Create(ctx context.Context, userID int, list entity.TimeslotsList) (int, error) {
+	transaction, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, translateError(err, "list")
+	}
+
+	var listID int
+
+	createListQuery := fmt.Sprintf(
+		`
+			INSERT INTO %s (title, description)
+			    VALUES (?1, ?2)
+			RETURNING
+			    id`,
+		TimeslotListsTable,
+	)
+	row := transaction.QueryRowContext(ctx, createListQuery, list.Title, list.Description)
+
+	if err = row.Scan(&listID); err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return 0, err1
+		}
+
+		return 0, translateError(err, "list")
+	}
+
+	createUsersListQuery := fmt.Sprintf(
+		`
+			INSERT INTO %s (user_id, list_id)
+			    VALUES (?1, ?2)`,
+		UsersListsTable,
+	)
+
+	if _, err = transaction.ExecContext(ctx, createUsersListQuery, userID, listID); err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return 0, err1
+		}
+
+		return 0, translateError(err, "list")
+	}
+
+	return listID, transaction.Commit()
+}
+
+func (r *TimeslotListSQLite) GetAll(
+	ctx context.Context,
+	userID int,
+	filter entity.ListsFilter,
+) ([]entity.TimeslotsList, error) {
+	var lists []entity.TimeslotsList
+
+	conditions, filterArgs, argID := listsFilterConditions(filter, 2)
+	query := fmt.Sprintf(
+		`
+			SELECT
+			    tl.id,
+			    tl.title,
+			    tl.description,
+			    tl.version
+			FROM
+			    %s tl
+			    INNER JOIN %s ul ON tl.id = ul.list_id
+			WHERE
+			    ul.user_id = ?1
+			    %s
+			ORDER BY
+			    tl.id
+			LIMIT ?%d`,
+		TimeslotListsTable,
+		UsersListsTable,
+		andConditions(conditions),
+		argID,
+	)
+
+	args := append([]interface{}{userID}, filterArgs...)
+	args = append(args, filter.Limit)
+	err := r.db.SelectContext(ctx, &lists, query, args...)
+
+	return lists, err
+}
+
+func (r *TimeslotListSQLite) GetByID(ctx context.Context, userID, listID int) (entity.TimeslotsList, error) {
+	var list entity.TimeslotsList
+
+	query := fmt.Sprintf(
+		`
+			SELECT
+			    tl.id,
+			    tl.title,
+			    tl.description,
+			    tl.version
+			FROM
+			    %s tl
+			    INNER JOIN %s ul ON tl.id = ul.list_id
+			WHERE
+			    ul.user_id = ?1
+			    AND ul.list_id = ?2`,
+		TimeslotListsTable,
+		UsersListsTable,
+	)
+	err := r.db.GetContext(ctx, &list, query, userID, listID)
+
+	return list, translateError(err, "list")
+}
+
+// Update changes the list when its version still equals version, a zero
+// version skips the check. It returns a not found error wrapping sql.ErrNoRows
+// when no list was changed.
+func (r *TimeslotListSQLite) Update(
+	ctx context.Context,
+	userID, listID int,
+	input entity.UpdateListInput,
+	version int,
+) error {
+	setValues := make([]string, 0)
+	args := make([]interface{}, 0)
+	argID := 1
+
+	if input.Title != nil {
+		setValues = append(setValues, fmt.Sprintf("title=?%d", argID))
+		args = append(args, *input.Title)
+		argID++
+	}
+
+	if input.Description != nil {
+		setValues = append(setValues, fmt.Sprintf("description=?%d", argID))
+		args = append(args, *input.Description)
+		argID++
+	}
+
+	setQuery := strings.Join(setValues, ", ")
+	query := fmt.Sprintf(
+		`
+			UPDATE
+			    %s AS tl
+			SET
+			    %s
+			FROM
+			    %s AS ul
+			WHERE
+			    tl.id = ul.list_id
+			    AND ul.list_id = ?%d
+			    AND ul.user_id = ?%d
+			    AND (?%d = 0
+			        OR tl.version = ?%d)`,
+		TimeslotListsTable,
+		setQuery,
+		UsersListsTable,
+		argID,
+		argID+1,
+		argID+2,
+		argID+2,
+	)
+
+	args = append(args, listID, userID, version)
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return translateError(err, "list")
+	}
+
+	return translateError(checkUpdated(result), "list")
+}
+
+// Delete removes the list when the user owns it. SQLite has no DELETE ...
+// USING, so ownership is checked in a subquery; a trigger takes the items
+// along.
+func (r *TimeslotListSQLite) Delete(ctx context.Context, userID, listID int) error {
+	query := fmt.Sprintf(
+		`
+			DELETE FROM %s
+			WHERE id IN (
+			    SELECT
+			        list_id
+			    FROM
+			        %s
+			    WHERE
+			        user_id = ?1
+			        AND list_id = ?2)`,
+		TimeslotListsTable,
+		UsersListsTable,
+	)
+	_, err := r.db.ExecContext(ctx, query, userID, listID)
+
+	return err
+}
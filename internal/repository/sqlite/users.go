@@ -0,0 +1,156 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"main.go/internal/entity"
+	"main.go/internal/repository/sqltrace"
+)
+
+// UsersSQLite backs the admin tools, which act on any user rather than on
+// behalf of one.
+type UsersSQLite struct {
+	db *sqltrace.DB
+}
+
+func NewUsersSQLite(db *sqlx.DB, timeouts sqltrace.Timeouts) *UsersSQLite {
+	return &UsersSQLite{db: newTracedDB(db, timeouts)}
+}
+
+func (r *UsersSQLite) GetUsers(ctx context.Context) ([]entity.UserInfo, error) {
+	var users []entity.UserInfo
+
+	query := fmt.Sprintf(
+		`
+			SELECT
+			    u.id,
+			    u.name,
+			    u.color,
+			    u.username,
+			    u.disabled,
+			    count(ul.id) AS lists
+			FROM
+			    %s u
+			    LEFT JOIN %s ul ON ul.user_id = u.id
+			GROUP BY
+			    u.id
+			ORDER BY
+			    u.id`,
+		UsersTable,
+		UsersListsTable,
+	)
+
+	if err := r.db.SelectContext(ctx, &users, query); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *UsersSQLite) SetDisabled(ctx context.Context, userID int, disabled bool) error {
+	return r.setColumn(ctx, userID, "disabled", disabled)
+}
+
+func (r *UsersSQLite) SetPassword(ctx context.Context, userID int, passwordHash string) error {
+	return r.setColumn(ctx, userID, "password_hash", passwordHash)
+}
+
+func (r *UsersSQLite) SetColor(ctx context.Context, userID int, color string) error {
+	return r.setColumn(ctx, userID, "color", color)
+}
+
+func (r *UsersSQLite) setColumn(ctx context.Context, userID int, column string, value any) error {
+	query := fmt.Sprintf(`UPDATE %s SET %s = ?1 WHERE id = ?2`, UsersTable, column)
+
+	result, err := r.db.ExecContext(ctx, query, value, userID)
+	if err != nil {
+		return translateError(err, "user")
+	}
+
+	return translateError(checkUpdated(result), "user")
+}
+
+// DeleteUser removes the user together with their lists and the items in
+// them, which would otherwise be left behind unreachable. The lists' delete
+// trigger takes the items along.
+func (r *UsersSQLite) DeleteUser(ctx context.Context, userID int) error {
+	transaction, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	deleteListsQuery := fmt.Sprintf(
+		`DELETE FROM %s WHERE id IN (SELECT list_id FROM %s WHERE user_id = ?1)`,
+		TimeslotListsTable,
+		UsersListsTable,
+	)
+
+	if _, err = transaction.ExecContext(ctx, deleteListsQuery, userID); err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return err1
+		}
+
+		return err
+	}
+
+	deleteUserQuery := fmt.Sprintf(`DELETE FROM %s WHERE id = ?1`, UsersTable)
+
+	result, err := transaction.ExecContext(ctx, deleteUserQuery, userID)
+	if err == nil {
+		err = checkUpdated(result)
+	}
+
+	if err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return err1
+		}
+
+		return translateError(err, "user")
+	}
+
+	return transaction.Commit()
+}
+
+// TransferList hands the list over to another user.
+func (r *UsersSQLite) TransferList(ctx context.Context, listID, userID int) error {
+	query := fmt.Sprintf(`UPDATE %s SET user_id = ?1 WHERE list_id = ?2`, UsersListsTable)
+
+	result, err := r.db.ExecContext(ctx, query, userID, listID)
+	if err != nil {
+		return translateError(err, "user")
+	}
+
+	return translateError(checkUpdated(result), "list")
+}
+
+func (r *UsersSQLite) GetStats(ctx context.Context, now time.Time) (entity.StudioStats, error) {
+	var stats entity.StudioStats
+
+	query := fmt.Sprintf(
+		`
+			SELECT
+			    (SELECT count(*) FROM %[1]s) AS users,
+			    (SELECT count(*) FROM %[1]s WHERE disabled) AS disabled_users,
+			    (SELECT count(*) FROM %[2]s) AS lists,
+			    (SELECT count(*) FROM %[3]s) AS items,
+			    (SELECT count(*) FROM %[3]s WHERE beginning >= ?1 AND NOT done AND NOT cancelled) AS upcoming_items,
+			    (SELECT count(*) FROM %[4]s WHERE status = ?2) AS pending_bookings,
+			    (SELECT count(*) FROM %[5]s WHERE status = ?3) AS waiting_clients,
+			    (SELECT count(*) FROM %[6]s WHERE status = ?4) AS pending_offers`,
+		UsersTable,
+		TimeslotListsTable,
+		TimeslotsItemsTable,
+		BookingRequestsTable,
+		WaitlistEntriesTable,
+		WaitlistOffersTable,
+	)
+	err := r.db.GetContext(
+		ctx, &stats, query,
+		utc(now), entity.BookingStatusRequested, entity.WaitlistStatusWaiting, entity.OfferStatusPending,
+	)
+
+	return stats, err
+}
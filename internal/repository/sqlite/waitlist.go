@@ -0,0 +1,454 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"main.go/internal/entity"
+	"main.go/internal/repository/sqltrace"
+)
+
+const offerColumns = `id, entry_id, list_id, slot_start, slot_end, beginning, finish, status, expires_at`
+
+type WaitlistSQLite struct {
+	db *sqltrace.DB
+}
+
+func NewWaitlistSQLite(db *sqlx.DB, timeouts sqltrace.Timeouts) *WaitlistSQLite {
+	return &WaitlistSQLite{db: newTracedDB(db, timeouts)}
+}
+
+func (r *WaitlistSQLite) CreateEntry(ctx context.Context, entry entity.WaitlistEntry) (int, error) {
+	var entryID int
+
+	query := fmt.Sprintf(
+		`
+			INSERT INTO %s (client_name, client_contact, artist_id, window_start, window_end, duration_minutes,
+			    created_at)
+			    VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7)
+			RETURNING
+			    id`,
+		WaitlistEntriesTable,
+	)
+	row := r.db.QueryRowContext(ctx,
+		query,
+		entry.ClientName,
+		entry.ClientContact,
+		entry.ArtistID,
+		utc(entry.WindowStart),
+		utc(entry.WindowEnd),
+		entry.DurationMinutes,
+		utc(time.Now()),
+	)
+
+	if err := row.Scan(&entryID); err != nil {
+		return 0, translateError(err, "waitlist_entry")
+	}
+
+	return entryID, nil
+}
+
+// GetEntries returns the entries of one artist, or of everyone when artistID
+// is zero, in the order they will be offered slots.
+func (r *WaitlistSQLite) GetEntries(ctx context.Context, artistID int) ([]entity.WaitlistEntry, error) {
+	var entries []entity.WaitlistEntry
+
+	query := fmt.Sprintf(
+		`
+			SELECT
+			    id,
+			    client_name,
+			    client_contact,
+			    artist_id,
+			    window_start,
+			    window_end,
+			    duration_minutes,
+			    status,
+			    created_at
+			FROM
+			    %s
+			WHERE
+			    ?1 = 0
+			    OR artist_id = ?1
+			ORDER BY
+			    created_at,
+			    id`,
+		WaitlistEntriesTable,
+	)
+
+	if err := r.db.SelectContext(ctx, &entries, query, artistID); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *WaitlistSQLite) DeleteEntry(ctx context.Context, entryID int) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE id = ?1`, WaitlistEntriesTable)
+	_, err := r.db.ExecContext(ctx, query, entryID)
+
+	return err
+}
+
+// CreateOffer picks the oldest waiting entry of an artist owning the slot's
+// list whose window and duration fit into the slot from its opening on and
+// has not been offered this slot before, marks it offered and holds the slot
+// for it. It returns sql.ErrNoRows when nobody on the waitlist fits. SQLite
+// lacks interval arithmetic, so the fit is checked here rather than in the
+// query.
+func (r *WaitlistSQLite) CreateOffer(
+	ctx context.Context,
+	slot entity.FreedSlot,
+	hold time.Duration,
+) (entity.WaitlistOffer, error) {
+	transaction, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return entity.WaitlistOffer{}, err
+	}
+
+	var candidates []entity.WaitlistEntry
+
+	candidatesQuery := fmt.Sprintf(
+		`
+			SELECT
+			    we.id,
+			    we.window_start,
+			    we.window_end,
+			    we.duration_minutes
+			FROM
+			    %[1]s we
+			WHERE
+			    we.status = '%[4]s'
+			    AND we.artist_id IN (
+			        SELECT
+			            user_id
+			        FROM
+			            %[3]s
+			        WHERE
+			            list_id = ?1)
+			    AND NOT EXISTS (
+			        SELECT
+			            1
+			        FROM
+			            %[2]s wo
+			        WHERE
+			            wo.entry_id = we.id
+			            AND wo.list_id = ?1
+			            AND wo.slot_start = ?2
+			            AND wo.slot_end = ?3)
+			ORDER BY
+			    we.created_at,
+			    we.id`,
+		WaitlistEntriesTable,
+		WaitlistOffersTable,
+		UsersListsTable,
+		entity.WaitlistStatusWaiting,
+	)
+
+	err = transaction.SelectContext(ctx, &candidates, candidatesQuery, slot.ListID, utc(slot.Start), utc(slot.End))
+	if err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return entity.WaitlistOffer{}, err1
+		}
+
+		return entity.WaitlistOffer{}, err
+	}
+
+	entry, start, ok := fittingEntry(candidates, slot)
+	if !ok {
+		if err = transaction.Rollback(); err != nil {
+			return entity.WaitlistOffer{}, err
+		}
+
+		return entity.WaitlistOffer{}, sql.ErrNoRows
+	}
+
+	offerQuery := fmt.Sprintf(`UPDATE %s SET status = '%s' WHERE id = ?1`,
+		WaitlistEntriesTable, entity.WaitlistStatusOffered)
+
+	if _, err = transaction.ExecContext(ctx, offerQuery, entry.ID); err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return entity.WaitlistOffer{}, err1
+		}
+
+		return entity.WaitlistOffer{}, err
+	}
+
+	var offer entity.WaitlistOffer
+
+	now := time.Now()
+	createOfferQuery := fmt.Sprintf(
+		`
+			INSERT INTO %s (entry_id, list_id, slot_start, slot_end, beginning, finish, expires_at, created_at)
+			    VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8)
+			RETURNING
+			    %s`,
+		WaitlistOffersTable,
+		offerColumns,
+	)
+
+	err = transaction.GetContext(ctx, &offer, createOfferQuery,
+		entry.ID,
+		slot.ListID,
+		utc(slot.Start),
+		utc(slot.End),
+		utc(start),
+		utc(start.Add(time.Duration(entry.DurationMinutes)*time.Minute)),
+		utc(now.Add(hold)),
+		utc(now),
+	)
+	if err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return entity.WaitlistOffer{}, err1
+		}
+
+		return entity.WaitlistOffer{}, err
+	}
+
+	return offer, transaction.Commit()
+}
+
+// fittingEntry returns the first candidate whose duration fits into both the
+// slot and its own window, and where its appointment would start.
+func fittingEntry(candidates []entity.WaitlistEntry, slot entity.FreedSlot) (entity.WaitlistEntry, time.Time, bool) {
+	for _, entry := range candidates {
+		start, end := entry.WindowStart, entry.WindowEnd
+		if slot.Opening().After(start) {
+			start = slot.Opening()
+		}
+
+		if slot.End.Before(end) {
+			end = slot.End
+		}
+
+		if !start.Add(time.Duration(entry.DurationMinutes) * time.Minute).After(end) {
+			return entry, start, true
+		}
+	}
+
+	return entity.WaitlistEntry{}, time.Time{}, false
+}
+
+// GetOffers returns the pending offers of one artist, or of everyone when
+// artistID is zero.
+func (r *WaitlistSQLite) GetOffers(ctx context.Context, artistID int) ([]entity.WaitlistOffer, error) {
+	var offers []entity.WaitlistOffer
+
+	query := fmt.Sprintf(
+		`
+			SELECT
+			    wo.id,
+			    wo.entry_id,
+			    wo.list_id,
+			    we.client_name,
+			    wo.slot_start,
+			    wo.slot_end,
+			    wo.beginning,
+			    wo.finish,
+			    wo.status,
+			    wo.expires_at
+			FROM
+			    %s wo
+			    INNER JOIN %s we ON we.id = wo.entry_id
+			WHERE
+			    wo.status = '%s'
+			    AND (?1 = 0
+			        OR we.artist_id = ?1)
+			ORDER BY
+			    wo.expires_at,
+			    wo.id`,
+		WaitlistOffersTable,
+		WaitlistEntriesTable,
+		entity.OfferStatusPending,
+	)
+
+	if err := r.db.SelectContext(ctx, &offers, query, artistID); err != nil {
+		return nil, err
+	}
+
+	return offers, nil
+}
+
+// AcceptOffer books a pending, unexpired offer as a new item in the offer's
+// list and returns the item id. It returns sql.ErrNoRows when the offer is
+// gone, already answered or expired.
+func (r *WaitlistSQLite) AcceptOffer(ctx context.Context, offerID int) (int, error) {
+	transaction, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var accepted struct {
+		EntryID int       `db:"entry_id"`
+		ListID  int       `db:"list_id"`
+		Start   time.Time `db:"beginning"`
+		End     time.Time `db:"finish"`
+	}
+
+	acceptQuery := fmt.Sprintf(
+		`
+			UPDATE
+			    %s
+			SET
+			    status = '%s'
+			WHERE
+			    id = ?1
+			    AND status = '%s'
+			    AND expires_at > ?2
+			RETURNING
+			    entry_id,
+			    list_id,
+			    beginning,
+			    finish`,
+		WaitlistOffersTable,
+		entity.OfferStatusAccepted,
+		entity.OfferStatusPending,
+	)
+
+	if err = transaction.GetContext(ctx, &accepted, acceptQuery, offerID, utc(time.Now())); err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return 0, err1
+		}
+
+		return 0, err
+	}
+
+	// RETURNING can't reach the entry in SQLite, so the client is read and
+	// booked in one go.
+	var client struct {
+		Name    string `db:"client_name"`
+		Contact string `db:"client_contact"`
+	}
+
+	bookEntryQuery := fmt.Sprintf(
+		`UPDATE %s SET status = '%s' WHERE id = ?1 RETURNING client_name, client_contact`,
+		WaitlistEntriesTable,
+		entity.WaitlistStatusBooked,
+	)
+
+	if err = transaction.GetContext(ctx, &client, bookEntryQuery, accepted.EntryID); err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return 0, err1
+		}
+
+		return 0, err
+	}
+
+	var itemID int
+
+	createItemQuery := fmt.Sprintf(
+		`
+			INSERT INTO %s (title, description, beginning, finish)
+			    VALUES (?1, ?2, ?3, ?4)
+			RETURNING
+			    id`,
+		TimeslotsItemsTable,
+	)
+	row := transaction.QueryRowContext(ctx,
+		createItemQuery,
+		client.Name,
+		client.Contact,
+		utc(accepted.Start),
+		utc(accepted.End),
+	)
+
+	if err = row.Scan(&itemID); err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return 0, err1
+		}
+
+		return 0, err
+	}
+
+	createListsItemsQuery := fmt.Sprintf(
+		`
+			INSERT INTO %s (list_id, item_id)
+			    VALUES (?1, ?2)`,
+		ListsItemsTable,
+	)
+
+	if _, err = transaction.ExecContext(ctx, createListsItemsQuery, accepted.ListID, itemID); err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return 0, err1
+		}
+
+		return 0, err
+	}
+
+	return itemID, transaction.Commit()
+}
+
+// DeclineOffer closes a pending offer and puts its entry back on the
+// waitlist. It returns sql.ErrNoRows when the offer is not pending.
+func (r *WaitlistSQLite) DeclineOffer(ctx context.Context, offerID int) (entity.WaitlistOffer, error) {
+	query := fmt.Sprintf(
+		`UPDATE %s SET status = '%s' WHERE id = ?1 AND status = '%s' RETURNING %s`,
+		WaitlistOffersTable,
+		entity.OfferStatusDeclined,
+		entity.OfferStatusPending,
+		offerColumns,
+	)
+
+	offers, err := r.closeOffers(ctx, query, offerID)
+	if err != nil {
+		return entity.WaitlistOffer{}, err
+	}
+
+	if len(offers) == 0 {
+		return entity.WaitlistOffer{}, sql.ErrNoRows
+	}
+
+	return offers[0], nil
+}
+
+// ExpireOffers closes every pending offer whose hold ran out, puts the
+// entries back on the waitlist and returns the expired offers.
+func (r *WaitlistSQLite) ExpireOffers(ctx context.Context) ([]entity.WaitlistOffer, error) {
+	query := fmt.Sprintf(
+		`UPDATE %s SET status = '%s' WHERE status = '%s' AND expires_at <= ?1 RETURNING %s`,
+		WaitlistOffersTable,
+		entity.OfferStatusExpired,
+		entity.OfferStatusPending,
+		offerColumns,
+	)
+
+	return r.closeOffers(ctx, query, utc(time.Now()))
+}
+
+// closeOffers runs the UPDATE ... RETURNING query closing offers and puts
+// their entries back on the waitlist, which Postgres does in one statement
+// with data-modifying CTEs.
+func (r *WaitlistSQLite) closeOffers(ctx context.Context, query string, args ...any) ([]entity.WaitlistOffer, error) {
+	transaction, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var offers []entity.WaitlistOffer
+
+	if err = transaction.SelectContext(ctx, &offers, query, args...); err != nil {
+		if err1 := transaction.Rollback(); err1 != nil {
+			return nil, err1
+		}
+
+		return nil, err
+	}
+
+	resetQuery := fmt.Sprintf(`UPDATE %s SET status = '%s' WHERE id = ?1`,
+		WaitlistEntriesTable, entity.WaitlistStatusWaiting)
+
+	for _, offer := range offers {
+		if _, err = transaction.ExecContext(ctx, resetQuery, offer.EntryID); err != nil {
+			if err1 := transaction.Rollback(); err1 != nil {
+				return nil, err1
+			}
+
+			return nil, err
+		}
+	}
+
+	return offers, transaction.Commit()
+}
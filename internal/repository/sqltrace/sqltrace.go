@@ -0,0 +1,274 @@
+// Package sqltrace wraps sqlx so every statement the repositories run gets a
+// client span carrying its SQL and a deadline.
+package sqltrace
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	apperrors "main.go/internal/errors"
+)
+
+var tracer = otel.Tracer("main.go/internal/repository/sqltrace") //nolint:gochecknoglobals // package tracer
+
+// DB is what the repositories run their statements through. Every
+// statement gets a client span carrying its SQL, so a slow call can be
+// pinned on a query, and is cancelled once it runs longer than its timeout.
+type DB struct {
+	db       *sqlx.DB
+	system   attribute.KeyValue
+	timeouts Timeouts
+}
+
+// Timeouts bound the statements of a DB, zero meaning no limit. Write
+// applies to INSERT, UPDATE and DELETE statements, WITH queries running one
+// of them included, and to every statement of a transaction, Read to the
+// others.
+type Timeouts struct {
+	Read  time.Duration
+	Write time.Duration
+}
+
+// of is the timeout of query when run outside a transaction.
+func (t Timeouts) of(query string) time.Duration {
+	if writes(query) {
+		return t.Write
+	}
+
+	return t.Read
+}
+
+// writes tells whether query changes data. A WITH query does when any of
+// its parts is an INSERT, UPDATE or DELETE; a SELECT ... FOR UPDATE in one
+// counts too, as it locks the rows for a write.
+func writes(query string) bool {
+	switch sqlOperation(query) {
+	case "INSERT", "UPDATE", "DELETE":
+		return true
+	case "WITH":
+		words := strings.FieldsFunc(query, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+		})
+
+		for _, word := range words {
+			switch strings.ToUpper(word) {
+			case "INSERT", "UPDATE", "DELETE":
+				return true
+			}
+		}
+
+		return false
+	default:
+		return false
+	}
+}
+
+// Wrap traces the statements run on db, tagging the spans with system, such
+// as semconv.DBSystemPostgreSQL, and bounds each of them by timeouts.
+func Wrap(db *sqlx.DB, system attribute.KeyValue, timeouts Timeouts) *DB {
+	return &DB{db: db, system: system, timeouts: timeouts}
+}
+
+// WithReadTimeout is t with reads bounded by timeout instead, for the
+// operations configured apart from the rest of their repository.
+func (t *DB) WithReadTimeout(timeout time.Duration) *DB {
+	timeouts := t.timeouts
+	timeouts.Read = timeout
+
+	return &DB{db: t.db, system: t.system, timeouts: timeouts}
+}
+
+func (t *DB) GetContext(ctx context.Context, dest any, query string, args ...any) error {
+	statementCtx, cancel := withTimeout(ctx, t.timeouts.of(query))
+	defer cancel()
+
+	statementCtx, span := startSpan(statementCtx, t.system, query)
+	err := checkTimeout(ctx, statementCtx, t.db.GetContext(statementCtx, dest, query, args...))
+	endSpan(span, err)
+
+	return err
+}
+
+func (t *DB) SelectContext(ctx context.Context, dest any, query string, args ...any) error {
+	statementCtx, cancel := withTimeout(ctx, t.timeouts.of(query))
+	defer cancel()
+
+	statementCtx, span := startSpan(statementCtx, t.system, query)
+	err := checkTimeout(ctx, statementCtx, t.db.SelectContext(statementCtx, dest, query, args...))
+	endSpan(span, err)
+
+	return err
+}
+
+func (t *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	statementCtx, cancel := withTimeout(ctx, t.timeouts.of(query))
+	defer cancel()
+
+	statementCtx, span := startSpan(statementCtx, t.system, query)
+	result, err := t.db.ExecContext(statementCtx, query, args...)
+	err = checkTimeout(ctx, statementCtx, err)
+	endSpan(span, err)
+
+	return result, err
+}
+
+// QueryRowContext ends the span once the query has run. Errors that only
+// show up on Scan, such as sql.ErrNoRows, are not recorded.
+func (t *DB) QueryRowContext(ctx context.Context, query string, args ...any) *Row {
+	statementCtx, cancel := withTimeout(ctx, t.timeouts.of(query))
+
+	statementCtx, span := startSpan(statementCtx, t.system, query)
+	row := t.db.QueryRowContext(statementCtx, query, args...)
+	endSpan(span, checkTimeout(ctx, statementCtx, row.Err()))
+
+	return &Row{row: row, parent: ctx, ctx: statementCtx, cancel: cancel}
+}
+
+// BeginTxx leaves the transaction itself unbounded; each of its statements
+// gets the write timeout.
+func (t *DB) BeginTxx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	transaction, err := t.db.BeginTxx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tx{tx: transaction, system: t.system, timeout: t.timeouts.Write}, nil
+}
+
+// Tx is DB for the statements of a transaction.
+type Tx struct {
+	tx      *sqlx.Tx
+	system  attribute.KeyValue
+	timeout time.Duration
+}
+
+func (t *Tx) GetContext(ctx context.Context, dest any, query string, args ...any) error {
+	statementCtx, cancel := withTimeout(ctx, t.timeout)
+	defer cancel()
+
+	statementCtx, span := startSpan(statementCtx, t.system, query)
+	err := checkTimeout(ctx, statementCtx, t.tx.GetContext(statementCtx, dest, query, args...))
+	endSpan(span, err)
+
+	return err
+}
+
+func (t *Tx) SelectContext(ctx context.Context, dest any, query string, args ...any) error {
+	statementCtx, cancel := withTimeout(ctx, t.timeout)
+	defer cancel()
+
+	statementCtx, span := startSpan(statementCtx, t.system, query)
+	err := checkTimeout(ctx, statementCtx, t.tx.SelectContext(statementCtx, dest, query, args...))
+	endSpan(span, err)
+
+	return err
+}
+
+func (t *Tx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	statementCtx, cancel := withTimeout(ctx, t.timeout)
+	defer cancel()
+
+	statementCtx, span := startSpan(statementCtx, t.system, query)
+	result, err := t.tx.ExecContext(statementCtx, query, args...)
+	err = checkTimeout(ctx, statementCtx, err)
+	endSpan(span, err)
+
+	return result, err
+}
+
+func (t *Tx) QueryRowContext(ctx context.Context, query string, args ...any) *Row {
+	statementCtx, cancel := withTimeout(ctx, t.timeout)
+
+	statementCtx, span := startSpan(statementCtx, t.system, query)
+	row := t.tx.QueryRowContext(statementCtx, query, args...)
+	endSpan(span, checkTimeout(ctx, statementCtx, row.Err()))
+
+	return &Row{row: row, parent: ctx, ctx: statementCtx, cancel: cancel}
+}
+
+func (t *Tx) Commit() error {
+	return t.tx.Commit()
+}
+
+func (t *Tx) Rollback() error {
+	return t.tx.Rollback()
+}
+
+// Row is sql.Row holding on to the statement's deadline until Scan, since
+// the row is read from the connection only then.
+type Row struct {
+	row    *sql.Row
+	parent context.Context //nolint:containedctx // released by Scan
+	ctx    context.Context //nolint:containedctx // released by Scan
+	cancel context.CancelFunc
+}
+
+func (r *Row) Scan(dest ...any) error {
+	defer r.cancel()
+
+	return checkTimeout(r.parent, r.ctx, r.row.Scan(dest...))
+}
+
+// withTimeout bounds ctx by timeout, unless it is zero.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout == 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// checkTimeout turns the error of a statement cancelled by its own timeout
+// into a typed error. The driver reports those in its own words, so it is
+// told from the caller going away by which context ended.
+func checkTimeout(parent, statementCtx context.Context, err error) error {
+	if err == nil || parent.Err() != nil || !errors.Is(statementCtx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+
+	return apperrors.Timeout("query_timeout", "the database did not answer in time", err)
+}
+
+func startSpan(ctx context.Context, system attribute.KeyValue, query string) (context.Context, trace.Span) {
+	operation := sqlOperation(query)
+
+	return tracer.Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			system,
+			semconv.DBOperation(operation),
+			semconv.DBStatement(query),
+		),
+	)
+}
+
+// endSpan marks the span as failed unless the query just found nothing,
+// which the repositories treat as an answer rather than an error.
+func endSpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// sqlOperation is the first keyword of query, such as SELECT or INSERT.
+func sqlOperation(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return "SQL"
+	}
+
+	return strings.ToUpper(fields[0])
+}
@@ -0,0 +1,194 @@
+package sqltrace_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	apperrors "main.go/internal/errors"
+	"main.go/internal/repository/sqltrace"
+)
+
+func TestDB_Timeout(t *testing.T) {
+	testTable := []struct {
+		name      string
+		timeout   time.Duration
+		delay     time.Duration
+		cancelled bool
+		wantErr   error
+	}{
+		{
+			name:      "OK",
+			timeout:   time.Second,
+			delay:     0,
+			cancelled: false,
+			wantErr:   nil,
+		},
+		{
+			name:      "No Limit",
+			timeout:   0,
+			delay:     20 * time.Millisecond,
+			cancelled: false,
+			wantErr:   nil,
+		},
+		{
+			name:      "Timed Out",
+			timeout:   10 * time.Millisecond,
+			delay:     time.Second,
+			cancelled: false,
+			wantErr:   apperrors.ErrTimeout,
+		},
+		{
+			name:      "Caller Gone",
+			timeout:   time.Second,
+			delay:     time.Second,
+			cancelled: true,
+			wantErr:   sqlmock.ErrCancelled,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init deps
+			dataBase, mock, err := sqlmock.Newx()
+			require.NoError(t, err)
+
+			defer dataBase.Close()
+
+			db := sqltrace.Wrap(dataBase, semconv.DBSystemPostgreSQL, sqltrace.Timeouts{
+				Read:  testCase.timeout,
+				Write: testCase.timeout,
+			})
+			ctx, cancel := context.WithCancel(context.Background())
+
+			defer cancel()
+
+			if testCase.cancelled {
+				time.AfterFunc(10*time.Millisecond, cancel)
+			}
+
+			mock.ExpectQuery("SELECT 1").
+				WillDelayFor(testCase.delay).
+				WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+
+			mock.ExpectQuery("SELECT 2").
+				WillDelayFor(testCase.delay).
+				WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(2))
+
+			// Call and assert
+			var n int
+
+			err = db.GetContext(ctx, &n, "SELECT 1")
+			require.ErrorIs(t, err, testCase.wantErr)
+
+			if testCase.cancelled {
+				return
+			}
+
+			err = db.QueryRowContext(ctx, "SELECT 2").Scan(&n)
+			require.ErrorIs(t, err, testCase.wantErr)
+		})
+	}
+}
+
+func TestDB_Timeout_operations(t *testing.T) {
+	const delay = 50 * time.Millisecond
+
+	testTable := []struct {
+		name    string
+		call    func(ctx context.Context, db *sqltrace.DB) error
+		wantErr error
+	}{
+		{
+			name: "Read",
+			call: func(ctx context.Context, db *sqltrace.DB) error {
+				_, err := db.ExecContext(ctx, "SELECT 1")
+
+				return err
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Write",
+			call: func(ctx context.Context, db *sqltrace.DB) error {
+				_, err := db.ExecContext(ctx, "UPDATE t SET n = 1")
+
+				return err
+			},
+			wantErr: apperrors.ErrTimeout,
+		},
+		{
+			name: "Write In WITH",
+			call: func(ctx context.Context, db *sqltrace.DB) error {
+				_, err := db.ExecContext(ctx, `
+					WITH expired AS (
+					    UPDATE t SET status = 'expired' RETURNING id
+					)
+					SELECT id FROM expired`)
+
+				return err
+			},
+			wantErr: apperrors.ErrTimeout,
+		},
+		{
+			name: "Read In WITH",
+			call: func(ctx context.Context, db *sqltrace.DB) error {
+				_, err := db.ExecContext(ctx, "WITH updated_at AS (SELECT 1) SELECT * FROM updated_at")
+
+				return err
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Own Read Timeout",
+			call: func(ctx context.Context, db *sqltrace.DB) error {
+				_, err := db.WithReadTimeout(10*time.Millisecond).ExecContext(ctx, "SELECT 1")
+
+				return err
+			},
+			wantErr: apperrors.ErrTimeout,
+		},
+		{
+			name: "Transaction",
+			call: func(ctx context.Context, db *sqltrace.DB) error {
+				transaction, err := db.BeginTxx(ctx, nil)
+				if err != nil {
+					return err
+				}
+
+				defer func() { _ = transaction.Rollback() }()
+
+				_, err = transaction.ExecContext(ctx, "SELECT 1")
+
+				return err
+			},
+			wantErr: apperrors.ErrTimeout,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init deps
+			dataBase, mock, err := sqlmock.Newx()
+			require.NoError(t, err)
+
+			defer dataBase.Close()
+
+			db := sqltrace.Wrap(dataBase, semconv.DBSystemPostgreSQL, sqltrace.Timeouts{
+				Read:  time.Second,
+				Write: 10 * time.Millisecond,
+			})
+
+			mock.MatchExpectationsInOrder(false)
+			mock.ExpectBegin()
+			mock.ExpectExec(".*").WillDelayFor(delay).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectRollback()
+
+			// Call and assert
+			require.ErrorIs(t, testCase.call(context.Background(), db), testCase.wantErr)
+		})
+	}
+}
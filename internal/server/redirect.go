@@ -0,0 +1,30 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const httpsPort = "443"
+
+// NewRedirectServer listens on port and sends every request to the same URL
+// over HTTPS on tlsPort.
+func NewRedirectServer(port, tlsPort string, readTimeout, writeTimeout time.Duration) *Server {
+	redirect := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if hostname, _, err := net.SplitHostPort(host); err == nil {
+			host = hostname
+		}
+
+		if tlsPort != httpsPort {
+			host = net.JoinHostPort(host, tlsPort)
+		}
+
+		target := url.URL{Scheme: "https", Host: host, Path: r.URL.Path, RawQuery: r.URL.RawQuery}
+		http.Redirect(w, r, target.String(), http.StatusPermanentRedirect)
+	})
+
+	return NewServer(port, redirect, 0, readTimeout, writeTimeout)
+}
@@ -2,12 +2,17 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"net/http"
 	"time"
 )
 
 type Server struct {
-	httpServer *http.Server
+	httpServer  *http.Server
+	certs       *certReloader
+	watchCtx    context.Context //nolint:containedctx // lives as long as the server
+	stopWatch   context.CancelFunc
+	watchPeriod time.Duration
 }
 
 func NewServer(
@@ -24,13 +29,45 @@ func NewServer(
 			ReadTimeout:    readTimeout,
 			WriteTimeout:   writeTimeout,
 		},
+		certs:       nil,
+		watchCtx:    nil,
+		stopWatch:   func() {},
+		watchPeriod: 0,
 	}
 }
 
+// EnableTLS makes Run serve HTTPS, with HTTP/2, using the certificate in
+// cfg. It fails when the certificate can't be loaded.
+func (s *Server) EnableTLS(cfg TLSConfig) error {
+	certs, err := newCertReloader(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return err
+	}
+
+	s.certs = certs
+	s.watchCtx, s.stopWatch = context.WithCancel(context.Background())
+	s.watchPeriod = cfg.WatchInterval
+	s.httpServer.TLSConfig = &tls.Config{
+		MinVersion:     TLSVersions[cfg.MinVersion],
+		GetCertificate: certs.getCertificate,
+		NextProtos:     []string{"h2", "http/1.1"},
+	}
+
+	return nil
+}
+
 func (s *Server) Run() error {
-	return s.httpServer.ListenAndServe()
+	if s.certs == nil {
+		return s.httpServer.ListenAndServe()
+	}
+
+	go s.certs.watch(s.watchCtx, s.watchPeriod)
+
+	return s.httpServer.ListenAndServeTLS("", "")
 }
 
 func (s *Server) Shutdown(ctx context.Context) error {
+	s.stopWatch()
+
 	return s.httpServer.Shutdown(ctx)
 }
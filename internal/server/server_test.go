@@ -0,0 +1,124 @@
+package server //nolint:testpackage // need to use certReloader.
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeCert(t *testing.T, dir, commonName string, modTime time.Time) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+
+	return certFile, keyFile
+}
+
+func commonName(t *testing.T, reloader *certReloader) string {
+	t.Helper()
+
+	cert, err := reloader.getCertificate(nil)
+	require.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+
+	return leaf.Subject.CommonName
+}
+
+func TestCertReloader_watch(t *testing.T) {
+	// Init deps
+	dir := t.TempDir()
+	start := time.Now().Add(-time.Hour)
+
+	certFile, keyFile := writeCert(t, dir, "old", start)
+	reloader, err := newCertReloader(certFile, keyFile)
+	require.NoError(t, err)
+	require.Equal(t, "old", commonName(t, reloader))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go reloader.watch(ctx, 10*time.Millisecond)
+
+	// Broken files keep the old certificate
+	require.NoError(t, os.WriteFile(certFile, []byte("garbage"), 0o600))
+	require.NoError(t, os.Chtimes(certFile, start.Add(time.Minute), start.Add(time.Minute)))
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, "old", commonName(t, reloader))
+
+	// Renewed files are picked up
+	writeCert(t, dir, "new", start.Add(2*time.Minute))
+	require.Eventually(t, func() bool {
+		return commonName(t, reloader) == "new"
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestNewRedirectServer(t *testing.T) {
+	testTable := []struct {
+		name             string
+		tlsPort          string
+		target           string
+		expectedLocation string
+	}{
+		{
+			name:             "Default Port",
+			tlsPort:          "443",
+			target:           "http://studio.example:80/api/lists?page=2",
+			expectedLocation: "https://studio.example/api/lists?page=2",
+		},
+		{
+			name:             "Custom Port",
+			tlsPort:          "8443",
+			target:           "http://studio.example/auth/sign-in",
+			expectedLocation: "https://studio.example:8443/auth/sign-in",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init deps
+			srv := NewRedirectServer("8080", testCase.tlsPort, time.Second, time.Second)
+
+			// Make request
+			recorder := httptest.NewRecorder()
+			srv.httpServer.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, testCase.target, nil))
+
+			// Assert
+			require.Equal(t, http.StatusPermanentRedirect, recorder.Code)
+			require.Equal(t, testCase.expectedLocation, recorder.Header().Get("Location"))
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TLSConfig turns on HTTPS when CertFile and KeyFile are set. The files are
+// reloaded on SIGHUP and whenever they change on disk, checked every
+// WatchInterval, so renewed certificates apply without a restart.
+type TLSConfig struct {
+	CertFile      string        `mapstructure:"certFile"`
+	KeyFile       string        `mapstructure:"keyFile"`
+	MinVersion    string        `mapstructure:"minVersion"`
+	WatchInterval time.Duration `mapstructure:"watchInterval"`
+	// RedirectPort, when set, gets a plain HTTP listener that redirects to
+	// HTTPS.
+	RedirectPort string `mapstructure:"redirectPort"`
+}
+
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != ""
+}
+
+//nolint:gochecknoglobals // lookup table
+var TLSVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// NewTLSConfig loads the certificate in cfg for a server that doesn't run
+// on Server, such as the gRPC one, and reloads it as EnableTLS does until
+// ctx is cancelled.
+func NewTLSConfig(ctx context.Context, cfg TLSConfig) (*tls.Config, error) {
+	certs, err := newCertReloader(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	go certs.watch(ctx, cfg.WatchInterval)
+
+	return &tls.Config{
+		MinVersion:     TLSVersions[cfg.MinVersion],
+		GetCertificate: certs.getCertificate,
+		NextProtos:     []string{"h2"},
+	}, nil
+}
+
+// certReloader hands out the current certificate to new handshakes, so
+// swapping it leaves open connections alone.
+type certReloader struct {
+	certFile, keyFile string
+	cert              atomic.Pointer[tls.Certificate]
+	modTime           time.Time
+}
+
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	reloader := &certReloader{certFile: certFile, keyFile: keyFile, cert: atomic.Pointer[tls.Certificate]{}}
+	if err := reloader.reload(); err != nil {
+		return nil, err
+	}
+
+	return reloader, nil
+}
+
+func (r *certReloader) reload() error {
+	modTime, err := r.lastModified()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("error loading certificate: %w", err)
+	}
+
+	r.cert.Store(&cert)
+	r.modTime = modTime
+
+	return nil
+}
+
+func (r *certReloader) lastModified() (time.Time, error) {
+	var latest time.Time
+
+	for _, path := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("error reading certificate: %w", err)
+		}
+
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return latest, nil
+}
+
+func (r *certReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.cert.Load(), nil
+}
+
+// watch reloads the certificate on SIGHUP and when the files change until
+// ctx is cancelled. A broken certificate is logged and the old one kept.
+func (r *certReloader) watch(ctx context.Context, interval time.Duration) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+		case <-ticker.C:
+			if modTime, err := r.lastModified(); err == nil && !modTime.After(r.modTime) {
+				continue
+			}
+		}
+
+		if err := r.reload(); err != nil {
+			logrus.Errorf("keeping the current certificate: %s", err.Error())
+			continue
+		}
+
+		logrus.Infof("certificate reloaded from %s", r.certFile)
+	}
+}
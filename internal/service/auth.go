@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"encoding/hex"
 	"errors"
 	"time"
@@ -8,11 +9,15 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/sha3"
 	"main.go/internal/entity"
+	apperrors "main.go/internal/errors"
 )
 
+var ErrUserInactive = apperrors.Unauthenticated("user_inactive", "user is disabled or deleted", nil)
+
 type AuthorizationRepository interface {
-	CreateUser(user entity.User) (int, error)
-	GetUser(username, password string) (entity.User, error)
+	CreateUser(ctx context.Context, user entity.User) (int, error)
+	GetUser(ctx context.Context, username, password string) (entity.User, error)
+	IsActive(ctx context.Context, userID int) (bool, error)
 }
 
 const (
@@ -34,13 +39,27 @@ func NewAuthorizationService(repo AuthorizationRepository) *AuthorizationService
 	return &AuthorizationService{repo: repo}
 }
 
-func (s *AuthorizationService) CreateUser(user entity.User) (int, error) {
+func (s *AuthorizationService) CreateUser(ctx context.Context, user entity.User) (int, error) {
+	ctx, span := tracer.Start(ctx, "AuthorizationService.CreateUser")
+	defer span.End()
+
+	if err := user.Validate(); err != nil {
+		return 0, err
+	}
+
 	user.Password = generatePasswordHash(user.Password)
-	return s.repo.CreateUser(user)
+	return s.repo.CreateUser(ctx, user)
 }
 
-func (s *AuthorizationService) GenerateToken(username, password string) (string, error) {
-	user, err := s.repo.GetUser(username, generatePasswordHash(password))
+func (s *AuthorizationService) GenerateToken(ctx context.Context, username, password string) (string, error) {
+	ctx, span := tracer.Start(ctx, "AuthorizationService.GenerateToken")
+	defer span.End()
+
+	user, err := s.repo.GetUser(ctx, username, generatePasswordHash(password))
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return "", apperrors.Unauthenticated("invalid_credentials", "invalid username or password", err)
+	}
+
 	if err != nil {
 		return "", err
 	}
@@ -76,7 +95,13 @@ func (s *AuthorizationService) GenerateToken(username, password string) (string,
 	return token.SignedString([]byte(sighingKey))
 }
 
-func (s *AuthorizationService) ParseToken(accessToken string) (int, error) {
+// ParseToken returns the user the token was issued to. Tokens of users that
+// have since been disabled or deleted are refused, so that blocking a user
+// takes effect right away rather than when their tokens expire.
+func (s *AuthorizationService) ParseToken(ctx context.Context, accessToken string) (int, error) {
+	ctx, span := tracer.Start(ctx, "AuthorizationService.ParseToken")
+	defer span.End()
+
 	token, err := jwt.ParseWithClaims(
 		accessToken,
 		&tokenClaims{
@@ -105,7 +130,7 @@ func (s *AuthorizationService) ParseToken(accessToken string) (int, error) {
 			return []byte(sighingKey), nil
 		})
 	if err != nil {
-		return 0, err
+		return 0, apperrors.Unauthenticated("invalid_token", err.Error(), err)
 	}
 
 	claims, ok := token.Claims.(*tokenClaims)
@@ -113,6 +138,15 @@ func (s *AuthorizationService) ParseToken(accessToken string) (int, error) {
 		return 0, errors.New("token claims are not type *tokenClaims")
 	}
 
+	active, err := s.repo.IsActive(ctx, claims.UserID)
+	if err != nil {
+		return 0, err
+	}
+
+	if !active {
+		return 0, ErrUserInactive
+	}
+
 	return claims.UserID, nil
 }
 
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"main.go/internal/entity"
+	apperrors "main.go/internal/errors"
+)
+
+var (
+	ErrInvalidBooking      = apperrors.Validation("invalid_booking", "invalid booking request", nil)
+	ErrSlotUnavailable     = apperrors.Conflict("slot_unavailable", "requested time is not available", nil)
+	ErrBookingLimitReached = apperrors.RateLimited("booking_limit_reached", "too many booking requests", nil)
+	ErrBookingNotPending   = apperrors.Conflict(
+		"booking_not_pending", "booking request is not waiting for approval", nil)
+	ErrBookingListForeign = apperrors.Forbidden(
+		"booking_list_foreign", "list does not belong to the requested artist", nil)
+)
+
+type BookingRepository interface {
+	GetArtists(ctx context.Context) ([]entity.Artist, error)
+	GetBusy(ctx context.Context, artistID int, start, end time.Time) ([]entity.TimeRange, error)
+	CreateRequest(ctx context.Context, request entity.BookingRequest) (int, error)
+	CountRecentByIP(ctx context.Context, clientIP string, since time.Time) (int, error)
+	CountPendingByEmail(ctx context.Context, clientEmail string) (int, error)
+	GetRequests(ctx context.Context, filter entity.BookingsFilter) ([]entity.BookingRequest, error)
+	ApproveRequest(ctx context.Context, requestID, listID int) (int, error)
+	RejectRequest(ctx context.Context, requestID int) error
+}
+
+// BookingConfig sets the opening hours clients can book within, as offsets
+// from midnight, and the limits that keep the public portal from being
+// flooded.
+type BookingConfig struct {
+	DayStart           time.Duration
+	DayEnd             time.Duration
+	MinSlot            time.Duration
+	MaxRange           time.Duration
+	MaxRequestsPerIP   int
+	IPWindow           time.Duration
+	MaxPendingPerEmail int
+}
+
+type BookingService struct {
+	repo     BookingRepository
+	listRepo TimeslotListRepository
+	cfg      BookingConfig
+	recorder Recorder
+}
+
+func NewBookingService(
+	repo BookingRepository,
+	listRepo TimeslotListRepository,
+	cfg BookingConfig,
+	recorder Recorder,
+) *BookingService {
+	return &BookingService{repo: repo, listRepo: listRepo, cfg: cfg, recorder: recorder}
+}
+
+func (s *BookingService) GetArtists(ctx context.Context) ([]entity.Artist, error) {
+	ctx, span := tracer.Start(ctx, "BookingService.GetArtists")
+	defer span.End()
+
+	return s.repo.GetArtists(ctx)
+}
+
+// GetAvailability returns the free parts of the artist's opening hours in
+// the range that are at least MinSlot long.
+func (s *BookingService) GetAvailability(
+	ctx context.Context,
+	artistID int,
+	input entity.AvailabilityInput,
+) ([]entity.TimeRange, error) {
+	ctx, span := tracer.Start(ctx, "BookingService.GetAvailability")
+	defer span.End()
+
+	if !input.End.After(input.Start) {
+		return nil, fmt.Errorf("%w: end must be after start", ErrInvalidBooking)
+	}
+
+	if input.End.Sub(input.Start) > s.cfg.MaxRange {
+		return nil, fmt.Errorf("%w: range is longer than %s", ErrInvalidBooking, s.cfg.MaxRange)
+	}
+
+	busy, err := s.repo.GetBusy(ctx, artistID, input.Start, input.End)
+	if err != nil {
+		return nil, err
+	}
+
+	return freeSlots(input.Start, input.End, busy, s.cfg), nil
+}
+
+// CreateRequest stores a client's booking request in the requested state.
+// Requests that filled in the honeypot are dropped without telling the
+// sender.
+func (s *BookingService) CreateRequest(ctx context.Context, request entity.BookingRequest) (int, error) {
+	ctx, span := tracer.Start(ctx, "BookingService.CreateRequest")
+	defer span.End()
+
+	if request.Website != "" {
+		return 0, nil
+	}
+
+	if err := request.Validate(); err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidBooking, err.Error())
+	}
+
+	if err := s.checkLimits(ctx, request); err != nil {
+		return 0, err
+	}
+
+	available, err := s.GetAvailability(ctx, request.ArtistID, entity.AvailabilityInput{
+		Start: request.Start,
+		End:   request.End,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if len(available) != 1 || !available[0].Start.Equal(request.Start) || !available[0].End.Equal(request.End) {
+		return 0, ErrSlotUnavailable
+	}
+
+	return s.repo.CreateRequest(ctx, request)
+}
+
+func (s *BookingService) GetRequests(
+	ctx context.Context,
+	filter entity.BookingsFilter,
+) ([]entity.BookingRequest, error) {
+	ctx, span := tracer.Start(ctx, "BookingService.GetRequests")
+	defer span.End()
+
+	return s.repo.GetRequests(ctx, filter)
+}
+
+// ApproveRequest books the request into one of the approving user's lists.
+// The list has to belong to the artist the request is for, and the slot is
+// checked again since it may have been booked after the request came in.
+func (s *BookingService) ApproveRequest(
+	ctx context.Context,
+	userID, requestID int,
+	input entity.ApproveBookingInput,
+) (int, error) {
+	ctx, span := tracer.Start(ctx, "BookingService.ApproveRequest")
+	defer span.End()
+
+	if _, err := s.listRepo.GetByID(ctx, userID, input.ListID); err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return 0, apperrors.Forbidden("list_forbidden", "list does not belong to the user", err)
+		}
+
+		return 0, err
+	}
+
+	itemID, err := s.repo.ApproveRequest(ctx, requestID, input.ListID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return 0, ErrBookingNotPending
+	case errors.Is(err, entity.ErrBookingSlotTaken):
+		return 0, ErrSlotUnavailable
+	case errors.Is(err, entity.ErrBookingListForeign):
+		return 0, ErrBookingListForeign
+	case err != nil:
+		return 0, err
+	}
+
+	s.recorder.BookingCreated(BookingSourcePortal)
+
+	return itemID, nil
+}
+
+func (s *BookingService) RejectRequest(ctx context.Context, requestID int) error {
+	ctx, span := tracer.Start(ctx, "BookingService.RejectRequest")
+	defer span.End()
+
+	err := s.repo.RejectRequest(ctx, requestID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrBookingNotPending
+	}
+
+	return err
+}
+
+func (s *BookingService) checkLimits(ctx context.Context, request entity.BookingRequest) error {
+	recent, err := s.repo.CountRecentByIP(ctx, request.ClientIP, time.Now().Add(-s.cfg.IPWindow))
+	if err != nil {
+		return err
+	}
+
+	if recent >= s.cfg.MaxRequestsPerIP {
+		return ErrBookingLimitReached
+	}
+
+	pending, err := s.repo.CountPendingByEmail(ctx, request.ClientEmail)
+	if err != nil {
+		return err
+	}
+
+	if pending >= s.cfg.MaxPendingPerEmail {
+		return ErrBookingLimitReached
+	}
+
+	return nil
+}
+
+// freeSlots cuts the busy ranges out of each day's opening hours between
+// start and end.
+func freeSlots(
+	start, end time.Time,
+	busy []entity.TimeRange,
+	cfg BookingConfig,
+) []entity.TimeRange {
+	sort.Slice(busy, func(i, j int) bool {
+		return busy[i].Start.Before(busy[j].Start)
+	})
+
+	slots := make([]entity.TimeRange, 0)
+	addSlot := func(from, to time.Time) {
+		if to.Sub(from) >= cfg.MinSlot && to.Sub(from) > 0 {
+			slots = append(slots, entity.TimeRange{Start: from, End: to})
+		}
+	}
+
+	year, month, day := start.Date()
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, start.Location())
+
+	for ; midnight.Before(end); midnight = midnight.AddDate(0, 0, 1) {
+		opens := laterOf(midnight.Add(cfg.DayStart), start)
+		closes := earlierOf(midnight.Add(cfg.DayEnd), end)
+		cursor := opens
+
+		for _, taken := range busy {
+			if !taken.End.After(cursor) || !taken.Start.Before(closes) {
+				continue
+			}
+
+			addSlot(cursor, earlierOf(taken.Start, closes))
+			cursor = taken.End
+		}
+
+		addSlot(cursor, closes)
+	}
+
+	return slots
+}
+
+func laterOf(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
+
+func earlierOf(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"main.go/internal/entity"
+	apperrors "main.go/internal/errors"
+	"main.go/internal/health"
+)
+
+var (
+	ErrIdempotencyKeyReused = apperrors.Validation(
+		"idempotency_key_reused", "idempotency key was used with a different request", nil)
+	ErrIdempotencyKeyInProgress = apperrors.Conflict(
+		"idempotency_key_in_progress", "request with this idempotency key is still in progress", nil)
+)
+
+type IdempotencyRepository interface {
+	Create(ctx context.Context, record entity.IdempotencyKey) (bool, error)
+	Reclaim(ctx context.Context, record entity.IdempotencyKey, claimedBefore time.Time) (bool, error)
+	Get(ctx context.Context, userID int, key string) (entity.IdempotencyKey, error)
+	SaveResponse(ctx context.Context, record entity.IdempotencyKey) error
+	Delete(ctx context.Context, userID int, key string) error
+	DeleteStale(ctx context.Context, before time.Time) error
+}
+
+// IdempotencyService keeps responses for ttl. A claim on a key that is not
+// finished within lease is taken to be lost with the instance handling it,
+// and a retry of the same request takes it over.
+type IdempotencyService struct {
+	repo  IdempotencyRepository
+	ttl   time.Duration
+	lease time.Duration
+}
+
+func NewIdempotencyService(repo IdempotencyRepository, ttl, lease time.Duration) *IdempotencyService {
+	return &IdempotencyService{repo: repo, ttl: ttl, lease: lease}
+}
+
+// Start claims the key for the request. It returns the stored record when the
+// same request already completed under the key, and a record without a
+// status code when the caller should handle the request and Finish or
+// Release it.
+func (s *IdempotencyService) Start(
+	ctx context.Context,
+	userID int,
+	key string,
+	request []byte,
+) (entity.IdempotencyKey, error) {
+	ctx, span := tracer.Start(ctx, "IdempotencyService.Start")
+	defer span.End()
+
+	sum := sha256.Sum256(request)
+	record := entity.IdempotencyKey{
+		UserID:      userID,
+		Key:         key,
+		RequestHash: hex.EncodeToString(sum[:]),
+		StatusCode:  0,
+		Response:    nil,
+		CreatedAt:   time.Now(),
+	}
+
+	created, err := s.repo.Create(ctx, record)
+	if err != nil || created {
+		return record, err
+	}
+
+	stored, err := s.repo.Get(ctx, userID, key)
+	if err != nil {
+		return record, err
+	}
+
+	if stored.RequestHash != record.RequestHash {
+		return record, ErrIdempotencyKeyReused
+	}
+
+	if stored.Completed() {
+		return stored, nil
+	}
+
+	reclaimed, err := s.repo.Reclaim(ctx, record, time.Now().Add(-s.lease))
+	if err != nil || reclaimed {
+		return record, err
+	}
+
+	return record, ErrIdempotencyKeyInProgress
+}
+
+// Finish stores the response for replays. Server errors are not stored, the
+// key is released instead so that the client can retry.
+func (s *IdempotencyService) Finish(ctx context.Context, record entity.IdempotencyKey) error {
+	ctx, span := tracer.Start(ctx, "IdempotencyService.Finish")
+	defer span.End()
+
+	if record.StatusCode >= http.StatusInternalServerError {
+		return s.Release(ctx, record)
+	}
+
+	return s.repo.SaveResponse(ctx, record)
+}
+
+// Release gives the key up without a response, for requests that did not
+// complete, so that the client can retry right away.
+func (s *IdempotencyService) Release(ctx context.Context, record entity.IdempotencyKey) error {
+	ctx, span := tracer.Start(ctx, "IdempotencyService.Release")
+	defer span.End()
+
+	return s.repo.Delete(ctx, record.UserID, record.Key)
+}
+
+// Run drops keys older than the TTL every interval until ctx is cancelled.
+func (s *IdempotencyService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.repo.DeleteStale(ctx, time.Now().Add(-s.ttl)); err != nil {
+				logrus.Errorf("error deleting stale idempotency keys: %s", err.Error())
+			}
+
+			health.Beat(ctx)
+		}
+	}
+}
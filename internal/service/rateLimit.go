@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"main.go/internal/entity"
+	"main.go/internal/health"
+)
+
+type RateLimitRepository interface {
+	Take(ctx context.Context, key string, limit entity.RateLimit, now time.Time) (entity.RateDecision, error)
+	DeleteStale(ctx context.Context, before time.Time) error
+}
+
+type RateLimitService struct {
+	repo   RateLimitRepository
+	limits map[string]entity.RateLimit
+	// refillTime is how long the slowest bucket takes to fill up from empty.
+	refillTime time.Duration
+}
+
+func NewRateLimitService(repo RateLimitRepository, limits map[string]entity.RateLimit) *RateLimitService {
+	var refillTime time.Duration
+
+	for _, limit := range limits {
+		full := time.Duration(float64(limit.Burst) / limit.RefillRate() * float64(time.Second))
+		if full > refillTime {
+			refillTime = full
+		}
+	}
+
+	return &RateLimitService{repo: repo, limits: limits, refillTime: refillTime}
+}
+
+// Allow takes a token from the key's bucket in the route group. Groups
+// without a configured limit are not limited.
+func (s *RateLimitService) Allow(ctx context.Context, group, key string) (entity.RateDecision, error) {
+	ctx, span := tracer.Start(ctx, "RateLimitService.Allow")
+	defer span.End()
+
+	limit, ok := s.limits[group]
+	if !ok {
+		return entity.RateDecision{Allowed: true, Remaining: 0, RetryAfter: 0}, nil
+	}
+
+	return s.repo.Take(ctx, group+":"+key, limit, time.Now())
+}
+
+// Run drops buckets that have refilled completely every interval until ctx
+// is cancelled.
+func (s *RateLimitService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.repo.DeleteStale(ctx, time.Now().Add(-s.refillTime)); err != nil {
+				logrus.Errorf("error deleting stale rate limit buckets: %s", err.Error())
+			}
+
+			health.Beat(ctx)
+		}
+	}
+}
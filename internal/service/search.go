@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"main.go/internal/entity"
+)
+
+type SearchRepository interface {
+	Search(ctx context.Context, userID int, tsQuery string, limit int) ([]entity.SearchResult, error)
+}
+
+type SearchService struct {
+	repo SearchRepository
+}
+
+func NewSearchService(repo SearchRepository) *SearchService {
+	return &SearchService{repo: repo}
+}
+
+func (s *SearchService) Search(
+	ctx context.Context,
+	userID int,
+	input entity.SearchInput,
+) ([]entity.SearchResult, error) {
+	ctx, span := tracer.Start(ctx, "SearchService.Search")
+	defer span.End()
+
+	tsQuery := prefixTSQuery(input.Query)
+	if tsQuery == "" {
+		return []entity.SearchResult{}, nil
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = entity.DefaultPageLimit
+	}
+
+	if limit > entity.MaxPageLimit {
+		limit = entity.MaxPageLimit
+	}
+
+	return s.repo.Search(ctx, userID, tsQuery, limit)
+}
+
+// prefixTSQuery turns free text into a to_tsquery expression where every
+// word must match as a prefix, so "sle mar" finds "sleeve" for "Maria".
+// Anything but letters and digits is dropped, which keeps tsquery operators
+// out of user input.
+func prefixTSQuery(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+
+	return strings.Join(words, " & ")
+}
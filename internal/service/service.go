@@ -1,45 +1,131 @@
 package service
 
 import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
 	"main.go/internal/entity"
 	"main.go/internal/repository"
 )
 
 //go:generate mockgen -source=service.go -destination=mocks/mock.go
 
+var tracer = otel.Tracer("main.go/internal/service")
+
 type Authorization interface {
-	CreateUser(user entity.User) (int, error)
-	GenerateToken(username, password string) (string, error)
-	ParseToken(token string) (int, error)
+	CreateUser(ctx context.Context, user entity.User) (int, error)
+	GenerateToken(ctx context.Context, username, password string) (string, error)
+	ParseToken(ctx context.Context, token string) (int, error)
 }
 
 type TimeslotList interface {
-	Create(userID int, list entity.TimeslotsList) (int, error)
-	GetAll(userID int) ([]entity.TimeslotsList, error)
-	GetByID(userID, listID int) (entity.TimeslotsList, error)
-	Delete(userID, listID int) error
-	Update(userID, listID int, input entity.UpdateListInput) error
+	Create(ctx context.Context, userID int, list entity.TimeslotsList) (int, error)
+	GetAll(ctx context.Context, userID int, filter entity.ListsFilter) (entity.ListsPage, error)
+	GetByID(ctx context.Context, userID, listID int) (entity.TimeslotsList, error)
+	Delete(ctx context.Context, userID, listID int) error
+	Update(ctx context.Context, userID, listID int, input entity.UpdateListInput, version int) error
 }
 
 type TimeslotItem interface {
-	Create(userID, listID int, input entity.TimeslotItem) (int, error)
-	GetAll(userID, listID int) ([]entity.TimeslotItem, error)
-	GetByID(userID, itemID int) (entity.TimeslotItem, error)
-	Delete(userID, itemID int) error
-	Update(userID, itemID int, input entity.UpdateItemInput) error
-	GetByRange(input entity.ItemsByRange) ([]entity.TimeslotItem, error)
+	Create(ctx context.Context, userID, listID int, input entity.TimeslotItem) (int, error)
+	GetAll(ctx context.Context, userID, listID int, filter entity.ItemsFilter) (entity.ItemsPage, error)
+	GetByID(ctx context.Context, userID, itemID int) (entity.TimeslotItem, error)
+	Delete(ctx context.Context, userID, itemID int) error
+	Update(ctx context.Context, userID, itemID int, input entity.UpdateItemInput, version int) error
+	GetByRange(ctx context.Context, input entity.ItemsByRange) (entity.ItemsPage, error)
+}
+
+type Search interface {
+	Search(ctx context.Context, userID int, input entity.SearchInput) ([]entity.SearchResult, error)
+}
+
+type Waitlist interface {
+	CreateEntry(ctx context.Context, entry entity.WaitlistEntry) (int, error)
+	GetEntries(ctx context.Context, artistID int) ([]entity.WaitlistEntry, error)
+	DeleteEntry(ctx context.Context, entryID int) error
+	GetOffers(ctx context.Context, artistID int) ([]entity.WaitlistOffer, error)
+	AcceptOffer(ctx context.Context, offerID int) (int, error)
+	DeclineOffer(ctx context.Context, offerID int) error
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type Booking interface {
+	GetArtists(ctx context.Context) ([]entity.Artist, error)
+	GetAvailability(ctx context.Context, artistID int, input entity.AvailabilityInput) ([]entity.TimeRange, error)
+	CreateRequest(ctx context.Context, request entity.BookingRequest) (int, error)
+	GetRequests(ctx context.Context, filter entity.BookingsFilter) ([]entity.BookingRequest, error)
+	ApproveRequest(ctx context.Context, userID, requestID int, input entity.ApproveBookingInput) (int, error)
+	RejectRequest(ctx context.Context, requestID int) error
+}
+
+type RateLimit interface {
+	Allow(ctx context.Context, group, key string) (entity.RateDecision, error)
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type Idempotency interface {
+	Start(ctx context.Context, userID int, key string, request []byte) (entity.IdempotencyKey, error)
+	Finish(ctx context.Context, record entity.IdempotencyKey) error
+	Release(ctx context.Context, record entity.IdempotencyKey) error
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type Users interface {
+	GetAll(ctx context.Context) ([]entity.UserInfo, error)
+	SetDisabled(ctx context.Context, userID int, disabled bool) error
+	Delete(ctx context.Context, userID int) error
+	ResetPassword(ctx context.Context, userID int, password string) error
+	SetColor(ctx context.Context, userID int, color string) error
+	TransferList(ctx context.Context, listID, userID int) error
+	StudioStats(ctx context.Context) (entity.StudioStats, error)
+}
+
+type Stats interface {
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// Config tunes the services. A nil Recorder drops the business events.
+type Config struct {
+	WaitlistHold     time.Duration
+	Booking          BookingConfig
+	RateLimits       map[string]entity.RateLimit
+	IdempotencyTTL   time.Duration
+	IdempotencyLease time.Duration
+	Recorder         Recorder
 }
 
 type Service struct {
 	Authorization
 	TimeslotList
 	TimeslotItem
+	Search
+	Waitlist
+	Booking
+	RateLimit
+	Idempotency
+	Stats
+	Users
 }
 
-func NewService(repo *repository.Repository) *Service {
+func NewService(repo *repository.Repository, cfg Config) *Service {
+	recorder := cfg.Recorder
+	if recorder == nil {
+		recorder = nopRecorder{}
+	}
+
+	waitlist := NewWaitlistService(repo.Waitlist, cfg.WaitlistHold, recorder)
+
 	return &Service{
 		Authorization: NewAuthorizationService(repo.Authorization),
 		TimeslotList:  NewTimeslotListService(repo.TimeslotList),
-		TimeslotItem:  NewTimeslotItemService(repo.TimeslotItem, repo.TimeslotList),
+		TimeslotItem:  NewTimeslotItemService(repo.TimeslotItem, repo.TimeslotList, waitlist, recorder),
+		Search:        NewSearchService(repo.Search),
+		Waitlist:      waitlist,
+		Booking:       NewBookingService(repo.Booking, repo.TimeslotList, cfg.Booking, recorder),
+		RateLimit:     NewRateLimitService(repo.RateLimit, cfg.RateLimits),
+		Idempotency:   NewIdempotencyService(repo.Idempotency, cfg.IdempotencyTTL, cfg.IdempotencyLease),
+		Stats:         NewStatsService(repo.TimeslotItem, recorder),
+		Users:         NewUsersService(repo.Users),
 	}
 }
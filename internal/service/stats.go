@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"main.go/internal/health"
+)
+
+// Where a booked appointment came from.
+const (
+	BookingSourceStaff    = "staff"
+	BookingSourcePortal   = "portal"
+	BookingSourceWaitlist = "waitlist"
+)
+
+// Recorder receives the business events worth watching on a dashboard.
+type Recorder interface {
+	BookingCreated(source string)
+	AppointmentCancelled()
+	SetUpcomingToday(count int)
+}
+
+type nopRecorder struct{}
+
+func (nopRecorder) BookingCreated(string) {}
+func (nopRecorder) AppointmentCancelled() {}
+func (nopRecorder) SetUpcomingToday(int)  {}
+
+type StatsRepository interface {
+	CountUpcoming(ctx context.Context, from, to time.Time) (int, error)
+}
+
+type StatsService struct {
+	repo     StatsRepository
+	recorder Recorder
+}
+
+func NewStatsService(repo StatsRepository, recorder Recorder) *StatsService {
+	return &StatsService{repo: repo, recorder: recorder}
+}
+
+// Refresh counts the appointments still to come today.
+func (s *StatsService) Refresh(ctx context.Context) error {
+	now := time.Now()
+	year, month, day := now.Date()
+	tomorrow := time.Date(year, month, day+1, 0, 0, 0, 0, now.Location())
+
+	count, err := s.repo.CountUpcoming(ctx, now, tomorrow)
+	if err != nil {
+		return err
+	}
+
+	s.recorder.SetUpcomingToday(count)
+
+	return nil
+}
+
+// Run refreshes the stats every interval until ctx is cancelled. Scrapes
+// only read the last value, so they never wait on or fail with the db.
+func (s *StatsService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Refresh(ctx); err != nil {
+			logrus.Errorf("error refreshing stats: %s", err.Error())
+		}
+
+		health.Beat(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -1,64 +1,189 @@
 package service
 
 import (
-	"net/http"
+	"context"
+	"time"
 
 	"main.go/internal/entity"
+	"main.go/internal/logging"
 )
 
 type TimeslotItemRepository interface {
-	Create(listID int, item entity.TimeslotItem) (int, error)
-	GetAll(userID, listID int) ([]entity.TimeslotItem, error)
-	GetByID(userID, itemID int) (entity.TimeslotItem, error)
-	Delete(userID, itemID int) error
-	Update(userID, itemID int, input entity.UpdateItemInput) error
-	GetByRange(input entity.ItemsByRange) ([]entity.TimeslotItem, error)
+	Create(ctx context.Context, listID int, item entity.TimeslotItem) (int, error)
+	GetAll(ctx context.Context, userID, listID int, filter entity.ItemsFilter) ([]entity.TimeslotItem, error)
+	GetByID(ctx context.Context, userID, itemID int) (entity.TimeslotItem, error)
+	Delete(ctx context.Context, userID, itemID int) error
+	Update(ctx context.Context, userID, itemID int, input entity.UpdateItemInput, version int) error
+	GetByRange(ctx context.Context, input entity.ItemsByRange) ([]entity.TimeslotItem, error)
+}
+
+// SlotOfferer passes slots of cancelled or deleted items on to the waitlist.
+type SlotOfferer interface {
+	OfferSlot(ctx context.Context, slot entity.FreedSlot) error
 }
 
 type TimeslotItemService struct {
 	itemRepo TimeslotItemRepository
 	listRepo TimeslotListRepository
+	waitlist SlotOfferer
+	recorder Recorder
 }
 
 func NewTimeslotItemService(
 	itemRepo TimeslotItemRepository,
 	listRepo TimeslotListRepository,
+	waitlist SlotOfferer,
+	recorder Recorder,
 ) *TimeslotItemService {
-	return &TimeslotItemService{listRepo: listRepo, itemRepo: itemRepo}
+	return &TimeslotItemService{listRepo: listRepo, itemRepo: itemRepo, waitlist: waitlist, recorder: recorder}
 }
 
-func (s *TimeslotItemService) Create(userID, listID int, item entity.TimeslotItem) (int, error) {
-	if _, err := s.listRepo.GetByID(userID, listID); err != nil {
-		return http.StatusBadRequest, err
+func (s *TimeslotItemService) Create(ctx context.Context, userID, listID int, item entity.TimeslotItem) (int, error) {
+	ctx, span := tracer.Start(ctx, "TimeslotItemService.Create")
+	defer span.End()
+
+	if err := item.Validate(); err != nil {
+		return 0, err
+	}
+
+	if _, err := s.listRepo.GetByID(ctx, userID, listID); err != nil {
+		return 0, err
+	}
+
+	itemID, err := s.itemRepo.Create(ctx, listID, item)
+	if err != nil {
+		return 0, err
 	}
 
-	return s.itemRepo.Create(listID, item)
+	s.recorder.BookingCreated(BookingSourceStaff)
+
+	return itemID, nil
 }
 
 func (s *TimeslotItemService) GetAll(
+	ctx context.Context,
 	userID, listID int,
-) ([]entity.TimeslotItem, error) {
-	return s.itemRepo.GetAll(userID, listID)
+	filter entity.ItemsFilter,
+) (entity.ItemsPage, error) {
+	ctx, span := tracer.Start(ctx, "TimeslotItemService.GetAll")
+	defer span.End()
+
+	limit := filter.Limit
+	filter.Limit++
+
+	items, err := s.itemRepo.GetAll(ctx, userID, listID, filter)
+	if err != nil {
+		return entity.ItemsPage{}, err
+	}
+
+	return entity.NewItemsPage(items, limit), nil
 }
 
-func (s *TimeslotItemService) GetByID(userID, itemID int) (entity.TimeslotItem, error) {
-	return s.itemRepo.GetByID(userID, itemID)
+func (s *TimeslotItemService) GetByID(ctx context.Context, userID, itemID int) (entity.TimeslotItem, error) {
+	ctx, span := tracer.Start(ctx, "TimeslotItemService.GetByID")
+	defer span.End()
+
+	return s.itemRepo.GetByID(ctx, userID, itemID)
 }
 
-func (s *TimeslotItemService) Delete(userID, itemID int) error {
-	return s.itemRepo.Delete(userID, itemID)
+func (s *TimeslotItemService) Delete(ctx context.Context, userID, itemID int) error {
+	ctx, span := tracer.Start(ctx, "TimeslotItemService.Delete")
+	defer span.End()
+
+	item, err := s.itemRepo.GetByID(ctx, userID, itemID)
+	if err != nil {
+		return err
+	}
+
+	if err = s.itemRepo.Delete(ctx, userID, itemID); err != nil {
+		return err
+	}
+
+	s.releaseSlot(ctx, item)
+
+	return nil
 }
 
-func (s *TimeslotItemService) Update(userID, itemID int, input entity.UpdateItemInput) error {
+// Update changes the item if it is still at version, a zero version skips
+// the check.
+func (s *TimeslotItemService) Update(
+	ctx context.Context,
+	userID, itemID int,
+	input entity.UpdateItemInput,
+	version int,
+) error {
+	ctx, span := tracer.Start(ctx, "TimeslotItemService.Update")
+	defer span.End()
+
 	if err := input.Validate(); err != nil {
 		return err
 	}
 
-	return s.itemRepo.Update(userID, itemID, input)
+	if (input.Start == nil) != (input.End == nil) {
+		current, err := s.itemRepo.GetByID(ctx, userID, itemID)
+		if err != nil {
+			return err
+		}
+
+		if err = input.ValidateRange(current); err != nil {
+			return err
+		}
+	}
+
+	getItem := func() error {
+		_, getErr := s.itemRepo.GetByID(ctx, userID, itemID)
+		return getErr
+	}
+
+	if input.Cancelled == nil || !*input.Cancelled {
+		err := s.itemRepo.Update(ctx, userID, itemID, input, version)
+		return versionError(err, version, getItem)
+	}
+
+	item, err := s.itemRepo.GetByID(ctx, userID, itemID)
+	if err != nil {
+		return err
+	}
+
+	if err = s.itemRepo.Update(ctx, userID, itemID, input, version); err != nil {
+		return versionError(err, version, getItem)
+	}
+
+	s.releaseSlot(ctx, item)
+
+	return nil
 }
 
 func (s *TimeslotItemService) GetByRange(
+	ctx context.Context,
 	input entity.ItemsByRange,
-) ([]entity.TimeslotItem, error) {
-	return s.itemRepo.GetByRange(input)
+) (entity.ItemsPage, error) {
+	ctx, span := tracer.Start(ctx, "TimeslotItemService.GetByRange")
+	defer span.End()
+
+	limit := input.Limit
+	input.Limit++
+
+	items, err := s.itemRepo.GetByRange(ctx, input)
+	if err != nil {
+		return entity.ItemsPage{}, err
+	}
+
+	return entity.NewItemsPage(items, limit), nil
+}
+
+// releaseSlot counts the cancellation of an item that was just cancelled or
+// deleted and offers its time to the waitlist. The item change has already
+// happened, so a failed offer is only logged.
+func (s *TimeslotItemService) releaseSlot(ctx context.Context, item entity.TimeslotItem) {
+	if item.Cancelled || item.Done || !item.End.After(time.Now()) {
+		return
+	}
+
+	s.recorder.AppointmentCancelled()
+
+	slot := entity.FreedSlot{ListID: item.ListID, Start: item.Start, End: item.End, NotBefore: time.Time{}}
+	if err := s.waitlist.OfferSlot(ctx, slot); err != nil {
+		logging.FromContext(ctx).Errorf("error offering slot of item %d to the waitlist: %s", item.ID, err.Error())
+	}
 }
@@ -1,16 +1,18 @@
 package service
 
 import (
+	"context"
+
 	"main.go/internal/entity"
 	"main.go/internal/repository/postgres"
 )
 
 type TimeslotListRepository interface {
-	Create(userID int, list entity.TimeslotsList) (int, error)
-	GetAll(userID int) ([]entity.TimeslotsList, error)
-	GetByID(userID, listID int) (entity.TimeslotsList, error)
-	Delete(userID, listID int) error
-	Update(userID, listID int, input entity.UpdateListInput) error
+	Create(ctx context.Context, userID int, list entity.TimeslotsList) (int, error)
+	GetAll(ctx context.Context, userID int, filter entity.ListsFilter) ([]entity.TimeslotsList, error)
+	GetByID(ctx context.Context, userID, listID int) (entity.TimeslotsList, error)
+	Delete(ctx context.Context, userID, listID int) error
+	Update(ctx context.Context, userID, listID int, input entity.UpdateListInput, version int) error
 }
 
 type TimeslotListService struct {
@@ -21,26 +23,69 @@ func NewTimeslotListService(repo postgres.TimeslotList) *TimeslotListService {
 	return &TimeslotListService{repo: repo}
 }
 
-func (s *TimeslotListService) Create(userID int, list entity.TimeslotsList) (int, error) {
-	return s.repo.Create(userID, list)
+func (s *TimeslotListService) Create(ctx context.Context, userID int, list entity.TimeslotsList) (int, error) {
+	ctx, span := tracer.Start(ctx, "TimeslotListService.Create")
+	defer span.End()
+
+	if err := list.Validate(); err != nil {
+		return 0, err
+	}
+
+	return s.repo.Create(ctx, userID, list)
 }
 
-func (s *TimeslotListService) GetAll(userID int) ([]entity.TimeslotsList, error) {
-	return s.repo.GetAll(userID)
+func (s *TimeslotListService) GetAll(
+	ctx context.Context,
+	userID int,
+	filter entity.ListsFilter,
+) (entity.ListsPage, error) {
+	ctx, span := tracer.Start(ctx, "TimeslotListService.GetAll")
+	defer span.End()
+
+	limit := filter.Limit
+	filter.Limit++
+
+	lists, err := s.repo.GetAll(ctx, userID, filter)
+	if err != nil {
+		return entity.ListsPage{}, err
+	}
+
+	return entity.NewListsPage(lists, limit), nil
 }
 
-func (s *TimeslotListService) GetByID(userID, listID int) (entity.TimeslotsList, error) {
-	return s.repo.GetByID(userID, listID)
+func (s *TimeslotListService) GetByID(ctx context.Context, userID, listID int) (entity.TimeslotsList, error) {
+	ctx, span := tracer.Start(ctx, "TimeslotListService.GetByID")
+	defer span.End()
+
+	return s.repo.GetByID(ctx, userID, listID)
 }
 
-func (s *TimeslotListService) Update(userID, listID int, input entity.UpdateListInput) error {
+// Update changes the list if it is still at version, a zero version skips
+// the check.
+func (s *TimeslotListService) Update(
+	ctx context.Context,
+	userID, listID int,
+	input entity.UpdateListInput,
+	version int,
+) error {
+	ctx, span := tracer.Start(ctx, "TimeslotListService.Update")
+	defer span.End()
+
 	if err := input.Validate(); err != nil {
 		return err
 	}
 
-	return s.repo.Update(userID, listID, input)
+	err := s.repo.Update(ctx, userID, listID, input, version)
+
+	return versionError(err, version, func() error {
+		_, getErr := s.repo.GetByID(ctx, userID, listID)
+		return getErr
+	})
 }
 
-func (s *TimeslotListService) Delete(userID, listID int) error {
-	return s.repo.Delete(userID, listID)
+func (s *TimeslotListService) Delete(ctx context.Context, userID, listID int) error {
+	ctx, span := tracer.Start(ctx, "TimeslotListService.Delete")
+	defer span.End()
+
+	return s.repo.Delete(ctx, userID, listID)
 }
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"main.go/internal/entity"
+)
+
+type UsersRepository interface {
+	GetUsers(ctx context.Context) ([]entity.UserInfo, error)
+	SetDisabled(ctx context.Context, userID int, disabled bool) error
+	DeleteUser(ctx context.Context, userID int) error
+	SetPassword(ctx context.Context, userID int, passwordHash string) error
+	SetColor(ctx context.Context, userID int, color string) error
+	TransferList(ctx context.Context, listID, userID int) error
+	GetStats(ctx context.Context, now time.Time) (entity.StudioStats, error)
+}
+
+// UsersService is the studio administration the admin CLI exposes. It is
+// not reachable over the API.
+type UsersService struct {
+	repo UsersRepository
+}
+
+func NewUsersService(repo UsersRepository) *UsersService {
+	return &UsersService{repo: repo}
+}
+
+func (s *UsersService) GetAll(ctx context.Context) ([]entity.UserInfo, error) {
+	ctx, span := tracer.Start(ctx, "UsersService.GetAll")
+	defer span.End()
+
+	return s.repo.GetUsers(ctx)
+}
+
+// SetDisabled blocks or unblocks sign-in and hides the artist from public
+// booking. Tokens issued before are refused from then on too.
+func (s *UsersService) SetDisabled(ctx context.Context, userID int, disabled bool) error {
+	ctx, span := tracer.Start(ctx, "UsersService.SetDisabled")
+	defer span.End()
+
+	return s.repo.SetDisabled(ctx, userID, disabled)
+}
+
+func (s *UsersService) Delete(ctx context.Context, userID int) error {
+	ctx, span := tracer.Start(ctx, "UsersService.Delete")
+	defer span.End()
+
+	return s.repo.DeleteUser(ctx, userID)
+}
+
+func (s *UsersService) ResetPassword(ctx context.Context, userID int, password string) error {
+	ctx, span := tracer.Start(ctx, "UsersService.ResetPassword")
+	defer span.End()
+
+	if err := entity.ValidatePassword(password); err != nil {
+		return err
+	}
+
+	return s.repo.SetPassword(ctx, userID, generatePasswordHash(password))
+}
+
+func (s *UsersService) SetColor(ctx context.Context, userID int, color string) error {
+	ctx, span := tracer.Start(ctx, "UsersService.SetColor")
+	defer span.End()
+
+	if err := entity.ValidateColor(color); err != nil {
+		return err
+	}
+
+	return s.repo.SetColor(ctx, userID, color)
+}
+
+func (s *UsersService) TransferList(ctx context.Context, listID, userID int) error {
+	ctx, span := tracer.Start(ctx, "UsersService.TransferList")
+	defer span.End()
+
+	return s.repo.TransferList(ctx, listID, userID)
+}
+
+func (s *UsersService) StudioStats(ctx context.Context) (entity.StudioStats, error) {
+	ctx, span := tracer.Start(ctx, "UsersService.StudioStats")
+	defer span.End()
+
+	return s.repo.GetStats(ctx, time.Now())
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+
+	apperrors "main.go/internal/errors"
+)
+
+var ErrVersionMismatch = apperrors.Conflict("version_mismatch", "resource was changed since it was read", nil)
+
+// versionError explains an update that changed nothing. When a version was
+// expected and the row still exists, someone else updated it first.
+func versionError(err error, version int, get func() error) error {
+	if !errors.Is(err, sql.ErrNoRows) || version == 0 {
+		return err
+	}
+
+	if getErr := get(); getErr != nil {
+		return getErr
+	}
+
+	return ErrVersionMismatch
+}
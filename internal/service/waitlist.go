@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"main.go/internal/entity"
+	apperrors "main.go/internal/errors"
+	"main.go/internal/health"
+	"main.go/internal/logging"
+)
+
+var ErrOfferUnavailable = apperrors.Conflict("offer_unavailable", "offer is no longer available", nil)
+
+type WaitlistRepository interface {
+	CreateEntry(ctx context.Context, entry entity.WaitlistEntry) (int, error)
+	GetEntries(ctx context.Context, artistID int) ([]entity.WaitlistEntry, error)
+	DeleteEntry(ctx context.Context, entryID int) error
+	CreateOffer(ctx context.Context, slot entity.FreedSlot, hold time.Duration) (entity.WaitlistOffer, error)
+	GetOffers(ctx context.Context, artistID int) ([]entity.WaitlistOffer, error)
+	AcceptOffer(ctx context.Context, offerID int) (int, error)
+	DeclineOffer(ctx context.Context, offerID int) (entity.WaitlistOffer, error)
+	ExpireOffers(ctx context.Context) ([]entity.WaitlistOffer, error)
+}
+
+type WaitlistService struct {
+	repo     WaitlistRepository
+	hold     time.Duration
+	recorder Recorder
+}
+
+func NewWaitlistService(repo WaitlistRepository, hold time.Duration, recorder Recorder) *WaitlistService {
+	return &WaitlistService{repo: repo, hold: hold, recorder: recorder}
+}
+
+func (s *WaitlistService) CreateEntry(ctx context.Context, entry entity.WaitlistEntry) (int, error) {
+	ctx, span := tracer.Start(ctx, "WaitlistService.CreateEntry")
+	defer span.End()
+
+	if err := entry.Validate(); err != nil {
+		return 0, apperrors.Validation("invalid_waitlist_entry", err.Error(), err)
+	}
+
+	return s.repo.CreateEntry(ctx, entry)
+}
+
+func (s *WaitlistService) GetEntries(ctx context.Context, artistID int) ([]entity.WaitlistEntry, error) {
+	ctx, span := tracer.Start(ctx, "WaitlistService.GetEntries")
+	defer span.End()
+
+	return s.repo.GetEntries(ctx, artistID)
+}
+
+func (s *WaitlistService) DeleteEntry(ctx context.Context, entryID int) error {
+	ctx, span := tracer.Start(ctx, "WaitlistService.DeleteEntry")
+	defer span.End()
+
+	return s.repo.DeleteEntry(ctx, entryID)
+}
+
+func (s *WaitlistService) GetOffers(ctx context.Context, artistID int) ([]entity.WaitlistOffer, error) {
+	ctx, span := tracer.Start(ctx, "WaitlistService.GetOffers")
+	defer span.End()
+
+	return s.repo.GetOffers(ctx, artistID)
+}
+
+// OfferSlot offers a freed slot to the first matching waitlist entry. Slots
+// that are already over are ignored, those under way are offered from now on,
+// and having nobody to offer the slot to is not an error.
+func (s *WaitlistService) OfferSlot(ctx context.Context, slot entity.FreedSlot) error {
+	ctx, span := tracer.Start(ctx, "WaitlistService.OfferSlot")
+	defer span.End()
+
+	now := time.Now()
+	if !slot.End.After(now) {
+		return nil
+	}
+
+	if slot.Start.Before(now) {
+		slot.NotBefore = now
+	}
+
+	offer, err := s.repo.CreateOffer(ctx, slot, s.hold)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Infof("waitlist entry %d offered slot %s, held until %s",
+		offer.EntryID, offer.Start.Format(time.RFC3339), offer.ExpiresAt.Format(time.RFC3339))
+
+	return nil
+}
+
+func (s *WaitlistService) AcceptOffer(ctx context.Context, offerID int) (int, error) {
+	ctx, span := tracer.Start(ctx, "WaitlistService.AcceptOffer")
+	defer span.End()
+
+	itemID, err := s.repo.AcceptOffer(ctx, offerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrOfferUnavailable
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	s.recorder.BookingCreated(BookingSourceWaitlist)
+
+	return itemID, nil
+}
+
+// DeclineOffer releases the slot held by the offer and passes it on to the
+// next matching entry.
+func (s *WaitlistService) DeclineOffer(ctx context.Context, offerID int) error {
+	ctx, span := tracer.Start(ctx, "WaitlistService.DeclineOffer")
+	defer span.End()
+
+	offer, err := s.repo.DeclineOffer(ctx, offerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrOfferUnavailable
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return s.OfferSlot(ctx, offer.Slot())
+}
+
+// ExpireOffers closes offers whose hold ran out and passes their slots on.
+// The offers are already closed when their slots are passed on, so a slot
+// that can't be offered is logged and the rest still go out.
+func (s *WaitlistService) ExpireOffers(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "WaitlistService.ExpireOffers")
+	defer span.End()
+
+	offers, err := s.repo.ExpireOffers(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, offer := range offers {
+		if err = s.OfferSlot(ctx, offer.Slot()); err != nil {
+			logging.FromContext(ctx).Errorf("error offering the slot of expired waitlist offer %d: %s", offer.ID, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Run expires offers every interval until ctx is cancelled.
+func (s *WaitlistService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.ExpireOffers(ctx); err != nil {
+				logging.FromContext(ctx).Errorf("error expiring waitlist offers: %s", err.Error())
+			}
+
+			health.Beat(ctx)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"main.go/internal/tracing"
+)
+
+func TestMiddleware(t *testing.T) {
+	// Init deps
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	var handlerSpan trace.SpanContext
+
+	// Test server
+	router := gin.New()
+	router.Use(tracing.Middleware)
+	router.GET("/api/items/:id", func(c *gin.Context) {
+		handlerSpan = trace.SpanContextFromContext(c.Request.Context())
+		c.Status(http.StatusInternalServerError)
+	})
+
+	// Make request
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/items/1", nil))
+
+	// Assert
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.Equal(t, "GET /api/items/:id", spans[0].Name())
+	require.Equal(t, trace.SpanKindServer, spans[0].SpanKind())
+	require.Equal(t, codes.Error, spans[0].Status().Code)
+	require.Equal(t, spans[0].SpanContext().SpanID(), handlerSpan.SpanID())
+}
+
+func TestSetup_otlp(t *testing.T) {
+	// Test server
+	var (
+		path        string
+		contentType string
+		body        []byte
+	)
+
+	collector := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		contentType = r.Header.Get("Content-Type")
+
+		raw, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		body = raw
+	}))
+	defer collector.Close()
+
+	shutdown, err := tracing.Setup(tracing.Config{
+		Exporter:    tracing.ExporterOTLP,
+		Endpoint:    collector.URL,
+		ServiceName: "timeslot-app",
+		SampleRatio: 1,
+	})
+	require.NoError(t, err)
+
+	// Make spans
+	_, span := otel.Tracer("test").Start(context.Background(), "SELECT timeslots_items")
+	span.End()
+
+	require.NoError(t, shutdown(context.Background()))
+
+	// Assert
+	require.Equal(t, "/v1/traces", path)
+	require.Equal(t, "application/x-protobuf", contentType)
+	require.True(t, bytes.Contains(body, []byte("SELECT timeslots_items")))
+	require.True(t, bytes.Contains(body, []byte("timeslot-app")))
+}
+
+func TestSetup_invalidEndpoint(t *testing.T) {
+	_, err := tracing.Setup(tracing.Config{
+		Exporter:    tracing.ExporterOTLP,
+		Endpoint:    "localhost:4318",
+		ServiceName: "timeslot-app",
+		SampleRatio: 1,
+	})
+	require.Error(t, err)
+}
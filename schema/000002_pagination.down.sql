@@ -0,0 +1 @@
+drop index timeslots_items_beginning_id_idx;
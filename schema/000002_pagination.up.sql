@@ -0,0 +1 @@
+create index timeslots_items_beginning_id_idx on timeslots_items (beginning, id);
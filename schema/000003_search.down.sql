@@ -0,0 +1,7 @@
+drop index timeslots_lists_search_idx;
+
+alter table timeslots_lists drop column search;
+
+drop index timeslots_items_search_idx;
+
+alter table timeslots_items drop column search;
@@ -0,0 +1,15 @@
+alter table timeslots_items
+    add column search tsvector generated always as (
+        setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+    ) stored;
+
+create index timeslots_items_search_idx on timeslots_items using gin (search);
+
+alter table timeslots_lists
+    add column search tsvector generated always as (
+        setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+    ) stored;
+
+create index timeslots_lists_search_idx on timeslots_lists using gin (search);
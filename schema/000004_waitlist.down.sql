@@ -0,0 +1,5 @@
+drop table waitlist_offers;
+
+drop table waitlist_entries;
+
+alter table timeslots_items drop column cancelled;
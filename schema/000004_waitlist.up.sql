@@ -0,0 +1,33 @@
+alter table timeslots_items
+    add column cancelled boolean not null default false;
+
+create table waitlist_entries
+(
+    id               serial       not null unique,
+    client_name      varchar(255) not null,
+    client_contact   varchar(255) not null,
+    artist_id        int          references users (id) on delete cascade not null,
+    window_start     timestamp    not null,
+    window_end       timestamp    not null,
+    duration_minutes int          not null check (duration_minutes > 0),
+    status           varchar(16)  not null default 'waiting',
+    created_at       timestamp    not null default now()
+);
+
+create index waitlist_entries_artist_status_idx on waitlist_entries (artist_id, status, created_at);
+
+create table waitlist_offers
+(
+    id         serial      not null unique,
+    entry_id   int         references waitlist_entries (id) on delete cascade not null,
+    list_id    int         references timeslots_lists (id) on delete cascade not null,
+    slot_start timestamp   not null,
+    slot_end   timestamp   not null,
+    beginning  timestamp   not null,
+    finish     timestamp   not null,
+    status     varchar(16) not null default 'pending',
+    expires_at timestamp   not null,
+    created_at timestamp   not null default now()
+);
+
+create index waitlist_offers_status_expires_idx on waitlist_offers (status, expires_at);
@@ -0,0 +1 @@
+drop table booking_requests;
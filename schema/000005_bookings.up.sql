@@ -0,0 +1,19 @@
+create table booking_requests
+(
+    id           serial       not null unique,
+    artist_id    int          references users (id) on delete cascade not null,
+    client_name  varchar(255) not null,
+    client_email varchar(255) not null,
+    client_phone varchar(64)  not null default '',
+    reference    varchar(255) not null default '',
+    beginning    timestamp    not null,
+    finish       timestamp    not null,
+    status       varchar(16)  not null default 'requested',
+    item_id      int          references timeslots_items (id) on delete set null,
+    client_ip    varchar(64)  not null default '',
+    created_at   timestamp    not null default now()
+);
+
+create index booking_requests_status_idx on booking_requests (status, created_at);
+
+create index booking_requests_client_ip_idx on booking_requests (client_ip, created_at);
@@ -0,0 +1,9 @@
+create unlogged table rate_limit_buckets
+(
+    key        varchar(255)     not null unique,
+    tokens     double precision not null,
+    allowed    boolean          not null,
+    updated_at timestamptz      not null
+);
+
+create index rate_limit_buckets_updated_at_idx on rate_limit_buckets (updated_at);
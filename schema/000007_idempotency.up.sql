@@ -0,0 +1,12 @@
+create table idempotency_keys
+(
+    user_id      int          references users (id) on delete cascade not null,
+    key          varchar(255) not null,
+    request_hash char(64)     not null,
+    status_code  int          not null default 0,
+    response     bytea        not null default '',
+    created_at   timestamp    not null default now(),
+    primary key (user_id, key)
+);
+
+create index idempotency_keys_created_at_idx on idempotency_keys (created_at);
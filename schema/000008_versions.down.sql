@@ -0,0 +1,9 @@
+drop trigger timeslots_items_bump_version on timeslots_items;
+
+drop trigger timeslots_lists_bump_version on timeslots_lists;
+
+drop function bump_version();
+
+alter table timeslots_items drop column version;
+
+alter table timeslots_lists drop column version;
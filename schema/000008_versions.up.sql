@@ -0,0 +1,25 @@
+alter table timeslots_lists
+    add column version int not null default 1;
+
+alter table timeslots_items
+    add column version int not null default 1;
+
+create function bump_version() returns trigger as
+$$
+begin
+    new.version := old.version + 1;
+    return new;
+end;
+$$ language plpgsql;
+
+create trigger timeslots_lists_bump_version
+    before update
+    on timeslots_lists
+    for each row
+execute function bump_version();
+
+create trigger timeslots_items_bump_version
+    before update
+    on timeslots_items
+    for each row
+execute function bump_version();
@@ -0,0 +1,2 @@
+alter table users
+    add column disabled boolean not null default false;
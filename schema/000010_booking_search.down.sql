@@ -0,0 +1,3 @@
+drop index booking_requests_search_idx;
+
+alter table booking_requests drop column search;
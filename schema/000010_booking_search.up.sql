@@ -0,0 +1,8 @@
+-- Some databases got this column from an earlier 000005, hence the if not exists.
+alter table booking_requests
+    add column if not exists search tsvector generated always as (
+        setweight(to_tsvector('simple', client_name || ' ' || client_email || ' ' || client_phone), 'A') ||
+        setweight(to_tsvector('simple', reference), 'B')
+    ) stored;
+
+create index if not exists booking_requests_search_idx on booking_requests using gin (search);
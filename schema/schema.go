@@ -0,0 +1,29 @@
+// Package schema embeds the database migrations into the binary.
+package schema
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// Migrations holds the Postgres NNNNNN_name.up.sql and NNNNNN_name.down.sql
+// pairs.
+//
+//go:embed *.sql
+var Migrations embed.FS
+
+//go:embed sqlite/*.sql
+var sqliteFiles embed.FS
+
+// SQLiteMigrations holds the SQLite pairs. SQLite starts from the current
+// Postgres schema rather than replaying its history.
+var SQLiteMigrations = mustSub(sqliteFiles, "sqlite") //nolint:gochecknoglobals // embedded files
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+
+	return sub
+}
@@ -0,0 +1,10 @@
+drop table idempotency_keys;
+drop table rate_limit_buckets;
+drop table booking_requests;
+drop table waitlist_offers;
+drop table waitlist_entries;
+drop table lists_items;
+drop table timeslots_items;
+drop table users_lists;
+drop table timeslots_lists;
+drop table users;
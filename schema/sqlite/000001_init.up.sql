@@ -0,0 +1,160 @@
+-- Timestamps are stored as text in UTC so that they compare and sort like
+-- the Postgres timestamps do; the repositories write them, the defaults only
+-- cover rows inserted by hand.
+
+create table users
+(
+    id            integer primary key autoincrement,
+    name          varchar(255) not null,
+    color         varchar(6)   not null unique,
+    username      varchar(255) not null unique,
+    password_hash varchar(255) not null,
+    disabled      boolean      not null default false
+);
+
+create table timeslots_lists
+(
+    id          integer primary key autoincrement,
+    title       varchar(255) not null,
+    description varchar(255),
+    version     int          not null default 1
+);
+
+create table users_lists
+(
+    id      integer primary key autoincrement,
+    user_id int not null references users (id) on delete cascade,
+    list_id int not null references timeslots_lists (id) on delete cascade
+);
+
+create index users_lists_user_id_idx on users_lists (user_id);
+
+create index users_lists_list_id_idx on users_lists (list_id);
+
+create table timeslots_items
+(
+    id          integer primary key autoincrement,
+    title       varchar(255) not null,
+    description varchar(255),
+    beginning   timestamp    not null,
+    finish      timestamp    not null,
+    done        boolean      not null default false,
+    cancelled   boolean      not null default false,
+    version     int          not null default 1
+);
+
+create index timeslots_items_beginning_id_idx on timeslots_items (beginning, id);
+
+create table lists_items
+(
+    id      integer primary key autoincrement,
+    item_id int not null references timeslots_items (id) on delete cascade,
+    list_id int not null references timeslots_lists (id) on delete cascade
+);
+
+create index lists_items_item_id_idx on lists_items (item_id);
+
+create index lists_items_list_id_idx on lists_items (list_id);
+
+-- lists and items don't cascade to the rows they are joined with, as
+-- lists_items and users_lists point the other way; these triggers do what
+-- the Postgres DELETE ... USING queries get from the join.
+create trigger timeslots_lists_delete_items
+    before delete
+    on timeslots_lists
+    for each row
+begin
+    delete from timeslots_items
+    where id in (select item_id from lists_items where list_id = old.id);
+end;
+
+create trigger timeslots_lists_bump_version
+    after update
+    on timeslots_lists
+    for each row
+    when new.version = old.version
+begin
+    update timeslots_lists set version = old.version + 1 where id = new.id;
+end;
+
+create trigger timeslots_items_bump_version
+    after update
+    on timeslots_items
+    for each row
+    when new.version = old.version
+begin
+    update timeslots_items set version = old.version + 1 where id = new.id;
+end;
+
+create table waitlist_entries
+(
+    id               integer primary key autoincrement,
+    client_name      varchar(255) not null,
+    client_contact   varchar(255) not null,
+    artist_id        int          not null references users (id) on delete cascade,
+    window_start     timestamp    not null,
+    window_end       timestamp    not null,
+    duration_minutes int          not null check (duration_minutes > 0),
+    status           varchar(16)  not null default 'waiting',
+    created_at       timestamp    not null default (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+create index waitlist_entries_artist_status_idx on waitlist_entries (artist_id, status, created_at);
+
+create table waitlist_offers
+(
+    id         integer primary key autoincrement,
+    entry_id   int         not null references waitlist_entries (id) on delete cascade,
+    list_id    int         not null references timeslots_lists (id) on delete cascade,
+    slot_start timestamp   not null,
+    slot_end   timestamp   not null,
+    beginning  timestamp   not null,
+    finish     timestamp   not null,
+    status     varchar(16) not null default 'pending',
+    expires_at timestamp   not null,
+    created_at timestamp   not null default (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+create index waitlist_offers_status_expires_idx on waitlist_offers (status, expires_at);
+
+create table booking_requests
+(
+    id           integer primary key autoincrement,
+    artist_id    int          not null references users (id) on delete cascade,
+    client_name  varchar(255) not null,
+    client_email varchar(255) not null,
+    client_phone varchar(64)  not null default '',
+    reference    varchar(255) not null default '',
+    beginning    timestamp    not null,
+    finish       timestamp    not null,
+    status       varchar(16)  not null default 'requested',
+    item_id      int          references timeslots_items (id) on delete set null,
+    client_ip    varchar(64)  not null default '',
+    created_at   timestamp    not null default (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+create index booking_requests_status_idx on booking_requests (status, created_at);
+
+create index booking_requests_client_ip_idx on booking_requests (client_ip, created_at);
+
+create table rate_limit_buckets
+(
+    key        varchar(255) not null primary key,
+    tokens     real         not null,
+    updated_at timestamp    not null
+);
+
+create index rate_limit_buckets_updated_at_idx on rate_limit_buckets (updated_at);
+
+create table idempotency_keys
+(
+    user_id      int          not null references users (id) on delete cascade,
+    key          varchar(255) not null,
+    request_hash char(64)     not null,
+    status_code  int          not null default 0,
+    response     blob         not null default x'',
+    created_at   timestamp    not null default (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    primary key (user_id, key)
+);
+
+create index idempotency_keys_created_at_idx on idempotency_keys (created_at);